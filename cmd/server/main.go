@@ -1,12 +1,25 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"log"
+	"os"
 
 	"github.com/MuhibNayem/community-helper-app/internal/app"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		if err := runReport(os.Args[2:], os.Stdout); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(2)
+			}
+			log.Fatalf("report: %v", err)
+		}
+		return
+	}
+
 	application, err := app.New()
 	if err != nil {
 		log.Fatalf("initialize app: %v", err)
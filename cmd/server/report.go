@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/config"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/reports"
+)
+
+const reportUsage = `usage: server report <kind> [flags]
+
+Downloads a financial report as CSV from a running server's admin API.
+Kinds: transactions, fees, payouts, refunds, disputes, reconciliation.
+Reconciliation compares the ledger with the gateway settlement file given
+by -settlement.
+
+Flags:
+`
+
+// runReport implements the report subcommand. The store lives in the
+// server's memory, so reports are fetched over the admin API with an
+// admin access token.
+func runReport(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), reportUsage)
+		flags.PrintDefaults()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	apiURL := flags.String("api", cfg.PublicBaseURL, "base URL of the running server")
+	token := flags.String("token", os.Getenv("ADMIN_TOKEN"), "admin access token (default $ADMIN_TOKEN)")
+	from := flags.String("from", yesterday.Format("2006-01-02"), "first day of the report, UTC")
+	to := flags.String("to", "", "day after the last day of the report, UTC (default the day after -from)")
+	settlement := flags.String("settlement", "", "gateway settlement CSV to reconcile against")
+	output := flags.String("o", "", "write the CSV to this file instead of stdout")
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	kind := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	start, err := time.Parse("2006-01-02", *from)
+	if err != nil {
+		return fmt.Errorf("invalid -from %q", *from)
+	}
+	if *to == "" {
+		*to = start.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	if *token == "" {
+		return fmt.Errorf("an admin access token is required: set -token or ADMIN_TOKEN")
+	}
+
+	query := url.Values{"from": {*from}, "to": {*to}}
+	endpoint := strings.TrimSuffix(*apiURL, "/") + "/v1/admin/reports/" + url.PathEscape(kind) + "?" + query.Encode()
+
+	var req *http.Request
+	if kind == reports.KindReconciliation {
+		if *settlement == "" {
+			return fmt.Errorf("reconciliation requires -settlement")
+		}
+		file, err := os.Open(*settlement)
+		if err != nil {
+			return fmt.Errorf("open settlement file: %w", err)
+		}
+		defer file.Close()
+		req, err = http.NewRequest(http.MethodPost, endpoint, file)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/csv")
+	} else {
+		if req, err = http.NewRequest(http.MethodGet, endpoint, nil); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("fetch report: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	out := stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type AttachmentsHandler struct {
+	attachments services.AttachmentService
+}
+
+func NewAttachmentsHandler(attachments services.AttachmentService) *AttachmentsHandler {
+	return &AttachmentsHandler{attachments: attachments}
+}
+
+func (h *AttachmentsHandler) IssueUpload(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	ticket, err := h.attachments.IssueAttachmentUpload(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, ticket)
+}
+
+func (h *AttachmentsHandler) GetAttachment(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	thumbnail := c.Query("variant") == "thumbnail"
+
+	content, err := h.attachments.GetAttachment(c.Request.Context(), user.ID, key, thumbnail)
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, content.ContentType, content.Data)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type KYCHandler struct {
+	kyc services.KYCReviewService
+}
+
+func NewKYCHandler(kyc services.KYCReviewService) *KYCHandler {
+	return &KYCHandler{kyc: kyc}
+}
+
+func (h *KYCHandler) ListDocuments(c *gin.Context) {
+	docs, err := h.kyc.ListKYCDocuments(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, docs)
+}
+
+func (h *KYCHandler) ReviewDocument(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.KYCReviewInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	documentID := c.Param("documentId")
+	doc, err := h.kyc.ReviewKYC(c.Request.Context(), user.ID, documentID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, doc)
+}
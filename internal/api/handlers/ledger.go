@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type LedgerHandler struct {
+	ledger services.LedgerService
+}
+
+func NewLedgerHandler(ledger services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledger: ledger}
+}
+
+func (h *LedgerHandler) Summary(c *gin.Context) {
+	summary, err := h.ledger.LedgerSummary(c.Request.Context())
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, summary)
+}
+
+func (h *LedgerHandler) ListTransactions(c *gin.Context) {
+	transactions, err := h.ledger.LedgerTransactions(c.Request.Context(), c.Query("reference"))
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, transactions)
+}
@@ -24,10 +24,15 @@ func (h *MatchesHandler) ListInvitations(c *gin.Context) {
 		return
 	}
 
-	status := c.Query("status")
-	results, err := h.matches.ListInvitations(c.Request.Context(), user.ID, status)
+	var filter models.InvitationListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.matches.ListInvitations(c.Request.Context(), user.ID, filter)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, err.Error())
+		writeServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -44,7 +49,7 @@ func (h *MatchesHandler) AcceptInvitation(c *gin.Context) {
 	matchID := c.Param("matchId")
 	match, err := h.matches.Accept(c.Request.Context(), user.ID, matchID)
 	if err != nil {
-		writeError(c, http.StatusBadRequest, err.Error())
+		writeServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -67,7 +72,7 @@ func (h *MatchesHandler) DeclineInvitation(c *gin.Context) {
 	matchID := c.Param("matchId")
 	match, err := h.matches.Decline(c.Request.Context(), user.ID, matchID, payload)
 	if err != nil {
-		writeError(c, http.StatusBadRequest, err.Error())
+		writeServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -90,9 +95,55 @@ func (h *MatchesHandler) UpdateStatus(c *gin.Context) {
 	matchID := c.Param("matchId")
 	match, err := h.matches.UpdateStatus(c.Request.Context(), user.ID, matchID, payload)
 	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, match)
+}
+
+func (h *MatchesHandler) Withdraw(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.WithdrawMatchInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
 		writeError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	matchID := c.Param("matchId")
+	match, err := h.matches.Withdraw(c.Request.Context(), user.ID, matchID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
 	writeJSON(c, http.StatusOK, match)
 }
+
+func (h *MatchesHandler) CancelMatch(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.HelperCancelInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matchID := c.Param("matchId")
+	request, err := h.matches.CancelMatch(c.Request.Context(), user.ID, matchID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, request)
+}
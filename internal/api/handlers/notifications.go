@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type NotificationsHandler struct {
+	notifications services.NotificationService
+}
+
+func NewNotificationsHandler(notifications services.NotificationService) *NotificationsHandler {
+	return &NotificationsHandler{notifications: notifications}
+}
+
+func (h *NotificationsHandler) ListNotifications(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	results, err := h.notifications.ListNotifications(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, results)
+}
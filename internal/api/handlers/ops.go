@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type OpsHandler struct {
+	ops services.OpsService
+}
+
+func NewOpsHandler(ops services.OpsService) *OpsHandler {
+	return &OpsHandler{ops: ops}
+}
+
+func (h *OpsHandler) SLAMetrics(c *gin.Context) {
+	metrics, err := h.ops.SLAMetrics(c.Request.Context())
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, metrics)
+}
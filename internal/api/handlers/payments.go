@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type PaymentsHandler struct {
+	payments services.PaymentService
+}
+
+func NewPaymentsHandler(payments services.PaymentService) *PaymentsHandler {
+	return &PaymentsHandler{payments: payments}
+}
+
+func (h *PaymentsHandler) GetRequestPayment(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	payment, err := h.payments.GetRequestPayment(c.Request.Context(), user.ID, c.Param("requestId"))
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, payment)
+}
+
+func (h *PaymentsHandler) ConfirmCompletion(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.CompleteSessionInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payment, err := h.payments.ConfirmCompletion(c.Request.Context(), user.ID, c.Param("matchId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, payment)
+}
+
+func (h *PaymentsHandler) AttachMethod(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.AttachPaymentMethodInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	method, err := h.payments.AttachPaymentMethod(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, method)
+}
+
+func (h *PaymentsHandler) ListMethods(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	methods, err := h.payments.ListPaymentMethods(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, methods)
+}
+
+func (h *PaymentsHandler) SetDefaultMethod(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	method, err := h.payments.SetDefaultPaymentMethod(c.Request.Context(), user.ID, c.Param("methodId"))
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, method)
+}
+
+func (h *PaymentsHandler) RemoveMethod(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	if err := h.payments.RemovePaymentMethod(c.Request.Context(), user.ID, c.Param("methodId")); err != nil {
+		writeServiceError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
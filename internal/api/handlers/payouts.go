@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type PayoutsHandler struct {
+	payouts services.PayoutService
+}
+
+func NewPayoutsHandler(payouts services.PayoutService) *PayoutsHandler {
+	return &PayoutsHandler{payouts: payouts}
+}
+
+func (h *PayoutsHandler) GetDestination(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	dest, err := h.payouts.GetPayoutDestination(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dest)
+}
+
+func (h *PayoutsHandler) SetDestination(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.PayoutDestinationInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dest, err := h.payouts.SetPayoutDestination(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dest)
+}
+
+func (h *PayoutsHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	payouts, err := h.payouts.ListPayouts(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, payouts)
+}
+
+func (h *PayoutsHandler) Get(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	payout, err := h.payouts.GetPayout(c.Request.Context(), user.ID, c.Param("payoutId"))
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, payout)
+}
+
+func (h *PayoutsHandler) Earnings(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	earnings, err := h.payouts.Earnings(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, earnings)
+}
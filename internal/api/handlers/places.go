@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type PlacesHandler struct {
+	places services.PlaceService
+}
+
+func NewPlacesHandler(places services.PlaceService) *PlacesHandler {
+	return &PlacesHandler{places: places}
+}
+
+func (h *PlacesHandler) ListPlaces(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	places, err := h.places.ListPlaces(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, places)
+}
+
+func (h *PlacesHandler) CreatePlace(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.SavedPlaceInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	place, err := h.places.CreatePlace(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, place)
+}
+
+func (h *PlacesHandler) UpdatePlace(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.SavedPlaceInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	place, err := h.places.UpdatePlace(c.Request.Context(), user.ID, c.Param("placeId"), payload)
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, place)
+}
+
+func (h *PlacesHandler) DeletePlace(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	if err := h.places.DeletePlace(c.Request.Context(), user.ID, c.Param("placeId")); err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type PricingHandler struct {
+	pricing services.PricingService
+}
+
+func NewPricingHandler(pricing services.PricingService) *PricingHandler {
+	return &PricingHandler{pricing: pricing}
+}
+
+func (h *PricingHandler) Estimate(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.CreateHelpRequestInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := h.pricing.EstimatePrice(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, quote)
+}
+
+func (h *PricingHandler) CreateQuote(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.CreateHelpRequestInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := h.pricing.CreateQuote(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, quote)
+}
+
+func (h *PricingHandler) ListPriceBooks(c *gin.Context) {
+	books, err := h.pricing.ListPriceBooks(c.Request.Context())
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, books)
+}
+
+func (h *PricingHandler) PublishPriceBook(c *gin.Context) {
+	var payload models.PriceBook
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	book, err := h.pricing.PublishPriceBook(c.Request.Context(), payload)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, book)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/reports"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// maxSettlementBytes bounds uploaded settlement files.
+const maxSettlementBytes = 10 << 20
+
+type ReportsHandler struct {
+	reports services.ReportService
+}
+
+func NewReportsHandler(reports services.ReportService) *ReportsHandler {
+	return &ReportsHandler{reports: reports}
+}
+
+// Export serves a financial report as a CSV download.
+func (h *ReportsHandler) Export(c *gin.Context) {
+	var filter models.ReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.reports.FinancialReport(c.Request.Context(), c.Param("kind"), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, reports.ErrUnknownReport) {
+			status = http.StatusNotFound
+		}
+		writeError(c, status, err.Error())
+		return
+	}
+
+	writeCSV(c, filter, report)
+}
+
+// Reconcile compares the ledger with the settlement CSV sent as the
+// request body and serves the differences as a CSV download.
+func (h *ReportsHandler) Reconcile(c *gin.Context) {
+	var filter models.ReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSettlementBytes+1))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "read settlement file")
+		return
+	}
+	if len(body) > maxSettlementBytes {
+		writeServiceError(c, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: settlement file exceeds %d bytes", services.ErrPayloadTooLarge, maxSettlementBytes))
+		return
+	}
+	settlements, err := reports.ParseSettlements(bytes.NewReader(body))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.reports.ReconciliationReport(c.Request.Context(), filter, settlements)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeCSV(c, filter, report)
+}
+
+func writeCSV(c *gin.Context, filter models.ReportFilter, report *models.Report) {
+	var buf bytes.Buffer
+	if err := reports.WriteCSV(&buf, *report); err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.csv", report.Name, filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
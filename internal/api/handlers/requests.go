@@ -32,7 +32,7 @@ func (h *RequestsHandler) CreateRequest(c *gin.Context) {
 
 	request, err := h.requests.Create(c.Request.Context(), user.ID, payload)
 	if err != nil {
-		writeError(c, http.StatusBadRequest, err.Error())
+		writeServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -54,7 +54,7 @@ func (h *RequestsHandler) ListRequests(c *gin.Context) {
 
 	requests, err := h.requests.List(c.Request.Context(), user.ID, filter)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, err.Error())
+		writeServiceError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -78,6 +78,71 @@ func (h *RequestsHandler) GetRequest(c *gin.Context) {
 	writeJSON(c, http.StatusOK, request)
 }
 
+func (h *RequestsHandler) UpdateRequest(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.UpdateHelpRequestInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requestID := c.Param("requestId")
+	request, err := h.requests.Update(c.Request.Context(), user.ID, requestID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, request)
+}
+
+func (h *RequestsHandler) SubmitRequest(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	requestID := c.Param("requestId")
+	request, err := h.requests.Submit(c.Request.Context(), user.ID, requestID)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, request)
+}
+
+func (h *RequestsHandler) RepeatRequest(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.RepeatRequestInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			writeError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	requestID := c.Param("requestId")
+	request, err := h.requests.Repeat(c.Request.Context(), user.ID, requestID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, request)
+}
+
 func (h *RequestsHandler) CancelRequest(c *gin.Context) {
 	user, ok := currentUser(c)
 	if !ok {
@@ -94,7 +159,7 @@ func (h *RequestsHandler) CancelRequest(c *gin.Context) {
 	requestID := c.Param("requestId")
 	request, err := h.requests.Cancel(c.Request.Context(), user.ID, requestID, payload)
 	if err != nil {
-		writeError(c, http.StatusBadRequest, err.Error())
+		writeServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -1,13 +1,39 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
 )
 
 type errorResponse struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// serviceErrors maps sentinel service errors to their HTTP status and API
+// error code. Errors not listed fall back to the status chosen by the caller.
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{services.ErrHelperNotEligible, http.StatusForbidden, "HELPER_NOT_ELIGIBLE"},
+	{services.ErrSignatureInvalid, http.StatusForbidden, "SIGNATURE_INVALID"},
+	{services.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+	{services.ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+	{services.ErrQuoteInvalid, http.StatusBadRequest, "QUOTE_INVALID"},
+	{services.ErrQuoteExpired, http.StatusGone, "QUOTE_EXPIRED"},
+	{services.ErrInvalidState, http.StatusConflict, "INVALID_STATE"},
+	{services.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+	{services.ErrPaymentDeclined, http.StatusPaymentRequired, "PAYMENT_DECLINED"},
+	{services.ErrLimitExceeded, http.StatusConflict, "LIMIT_EXCEEDED"},
+	{services.ErrDisputeAlreadyOpen, http.StatusConflict, "DISPUTE_ALREADY_OPEN"},
+	{services.ErrIdempotencyMismatch, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_MISMATCH"},
+	{services.ErrIdempotencyInFlight, http.StatusConflict, "IDEMPOTENCY_KEY_IN_USE"},
 }
 
 func writeJSON(c *gin.Context, status int, payload interface{}) {
@@ -18,6 +44,16 @@ func writeError(c *gin.Context, status int, msg string) {
 	writeJSON(c, status, errorResponse{Error: msg})
 }
 
+func writeServiceError(c *gin.Context, fallback int, err error) {
+	for _, mapping := range serviceErrors {
+		if errors.Is(err, mapping.err) {
+			writeJSON(c, mapping.status, errorResponse{Error: err.Error(), Code: mapping.code})
+			return
+		}
+	}
+	writeError(c, fallback, err.Error())
+}
+
 func notImplemented(c *gin.Context) {
 	writeError(c, http.StatusNotImplemented, "not implemented")
 }
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type SeriesHandler struct {
+	series services.SeriesService
+}
+
+func NewSeriesHandler(series services.SeriesService) *SeriesHandler {
+	return &SeriesHandler{series: series}
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.CreateSeriesInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.series.CreateSeries(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, series)
+}
+
+func (h *SeriesHandler) ListSeries(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	results, err := h.series.ListSeries(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, results)
+}
+
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	series, err := h.series.GetSeries(c.Request.Context(), user.ID, c.Param("seriesId"))
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, series)
+}
+
+func (h *SeriesHandler) SkipOccurrence(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.SkipOccurrenceInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.series.SkipOccurrence(c.Request.Context(), user.ID, c.Param("seriesId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, series)
+}
+
+func (h *SeriesHandler) CancelSeries(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.CancelSeriesInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.series.CancelSeries(c.Request.Context(), user.ID, c.Param("seriesId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, series)
+}
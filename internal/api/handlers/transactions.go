@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+type TransactionsHandler struct {
+	transactions services.TransactionService
+}
+
+func NewTransactionsHandler(transactions services.TransactionService) *TransactionsHandler {
+	return &TransactionsHandler{transactions: transactions}
+}
+
+func (h *TransactionsHandler) GetTransaction(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	transaction, err := h.transactions.GetTransaction(c.Request.Context(), user.ID, c.Param("transactionId"))
+	if err != nil {
+		writeError(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, transaction)
+}
+
+func (h *TransactionsHandler) OpenDispute(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.OpenDisputeInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transaction, err := h.transactions.OpenDispute(c.Request.Context(), user.ID, c.Param("transactionId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusAccepted, transaction)
+}
+
+func (h *TransactionsHandler) PostMessage(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.DisputeMessageInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dispute, err := h.transactions.PostDisputeMessage(c.Request.Context(), user.ID, c.Param("transactionId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, dispute)
+}
+
+func (h *TransactionsHandler) ResolveDispute(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.ResolveDisputeInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transaction, err := h.transactions.ResolveDispute(c.Request.Context(), user.ID, c.Param("transactionId"), payload)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, transaction)
+}
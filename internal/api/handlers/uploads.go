@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// maxUploadBodyBytes caps how much of an upload body is read before the
+// service applies the per-ticket limit.
+const maxUploadBodyBytes = 10 << 20
+
+type UploadsHandler struct {
+	uploads services.UploadService
+}
+
+func NewUploadsHandler(uploads services.UploadService) *UploadsHandler {
+	return &UploadsHandler{uploads: uploads}
+}
+
+func (h *UploadsHandler) Receive(c *gin.Context) {
+	var sig models.UploadSignature
+	if err := c.ShouldBindQuery(&sig); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxUploadBodyBytes+1))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(data) > maxUploadBodyBytes {
+		writeServiceError(c, http.StatusBadRequest, fmt.Errorf("%w: limit is %d bytes", services.ErrPayloadTooLarge, maxUploadBodyBytes))
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	ticket, err := h.uploads.ReceiveUpload(c.Request.Context(), key, sig, data)
+	if err != nil {
+		writeServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, ticket)
+}
@@ -77,6 +77,44 @@ func (h *UsersHandler) ToggleHelper(c *gin.Context) {
 	writeJSON(c, http.StatusOK, result)
 }
 
+func (h *UsersHandler) PauseHelper(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	var payload models.HelperPauseInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.users.PauseHelper(c.Request.Context(), user.ID, payload)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, result)
+}
+
+func (h *UsersHandler) ResumeHelper(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	result, err := h.users.ResumeHelper(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, result)
+}
+
 func (h *UsersHandler) UpdateSkills(c *gin.Context) {
 	user, ok := currentUser(c)
 	if !ok {
@@ -121,6 +159,22 @@ func (h *UsersHandler) ManageAvailability(c *gin.Context) {
 	writeJSON(c, http.StatusOK, result)
 }
 
+func (h *UsersHandler) IssueKYCUpload(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		writeError(c, http.StatusUnauthorized, "unauthenticated")
+		return
+	}
+
+	ticket, err := h.users.IssueKYCUpload(c.Request.Context(), user.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusCreated, ticket)
+}
+
 func (h *UsersHandler) UploadKYC(c *gin.Context) {
 	user, ok := currentUser(c)
 	if !ok {
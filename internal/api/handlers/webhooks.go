@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/twilio"
+)
+
+// maxWebhookBytes bounds webhook bodies; provider events are small.
+const maxWebhookBytes = 256 << 10
+
+// WebhooksHandler verifies provider webhooks and stores them in the inbox;
+// they are applied asynchronously by the webhook processing job.
+type WebhooksHandler struct {
+	hooks    services.WebhookService
+	payments gateway.Gateway
+	// twilioAuthToken signs Twilio requests, which are signed over the
+	// public URL they were sent to.
+	twilioAuthToken string
+	publicURL       string
+}
+
+func NewWebhooksHandler(hooks services.WebhookService, payments gateway.Gateway, twilioAuthToken, publicURL string) *WebhooksHandler {
+	return &WebhooksHandler{
+		hooks:           hooks,
+		payments:        payments,
+		twilioAuthToken: twilioAuthToken,
+		publicURL:       strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+type webhookReceipt struct {
+	Received  bool `json:"received"`
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// Stripe receives payment gateway events signed with the Stripe-Signature
+// scheme.
+func (h *WebhooksHandler) Stripe(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "read webhook body")
+		return
+	}
+
+	event, err := h.payments.VerifyWebhook(payload, c.GetHeader("Stripe-Signature"))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, duplicate, err := h.hooks.IngestWebhook(c.Request.Context(), models.WebhookInput{
+		Provider:  "STRIPE",
+		EventID:   event.ID,
+		Type:      event.Type,
+		Reference: event.Reference,
+		Payload:   payload,
+	})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, webhookReceipt{Received: true, Duplicate: duplicate})
+}
+
+// Twilio receives SMS status callbacks. The status callback URL carries the
+// notification ID the message was sent for as ?notificationId=.
+func (h *WebhooksHandler) Twilio(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "read webhook body")
+		return
+	}
+	params, err := url.ParseQuery(string(payload))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid form body")
+		return
+	}
+
+	fullURL := h.publicURL + c.Request.URL.RequestURI()
+	if err := twilio.VerifySignature(h.twilioAuthToken, fullURL, params, c.GetHeader(twilio.SignatureHeader)); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sid, status := params.Get("MessageSid"), params.Get("MessageStatus")
+	if sid == "" || status == "" {
+		writeError(c, http.StatusBadRequest, "MessageSid and MessageStatus are required")
+		return
+	}
+	eventID := c.GetHeader("I-Twilio-Idempotency-Token")
+	if eventID == "" {
+		eventID = sid + ":" + status
+	}
+
+	_, duplicate, err := h.hooks.IngestWebhook(c.Request.Context(), models.WebhookInput{
+		Provider:  "TWILIO",
+		EventID:   eventID,
+		Type:      "message." + status,
+		Reference: c.Query("notificationId"),
+		Payload:   payload,
+	})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, webhookReceipt{Received: true, Duplicate: duplicate})
+}
+
+func (h *WebhooksHandler) ListEvents(c *gin.Context) {
+	var filter models.WebhookListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		writeError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, err := h.hooks.ListWebhookEvents(c.Request.Context(), filter)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(c, http.StatusOK, events)
+}
+
+func (h *WebhooksHandler) ReplayEvent(c *gin.Context) {
+	event, err := h.hooks.ReplayWebhookEvent(c.Request.Context(), c.Param("eventId"))
+	if err != nil {
+		writeServiceError(c, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(c, http.StatusAccepted, event)
+}
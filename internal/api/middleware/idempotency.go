@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyRequestMaxBody = 1 << 20
+)
+
+// NewIdempotencyMiddleware makes mutating requests that carry an
+// Idempotency-Key safe to retry: the first response for a (user, key,
+// route) is stored and replayed to retries with the same body, while a
+// retry with a different body is rejected with 422. Server errors are not
+// stored, so those requests can be retried. It must run after the auth
+// middleware.
+func NewIdempotencyMiddleware(idempotency services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !mutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key too long"})
+			return
+		}
+
+		value, _ := c.Get(ContextUserKey)
+		user, ok := value.(*models.User)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, idempotencyRequestMaxBody+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "read request body"})
+			return
+		}
+		if len(body) > idempotencyRequestMaxBody {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.Sum256(body)
+
+		ctx := c.Request.Context()
+		scope := models.IdempotencyScope{UserID: user.ID, Key: key, Route: c.Request.Method + " " + c.Request.URL.Path}
+		record, err := idempotency.BeginIdempotent(ctx, scope, hex.EncodeToString(hash[:]))
+		switch {
+		case errors.Is(err, services.ErrIdempotencyMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "code": "IDEMPOTENCY_KEY_MISMATCH"})
+			return
+		case errors.Is(err, services.ErrIdempotencyInFlight):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "IDEMPOTENCY_KEY_IN_USE"})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		case record != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.Response.Status, record.Response.ContentType, record.Response.Body)
+			c.Abort()
+			return
+		}
+
+		// Release the key unless the response was stored, including when a
+		// handler panics and the recovery middleware above us answers.
+		completed := false
+		defer func() {
+			if !completed {
+				idempotency.AbandonIdempotent(ctx, scope)
+			}
+		}()
+
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		err = idempotency.CompleteIdempotent(ctx, scope, models.StoredResponse{
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		completed = err == nil
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// recordingWriter keeps a copy of the response body for replay.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
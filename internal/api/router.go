@@ -4,18 +4,33 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/MuhibNayem/community-helper-app/internal/api/handlers"
+	"github.com/MuhibNayem/community-helper-app/internal/api/middleware"
 	"github.com/MuhibNayem/community-helper-app/internal/config"
 )
 
 type HandlerSet struct {
-	Auth     *handlers.AuthHandler
-	Users    *handlers.UsersHandler
-	Requests *handlers.RequestsHandler
-	Matches  *handlers.MatchesHandler
-	Health   *handlers.HealthHandler
+	Auth          *handlers.AuthHandler
+	Users         *handlers.UsersHandler
+	Requests      *handlers.RequestsHandler
+	Matches       *handlers.MatchesHandler
+	KYC           *handlers.KYCHandler
+	Uploads       *handlers.UploadsHandler
+	Attachments   *handlers.AttachmentsHandler
+	Places        *handlers.PlacesHandler
+	Series        *handlers.SeriesHandler
+	Ops           *handlers.OpsHandler
+	Payments      *handlers.PaymentsHandler
+	Payouts       *handlers.PayoutsHandler
+	Transactions  *handlers.TransactionsHandler
+	Webhooks      *handlers.WebhooksHandler
+	Ledger        *handlers.LedgerHandler
+	Reports       *handlers.ReportsHandler
+	Pricing       *handlers.PricingHandler
+	Notifications *handlers.NotificationsHandler
+	Health        *handlers.HealthHandler
 }
 
-func NewRouter(cfg *config.Config, handlers HandlerSet, authMiddleware gin.HandlerFunc) *gin.Engine {
+func NewRouter(cfg *config.Config, handlers HandlerSet, authMiddleware, idempotencyMiddleware gin.HandlerFunc) *gin.Engine {
 	if cfg.Env == "production" || cfg.Env == "stage" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -38,26 +53,97 @@ func NewRouter(cfg *config.Config, handlers HandlerSet, authMiddleware gin.Handl
 		authGroup.POST("/logout", handlers.Auth.Logout)
 	}
 
+	// Uploads are authorized by the signed URL issued to the uploader, not
+	// by a session token.
+	v1.PUT("/uploads/*key", handlers.Uploads.Receive)
+
+	// Provider webhooks are authorized by their signatures.
+	v1.POST("/hooks/stripe", handlers.Webhooks.Stripe)
+	v1.POST("/hooks/twilio", handlers.Webhooks.Twilio)
+
 	protected := v1.Group("")
-	protected.Use(authMiddleware)
+	protected.Use(authMiddleware, idempotencyMiddleware)
 
 	protected.GET("/users/me", handlers.Users.GetCurrentUser)
 	protected.PATCH("/users/me", handlers.Users.UpdateProfile)
+	protected.GET("/users/me/places", handlers.Places.ListPlaces)
+	protected.POST("/users/me/places", handlers.Places.CreatePlace)
+	protected.PUT("/users/me/places/:placeId", handlers.Places.UpdatePlace)
+	protected.DELETE("/users/me/places/:placeId", handlers.Places.DeletePlace)
 	protected.POST("/helpers/me/toggle", handlers.Users.ToggleHelper)
+	protected.POST("/helpers/me/pause", handlers.Users.PauseHelper)
+	protected.DELETE("/helpers/me/pause", handlers.Users.ResumeHelper)
 	protected.PUT("/helpers/me/skills", handlers.Users.UpdateSkills)
 	protected.PUT("/helpers/me/availability", handlers.Users.ManageAvailability)
+	protected.POST("/helpers/me/kyc/uploads", handlers.Users.IssueKYCUpload)
 	protected.POST("/helpers/me/kyc", handlers.Users.UploadKYC)
+	protected.GET("/helpers/me/payout-destination", handlers.Payouts.GetDestination)
+	protected.PUT("/helpers/me/payout-destination", handlers.Payouts.SetDestination)
+	protected.GET("/helpers/me/payouts", handlers.Payouts.List)
+	protected.GET("/helpers/me/payouts/:payoutId", handlers.Payouts.Get)
+	protected.GET("/helpers/me/earnings", handlers.Payouts.Earnings)
 
 	protected.POST("/requests", handlers.Requests.CreateRequest)
 	protected.GET("/requests", handlers.Requests.ListRequests)
 	protected.GET("/requests/:requestId", handlers.Requests.GetRequest)
+	protected.PATCH("/requests/:requestId", handlers.Requests.UpdateRequest)
+	protected.POST("/requests/:requestId/submit", handlers.Requests.SubmitRequest)
+	protected.POST("/requests/:requestId/repeat", handlers.Requests.RepeatRequest)
 	protected.POST("/requests/:requestId/cancel", handlers.Requests.CancelRequest)
 	protected.POST("/requests/:requestId/rate", handlers.Requests.RateHelper)
+	protected.GET("/requests/:requestId/payment", handlers.Payments.GetRequestPayment)
+
+	protected.POST("/series", handlers.Series.CreateSeries)
+	protected.GET("/series", handlers.Series.ListSeries)
+	protected.GET("/series/:seriesId", handlers.Series.GetSeries)
+	protected.POST("/series/:seriesId/skip", handlers.Series.SkipOccurrence)
+	protected.POST("/series/:seriesId/cancel", handlers.Series.CancelSeries)
+
+	protected.POST("/pricing/estimate", handlers.Pricing.Estimate)
+	protected.POST("/quotes", handlers.Pricing.CreateQuote)
+
+	protected.POST("/attachments/uploads", handlers.Attachments.IssueUpload)
+	protected.GET("/attachments/*key", handlers.Attachments.GetAttachment)
 
 	protected.GET("/matches", handlers.Matches.ListInvitations)
 	protected.POST("/matches/:matchId/accept", handlers.Matches.AcceptInvitation)
 	protected.POST("/matches/:matchId/decline", handlers.Matches.DeclineInvitation)
 	protected.POST("/matches/:matchId/status", handlers.Matches.UpdateStatus)
+	protected.POST("/matches/:matchId/withdraw", handlers.Matches.Withdraw)
+	protected.POST("/matches/:matchId/cancel", handlers.Matches.CancelMatch)
+	protected.POST("/matches/:matchId/complete", handlers.Payments.ConfirmCompletion)
+
+	protected.POST("/payments/methods", handlers.Payments.AttachMethod)
+	protected.GET("/payments/methods", handlers.Payments.ListMethods)
+	protected.POST("/payments/methods/:methodId/default", handlers.Payments.SetDefaultMethod)
+	protected.DELETE("/payments/methods/:methodId", handlers.Payments.RemoveMethod)
+
+	protected.GET("/transactions/:transactionId", handlers.Transactions.GetTransaction)
+	protected.POST("/transactions/:transactionId/dispute", handlers.Transactions.OpenDispute)
+	protected.POST("/transactions/:transactionId/dispute/messages", handlers.Transactions.PostMessage)
+	protected.POST("/transactions/:transactionId/resolve", middleware.RequireRole("ADMIN"), handlers.Transactions.ResolveDispute)
+
+	protected.GET("/notifications", handlers.Notifications.ListNotifications)
+
+	admin := protected.Group("/admin")
+	admin.Use(middleware.RequireRole("ADMIN"))
+
+	admin.GET("/kyc", handlers.KYC.ListDocuments)
+	admin.POST("/kyc/:documentId/review", handlers.KYC.ReviewDocument)
+
+	admin.GET("/pricing/books", handlers.Pricing.ListPriceBooks)
+	admin.POST("/pricing/books", handlers.Pricing.PublishPriceBook)
+
+	admin.GET("/metrics/sla", handlers.Ops.SLAMetrics)
+
+	admin.GET("/ledger", handlers.Ledger.Summary)
+	admin.GET("/ledger/transactions", handlers.Ledger.ListTransactions)
+
+	admin.GET("/reports/:kind", handlers.Reports.Export)
+	admin.POST("/reports/reconciliation", handlers.Reports.Reconcile)
+
+	admin.GET("/webhooks", handlers.Webhooks.ListEvents)
+	admin.POST("/webhooks/:eventId/replay", handlers.Webhooks.ReplayEvent)
 
 	return engine
 }
@@ -2,9 +2,16 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,24 +23,64 @@ import (
 	"github.com/MuhibNayem/community-helper-app/internal/config"
 	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
 	"github.com/MuhibNayem/community-helper-app/internal/domain/services/memory"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/storage"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/twilio"
 )
 
+var (
+	testWebhookSecret = []byte("test-webhook-secret")
+	testTwilioToken   = "test-twilio-token"
+	testPublicURL     = "https://api.example.test"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj <<>> endobj\ntrailer <<>>\n%%EOF\n")
+
 func setupRouter(t *testing.T) (*gin.Engine, *memory.Store) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
 	cfg := &config.Config{HTTPPort: "8080", Env: "test"}
-	store := memory.NewStore()
+	local, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("local storage: %v", err)
+	}
+	encrypted, err := storage.NewEncrypted(local, bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("encrypted storage: %v", err)
+	}
+
+	store := memory.NewStore().WithObjectStorage(memory.ObjectStorage{
+		KYC:         encrypted,
+		Attachments: local,
+		Signer:      storage.NewSigner([]byte("test-signing-key")),
+	})
+	paymentGateway := gateway.NewFake(testWebhookSecret)
+	store.WithGateway(paymentGateway)
 
 	handlerSet := api.HandlerSet{
-		Auth:     handlers.NewAuthHandler(store),
-		Users:    handlers.NewUsersHandler(store),
-		Requests: handlers.NewRequestsHandler(store),
-		Matches:  handlers.NewMatchesHandler(store),
-		Health:   handlers.NewHealthHandler(cfg.Env),
+		Auth:          handlers.NewAuthHandler(store),
+		Users:         handlers.NewUsersHandler(store),
+		Requests:      handlers.NewRequestsHandler(store),
+		Matches:       handlers.NewMatchesHandler(store),
+		KYC:           handlers.NewKYCHandler(store),
+		Uploads:       handlers.NewUploadsHandler(store),
+		Attachments:   handlers.NewAttachmentsHandler(store),
+		Places:        handlers.NewPlacesHandler(store),
+		Series:        handlers.NewSeriesHandler(store),
+		Ops:           handlers.NewOpsHandler(store),
+		Payments:      handlers.NewPaymentsHandler(store),
+		Payouts:       handlers.NewPayoutsHandler(store),
+		Transactions:  handlers.NewTransactionsHandler(store),
+		Webhooks:      handlers.NewWebhooksHandler(store, paymentGateway, testTwilioToken, testPublicURL),
+		Ledger:        handlers.NewLedgerHandler(store),
+		Reports:       handlers.NewReportsHandler(store),
+		Pricing:       handlers.NewPricingHandler(store),
+		Notifications: handlers.NewNotificationsHandler(store),
+		Health:        handlers.NewHealthHandler(cfg.Env),
 	}
 
-	router := api.NewRouter(cfg, handlerSet, middleware.NewAuthMiddleware(store))
+	router := api.NewRouter(cfg, handlerSet, middleware.NewAuthMiddleware(store), middleware.NewIdempotencyMiddleware(store))
 	return router, store
 }
 
@@ -64,6 +111,32 @@ func doRequest(t *testing.T, router *gin.Engine, method, path string, body inter
 	return rr
 }
 
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func doRawRequest(t *testing.T, router *gin.Engine, method, path string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
 func decodeBody[T any](t *testing.T, rr *httptest.ResponseRecorder, dest *T) {
 	t.Helper()
 	if err := json.Unmarshal(rr.Body.Bytes(), dest); err != nil {
@@ -96,6 +169,24 @@ func authenticate(t *testing.T, router *gin.Engine, phone string) (string, model
 	return session.Token, session.User
 }
 
+func issueAndUpload(t *testing.T, router *gin.Engine, path, token string, data []byte) models.UploadTicket {
+	t.Helper()
+
+	resp := doRequest(t, router, http.MethodPost, path, nil, token)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("issue upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var ticket models.UploadTicket
+	decodeBody(t, resp, &ticket)
+
+	resp = doRawRequest(t, router, ticket.Method, ticket.UploadURL, data)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	decodeBody(t, resp, &ticket)
+	return ticket
+}
+
 func TestAuthFlow(t *testing.T) {
 	router, _ := setupRouter(t)
 
@@ -173,9 +264,10 @@ func TestUsersEndpoints(t *testing.T) {
 		t.Fatalf("manage availability status=%d body=%s", resp.Code, resp.Body.String())
 	}
 
+	ticket := issueAndUpload(t, router, "/v1/helpers/me/kyc/uploads", token, testPDF)
 	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc", gin.H{
 		"documentType": "NID",
-		"fileUrl":      "https://example.com/nid.pdf",
+		"fileKey":      ticket.Key,
 	}, token)
 	if resp.Code != http.StatusAccepted {
 		t.Fatalf("upload kyc status=%d body=%s", resp.Code, resp.Body.String())
@@ -222,6 +314,13 @@ func TestRequestLifecycle(t *testing.T) {
 		t.Fatalf("cancel request status=%d body=%s", resp.Code, resp.Body.String())
 	}
 
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests/"+requestA.ID+"/cancel", gin.H{
+		"reason": "HELPER_NOT_NEEDED",
+	}, token)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("re-cancel request status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
 	// Create request B for full lifecycle
 	reqBodyB := gin.H{
 		"type":        "PLANNED",
@@ -261,6 +360,27 @@ func TestRequestLifecycle(t *testing.T) {
 		t.Fatalf("update match status=%d body=%s", resp.Code, resp.Body.String())
 	}
 
+	resp = doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/complete", gin.H{
+		"confirmation": "FAILED",
+	}, token)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("failed confirmation without reason status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/complete", gin.H{
+		"confirmation": "SUCCESS",
+	}, token)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("confirm completion status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/requests/"+requestB.ID+"/payment", nil, token)
+	var payment models.Payment
+	decodeBody(t, resp, &payment)
+	if resp.Code != http.StatusOK || payment.Status != "RELEASED" {
+		t.Fatalf("expected released payment, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
 	resp = doRequest(t, router, http.MethodPost, "/v1/requests/"+requestB.ID+"/rate", gin.H{
 		"rating": 5,
 	}, token)
@@ -279,3 +399,656 @@ func TestRequestLifecycle(t *testing.T) {
 	}
 }
 
+func TestHelperPause(t *testing.T) {
+	router, _ := setupRouter(t)
+	token, _ := authenticate(t, router, "+8801000000004")
+
+	resp := doRequest(t, router, http.MethodDelete, "/v1/helpers/me/pause", nil, token)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("resume without pause status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/pause", gin.H{
+		"until": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	}, token)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("pause in the past status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/pause", gin.H{
+		"durationMinutes": 120,
+	}, token)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pause helper status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	var paused models.User
+	decodeBody(t, resp, &paused)
+	if paused.HelperStatus != "PAUSED" || paused.PausedUntil == nil || !paused.IsHelper {
+		t.Fatalf("unexpected paused user: %+v", paused)
+	}
+
+	resp = doRequest(t, router, http.MethodDelete, "/v1/helpers/me/pause", nil, token)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("resume helper status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	var resumed models.User
+	decodeBody(t, resp, &resumed)
+	if resumed.HelperStatus != "ACTIVE" || resumed.PausedUntil != nil {
+		t.Fatalf("unexpected resumed user: %+v", resumed)
+	}
+}
+
+func TestKYCReview(t *testing.T) {
+	router, store := setupRouter(t)
+	helperToken, helper := authenticate(t, router, "+8801000000005")
+	adminToken, admin := authenticate(t, router, "+8801000000006")
+	if err := store.GrantRole(admin.ID, "ADMIN"); err != nil {
+		t.Fatalf("grant role: %v", err)
+	}
+
+	ticket := issueAndUpload(t, router, "/v1/helpers/me/kyc/uploads", helperToken, testPDF)
+	resp := doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc", gin.H{
+		"documentType": "NID",
+		"fileKey":      ticket.Key,
+	}, helperToken)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("upload kyc status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var doc models.KYCDocument
+	decodeBody(t, resp, &doc)
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/kyc?status=PENDING", nil, helperToken)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("non-admin list kyc status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/admin/kyc/"+doc.ID+"/review", gin.H{
+		"decision": "REJECTED",
+	}, adminToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("reject without notes status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/admin/kyc/"+doc.ID+"/review", gin.H{
+		"decision": "APPROVED",
+		"notes":    "NID matches profile",
+	}, adminToken)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("approve kyc status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	var reviewed models.KYCDocument
+	decodeBody(t, resp, &reviewed)
+	if reviewed.Status != "APPROVED" || reviewed.ReviewerID != admin.ID || reviewed.ReviewedAt == nil {
+		t.Fatalf("unexpected reviewed document: %+v", reviewed)
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/admin/kyc/"+doc.ID+"/review", gin.H{"decision": "APPROVED"}, adminToken)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("review reviewed kyc status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/admin/kyc/kyc-missing/review", gin.H{"decision": "APPROVED"}, adminToken)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("review missing kyc status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/users/me", nil, helperToken)
+	var current models.User
+	decodeBody(t, resp, &current)
+	if current.ID != helper.ID || current.KYCStatus != "VERIFIED" {
+		t.Fatalf("expected verified helper, got %+v", current)
+	}
+}
+
+func TestKYCUploadValidation(t *testing.T) {
+	router, _ := setupRouter(t)
+	ownerToken, _ := authenticate(t, router, "+8801000000007")
+	otherToken, _ := authenticate(t, router, "+8801000000008")
+
+	resp := doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc/uploads", nil, ownerToken)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("issue upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var ticket models.UploadTicket
+	decodeBody(t, resp, &ticket)
+
+	resp = doRawRequest(t, router, http.MethodPut, ticket.UploadURL+"0", testPDF)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("tampered signature status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRawRequest(t, router, http.MethodPut, ticket.UploadURL, []byte("#!/bin/sh\necho not a document\n"))
+	if resp.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("script upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc", gin.H{
+		"documentType": "NID",
+		"fileKey":      ticket.Key,
+	}, ownerToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("attach before upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRawRequest(t, router, http.MethodPut, ticket.UploadURL, testPDF)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc", gin.H{
+		"documentType": "NID",
+		"fileKey":      ticket.Key,
+	}, otherToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("attach foreign key status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/helpers/me/kyc", gin.H{
+		"documentType": "NID",
+		"fileKey":      ticket.Key,
+	}, ownerToken)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("attach own key status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	var doc models.KYCDocument
+	decodeBody(t, resp, &doc)
+	if doc.FileKey != ticket.Key || doc.ContentType != "application/pdf" {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+}
+
+func TestRequestAttachments(t *testing.T) {
+	router, store := setupRouter(t)
+	seekerToken, _ := authenticate(t, router, "+8801000000009")
+	helperToken, helper := authenticate(t, router, "+8801000000010")
+	strangerToken, _ := authenticate(t, router, "+8801000000011")
+
+	resp := doRequest(t, router, http.MethodPost, "/v1/attachments/uploads", nil, seekerToken)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("issue upload status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var rejected models.UploadTicket
+	decodeBody(t, resp, &rejected)
+	resp = doRawRequest(t, router, http.MethodPut, rejected.UploadURL, testPDF)
+	if resp.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("pdf attachment status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	ticket := issueAndUpload(t, router, "/v1/attachments/uploads", seekerToken, testPNG(t, 800, 600))
+	if ticket.ContentType != "image/png" || ticket.ThumbnailKey == "" {
+		t.Fatalf("unexpected uploaded ticket: %+v", ticket)
+	}
+
+	reqBody := gin.H{
+		"type":        "URGENT",
+		"category":    "MECHANICAL",
+		"description": "Flat tyre",
+		"location":    gin.H{"lat": 23.78, "lng": 90.36, "address": "Dhaka"},
+		"attachments": []string{ticket.Key},
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests", reqBody, strangerToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("create with foreign attachment status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	reqBody["attachments"] = []string{ticket.Key, ticket.Key}
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests", reqBody, seekerToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("create with repeated attachment status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	reqBody["attachments"] = []string{ticket.Key}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests", reqBody, seekerToken)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create request status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var request models.HelpRequest
+	decodeBody(t, resp, &request)
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/attachments/"+ticket.Key+"?variant=thumbnail", nil, seekerToken)
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "image/jpeg" {
+		t.Fatalf("get thumbnail status=%d type=%s", resp.Code, resp.Header().Get("Content-Type"))
+	}
+	thumb, _, err := image.DecodeConfig(bytes.NewReader(resp.Body.Bytes()))
+	if err != nil || thumb.Width != 320 || thumb.Height != 240 {
+		t.Fatalf("unexpected thumbnail %+v err=%v", thumb, err)
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/attachments/"+ticket.Key, nil, helperToken)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("unmatched helper get status=%d", resp.Code)
+	}
+
+	var match *models.MatchSession
+	invites, _ := store.ListInvitations(context.Background(), helper.ID, models.InvitationListFilter{Status: "INVITED"})
+	for i := range invites.Data {
+		if invites.Data[i].RequestID == request.ID {
+			match = &invites.Data[i]
+		}
+	}
+	if match == nil {
+		t.Fatalf("expected helper to be invited to %s", request.ID)
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/accept", nil, helperToken)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("accept status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/attachments/"+ticket.Key, nil, helperToken)
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("matched helper get status=%d type=%s", resp.Code, resp.Header().Get("Content-Type"))
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/attachments/"+ticket.Key, nil, strangerToken)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("stranger get status=%d", resp.Code)
+	}
+}
+
+func TestSavedPlacesAndRepeat(t *testing.T) {
+	router, _ := setupRouter(t)
+	token, _ := authenticate(t, router, "+8801000000030")
+
+	resp := doRequest(t, router, http.MethodPost, "/v1/users/me/places", gin.H{
+		"label":    "Home",
+		"location": gin.H{"lat": 23.78, "lng": 90.36, "address": "Mirpur, Dhaka"},
+	}, token)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create place status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var home models.SavedPlace
+	decodeBody(t, resp, &home)
+	if !home.IsDefault {
+		t.Fatalf("expected first place to be the default")
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/users/me/places", gin.H{
+		"label":     "Office",
+		"location":  gin.H{"lat": 23.75, "lng": 90.39, "address": "Karwan Bazar, Dhaka"},
+		"isDefault": true,
+	}, token)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create place status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var office models.SavedPlace
+	decodeBody(t, resp, &office)
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/users/me/places", nil, token)
+	var places []models.SavedPlace
+	decodeBody(t, resp, &places)
+	if len(places) != 2 || places[0].IsDefault || !places[1].IsDefault {
+		t.Fatalf("expected office to become the default, got %+v", places)
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests", gin.H{
+		"type":         "URGENT",
+		"category":     "GENERAL_HELP",
+		"description":  "Help moving a desk",
+		"savedPlaceId": office.ID,
+	}, token)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create request status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var original models.HelpRequest
+	decodeBody(t, resp, &original)
+	if original.Location.Address != "Karwan Bazar, Dhaka" {
+		t.Fatalf("expected saved place location, got %+v", original.Location)
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests", gin.H{
+		"type":        "URGENT",
+		"category":    "GENERAL_HELP",
+		"description": "No location",
+	}, token)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without location, got %d", resp.Code)
+	}
+
+	resp = doRequest(t, router, http.MethodDelete, "/v1/users/me/places/"+office.ID, nil, token)
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("delete place status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = doRequest(t, router, http.MethodGet, "/v1/users/me/places", nil, token)
+	decodeBody(t, resp, &places)
+	if len(places) != 1 || places[0].ID != home.ID || !places[0].IsDefault {
+		t.Fatalf("expected home to become the default again, got %+v", places)
+	}
+
+	resp = doRequest(t, router, http.MethodPost, "/v1/requests/"+original.ID+"/repeat", nil, token)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("repeat request status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var repeated models.HelpRequest
+	decodeBody(t, resp, &repeated)
+	if repeated.ID == original.ID || repeated.Status != "SUBMITTED" || repeated.Description != original.Description || repeated.Location != original.Location {
+		t.Fatalf("unexpected repeated request: %+v", repeated)
+	}
+}
+
+func TestListRequestsCursorPagination(t *testing.T) {
+	router, _ := setupRouter(t)
+	token, _ := authenticate(t, router, "+8801000000040")
+
+	for _, category := range []string{"GENERAL_HELP", "MEDICAL_FIRST_AID", "GENERAL_HELP"} {
+		resp := doRequest(t, router, http.MethodPost, "/v1/requests", gin.H{
+			"type":        "URGENT",
+			"category":    category,
+			"description": "Need a hand",
+			"location":    gin.H{"lat": 23.78, "lng": 90.36, "address": "Dhaka"},
+		}, token)
+		if resp.Code != http.StatusCreated {
+			t.Fatalf("create request status=%d body=%s", resp.Code, resp.Body.String())
+		}
+	}
+
+	resp := doRequest(t, router, http.MethodGet, "/v1/requests?category=GENERAL_HELP&status=SUBMITTED,SCHEDULED&limit=1", nil, token)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("list status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	var first models.RequestPage
+	decodeBody(t, resp, &first)
+	if len(first.Data) != 1 || first.NextCursor == "" {
+		t.Fatalf("expected one request and a cursor, got %+v", first)
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/requests?category=GENERAL_HELP&status=SUBMITTED,SCHEDULED&limit=1&cursor="+first.NextCursor, nil, token)
+	var second models.RequestPage
+	decodeBody(t, resp, &second)
+	if len(second.Data) != 1 || second.NextCursor != "" || second.Data[0].ID == first.Data[0].ID {
+		t.Fatalf("unexpected last page: %+v", second)
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/requests?from=2000-01-01T00:00:00Z&to=2001-01-01T00:00:00Z", nil, token)
+	var empty models.RequestPage
+	decodeBody(t, resp, &empty)
+	if resp.Code != http.StatusOK || len(empty.Data) != 0 {
+		t.Fatalf("expected no requests in range, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/requests?cursor=bogus", nil, token)
+	if resp.Code != http.StatusBadRequest || !bytes.Contains(resp.Body.Bytes(), []byte("INVALID_CURSOR")) {
+		t.Fatalf("expected INVALID_CURSOR, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/matches?status=INVITED,ACCEPTED&limit=10", nil, token)
+	var invites models.InvitationPage
+	decodeBody(t, resp, &invites)
+	if resp.Code != http.StatusOK || invites.Data == nil {
+		t.Fatalf("list invitations status=%d body=%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestTransactionDispute(t *testing.T) {
+	router, store := setupRouter(t)
+	seekerToken, _ := authenticate(t, router, "+8801000000030")
+	helperToken, helper := authenticate(t, router, "+8801000000031")
+	adminToken, admin := authenticate(t, router, "+8801000000032")
+	if err := store.GrantRole(admin.ID, "ADMIN"); err != nil {
+		t.Fatalf("grant role: %v", err)
+	}
+
+	resp := doRequest(t, router, http.MethodPost, "/v1/requests", gin.H{
+		"type":        "URGENT",
+		"category":    "GENERAL_HELP",
+		"description": "Carry groceries",
+		"location":    gin.H{"lat": 23.78, "lng": 90.36, "address": "Dhaka"},
+	}, seekerToken)
+	var req models.HelpRequest
+	decodeBody(t, resp, &req)
+
+	match := store.SeedMatch(helper.ID, req.ID)
+	doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/accept", nil, helperToken)
+	doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/status", gin.H{"status": "COMPLETED"}, helperToken)
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/requests/"+req.ID+"/payment", nil, seekerToken)
+	var payment models.Payment
+	decodeBody(t, resp, &payment)
+
+	dispute := gin.H{"reason": "SERVICE_NOT_COMPLETED", "details": "Helper left early"}
+	resp = doRequest(t, router, http.MethodPost, "/v1/transactions/"+payment.ID+"/dispute", dispute, seekerToken)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("open dispute status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/transactions/"+payment.ID+"/dispute", dispute, helperToken)
+	var errBody struct {
+		Code string `json:"code"`
+	}
+	decodeBody(t, resp, &errBody)
+	if resp.Code != http.StatusConflict || errBody.Code != "DISPUTE_ALREADY_OPEN" {
+		t.Fatalf("second dispute status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resolve := gin.H{"outcome": "REFUND_FULL", "notes": "Helper no-show"}
+	resp = doRequest(t, router, http.MethodPost, "/v1/transactions/"+payment.ID+"/resolve", resolve, seekerToken)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("non-admin resolve status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/transactions/"+payment.ID+"/resolve", resolve, adminToken)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("resolve status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/transactions/"+payment.ID, nil, helperToken)
+	var tx models.Transaction
+	decodeBody(t, resp, &tx)
+	if resp.Code != http.StatusOK || tx.Status != "REFUNDED" || tx.Dispute == nil || tx.Dispute.Resolution.Outcome != "REFUND_FULL" {
+		t.Fatalf("get transaction status=%d body=%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestIdempotencyKeyReplaysResponse(t *testing.T) {
+	router, _ := setupRouter(t)
+	token, _ := authenticate(t, router, "+8801000000040")
+	otherToken, _ := authenticate(t, router, "+8801000000041")
+
+	create := func(token, key, description string) *httptest.ResponseRecorder {
+		t.Helper()
+		data, _ := json.Marshal(gin.H{
+			"type":        "URGENT",
+			"category":    "GENERAL_HELP",
+			"description": description,
+			"location":    gin.H{"lat": 23.78, "lng": 90.36, "address": "Dhaka"},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/v1/requests", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", key)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := create(token, "retry-1", "Carry groceries")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("create status=%d body=%s", first.Code, first.Body.String())
+	}
+	retry := create(token, "retry-1", "Carry groceries")
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" || retry.Body.String() != first.Body.String() {
+		t.Fatalf("expected replayed response, status=%d body=%s", retry.Code, retry.Body.String())
+	}
+
+	mismatch := create(token, "retry-1", "Something else")
+	if mismatch.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a different body, status=%d body=%s", mismatch.Code, mismatch.Body.String())
+	}
+	if other := create(otherToken, "retry-1", "Carry groceries"); other.Code != http.StatusCreated || other.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("expected key to be scoped per user, status=%d", other.Code)
+	}
+
+	resp := doRequest(t, router, http.MethodGet, "/v1/requests", nil, token)
+	var page models.RequestPage
+	decodeBody(t, resp, &page)
+	if len(page.Data) != 1 {
+		t.Fatalf("expected a single request to be created, got %d", len(page.Data))
+	}
+}
+
+func TestIdempotencyKeyReleasedAfterPanic(t *testing.T) {
+	store := memory.NewStore()
+	panics := true
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard), func(c *gin.Context) {
+		c.Set(middleware.ContextUserKey, &models.User{ID: "user-1"})
+	}, middleware.NewIdempotencyMiddleware(store))
+	router.POST("/jobs", func(c *gin.Context) {
+		if panics {
+			panic("boom")
+		}
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("{}"))
+		req.Header.Set("Idempotency-Key", "job-1")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	if resp := post(); resp.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking handler status=%d", resp.Code)
+	}
+	panics = false
+	if resp := post(); resp.Code != http.StatusCreated {
+		t.Fatalf("expected the key to be released after a panic, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestWebhookIngestion(t *testing.T) {
+	router, store := setupRouter(t)
+	adminToken, admin := authenticate(t, router, "+8801000000050")
+	if err := store.GrantRole(admin.ID, "ADMIN"); err != nil {
+		t.Fatalf("grant role: %v", err)
+	}
+
+	postStripe := func(payload []byte, signature string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v1/hooks/stripe", bytes.NewReader(payload))
+		req.Header.Set("Stripe-Signature", signature)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	payload, _ := json.Marshal(gateway.Event{ID: "evt_1", Type: gateway.EventIntentCaptured, ObjectID: "pi_1", Reference: "pay-404", Created: time.Now()})
+	if resp := postStripe(payload, "t=1,v1=00"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("bad signature status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	signature := gateway.SignPayload(testWebhookSecret, payload, time.Now())
+	if resp := postStripe(payload, signature); resp.Code != http.StatusOK {
+		t.Fatalf("stripe webhook status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp := postStripe(payload, signature)
+	var receipt struct {
+		Duplicate bool `json:"duplicate"`
+	}
+	decodeBody(t, resp, &receipt)
+	if resp.Code != http.StatusOK || !receipt.Duplicate {
+		t.Fatalf("expected duplicate delivery to be acknowledged, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+
+	form := "MessageSid=SM1&MessageStatus=delivered"
+	path := "/v1/hooks/twilio?notificationId=ntf-404"
+	params, _ := url.ParseQuery(form)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(twilio.SignatureHeader, twilio.Sign(testTwilioToken, testPublicURL+path, params))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("twilio webhook status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	if processed, _ := store.ProcessWebhooks(context.Background()); processed != 0 {
+		t.Fatalf("expected events for unknown objects to fail, processed %d", processed)
+	}
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/webhooks?status=FAILED", nil, adminToken)
+	var failed []models.WebhookEvent
+	decodeBody(t, resp, &failed)
+	if resp.Code != http.StatusOK || len(failed) != 2 || failed[0].Attempts != 1 || failed[0].LastError == "" {
+		t.Fatalf("expected two failed events, status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	resp = doRequest(t, router, http.MethodPost, "/v1/admin/webhooks/"+failed[0].ID+"/replay", nil, adminToken)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("replay status=%d body=%s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestFinancialReportExport(t *testing.T) {
+	router, store := setupRouter(t)
+	seekerToken, _ := authenticate(t, router, "+8801000000050")
+	helperToken, helper := authenticate(t, router, "+8801000000051")
+	adminToken, admin := authenticate(t, router, "+8801000000052")
+	if err := store.GrantRole(admin.ID, "ADMIN"); err != nil {
+		t.Fatalf("grant role: %v", err)
+	}
+
+	resp := doRequest(t, router, http.MethodPost, "/v1/requests", gin.H{
+		"type":        "URGENT",
+		"category":    "GENERAL_HELP",
+		"description": "Carry groceries",
+		"location":    gin.H{"lat": 23.78, "lng": 90.36, "address": "Dhaka"},
+	}, seekerToken)
+	var req models.HelpRequest
+	decodeBody(t, resp, &req)
+	match := store.SeedMatch(helper.ID, req.ID)
+	doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/accept", nil, helperToken)
+	doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/status", gin.H{"status": "COMPLETED"}, helperToken)
+	resp = doRequest(t, router, http.MethodPost, "/v1/matches/"+match.ID+"/complete", gin.H{"confirmation": "SUCCESS"}, seekerToken)
+	var payment models.Payment
+	decodeBody(t, resp, &payment)
+	if payment.Status != "RELEASED" {
+		t.Fatalf("expected released payment, got %+v", payment)
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	query := "?from=" + today + "&to=" + tomorrow
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/reports/fees"+query, nil, seekerToken)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected non-admins to be refused, got %d", resp.Code)
+	}
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/reports/fees"+query, nil, adminToken)
+	if resp.Code != http.StatusOK || !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("fees status=%d type=%s body=%s", resp.Code, resp.Header().Get("Content-Type"), resp.Body.String())
+	}
+	if disposition := resp.Header().Get("Content-Disposition"); !strings.Contains(disposition, "fees_"+today+"_"+tomorrow+".csv") {
+		t.Fatalf("unexpected disposition %q", disposition)
+	}
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "posted_at,") || !strings.Contains(lines[1], payment.ID) {
+		t.Fatalf("unexpected fees csv:\n%s", resp.Body.String())
+	}
+
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/reports/bonuses"+query, nil, adminToken)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("unknown report status=%d", resp.Code)
+	}
+	resp = doRequest(t, router, http.MethodGet, "/v1/admin/reports/fees?from="+tomorrow+"&to="+today, nil, adminToken)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("inverted range status=%d", resp.Code)
+	}
+
+	reconcile := func(settlement string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/reports/reconciliation"+query, strings.NewReader(settlement))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	amount, err := payment.Amount.In(payment.Currency)
+	if err != nil {
+		t.Fatalf("bind amount: %v", err)
+	}
+	resp = reconcile("type,gateway_ref,amount,currency\nCHARGE," + payment.GatewayRef + "," + amount.Decimal() + "," + payment.Currency + "\n")
+	if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), payment.GatewayRef) || !strings.Contains(resp.Body.String(), "MATCHED") {
+		t.Fatalf("reconcile status=%d body=%s", resp.Code, resp.Body.String())
+	}
+	if resp = reconcile("type,amount\nCHARGE,1\n"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("invalid settlement status=%d", resp.Code)
+	}
+}
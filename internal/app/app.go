@@ -1,19 +1,31 @@
 package app
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
+	"log"
+	"os"
 
 	"github.com/MuhibNayem/community-helper-app/internal/api"
 	"github.com/MuhibNayem/community-helper-app/internal/api/handlers"
 	"github.com/MuhibNayem/community-helper-app/internal/api/middleware"
 	"github.com/MuhibNayem/community-helper-app/internal/config"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/cancellation"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/pricing"
 	"github.com/MuhibNayem/community-helper-app/internal/domain/services/memory"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/scheduler"
 	"github.com/MuhibNayem/community-helper-app/internal/platform/server"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/storage"
 )
 
 type App struct {
-	cfg    *config.Config
-	server *server.HTTPServer
+	cfg       *config.Config
+	server    *server.HTTPServer
+	scheduler *scheduler.Scheduler
 }
 
 func New() (*App, error) {
@@ -22,28 +34,229 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
-	store := memory.NewStore()
+	objects, err := newObjectStorage(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init object storage: %w", err)
+	}
+
+	pricingEngine, err := newPricingEngine(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init pricing: %w", err)
+	}
+
+	quoteKey, err := secretOrEphemeral(cfg, "QUOTE_SIGNING_KEY", cfg.QuoteSigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentGateway, err := newGateway(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init payment gateway: %w", err)
+	}
+
+	twilioToken, err := secretOrEphemeral(cfg, "TWILIO_AUTH_TOKEN", []byte(cfg.TwilioAuthToken))
+	if err != nil {
+		return nil, err
+	}
+
+	payoutPolicy := memory.DefaultPayoutPolicy()
+	payoutPolicy.Interval = cfg.PayoutInterval
+	payoutPolicy.Minimum = cfg.PayoutMinimum
+
+	cancellationPolicy := cancellation.DefaultPolicy()
+	cancellationPolicy.GracePeriod = cfg.CancellationGracePeriod
+
+	store := memory.NewStore().
+		WithAdminPhones(cfg.AdminPhones).
+		WithPricing(pricingEngine).
+		WithQuotes(pricing.NewQuoteSigner(quoteKey), cfg.QuoteTTL).
+		WithObjectStorage(objects).
+		WithIdempotencyTTL(cfg.IdempotencyTTL).
+		WithGateway(paymentGateway).
+		WithCancellationPolicy(cancellationPolicy).
+		WithPayoutPolicy(payoutPolicy).
+		WithSchedulingPolicy(memory.SchedulingPolicy{
+			BookingHorizon:  cfg.BookingHorizon,
+			MatchingLead:    cfg.MatchingLead,
+			Reminders:       cfg.ScheduleReminders,
+			UnmatchedExpiry: cfg.RequestExpiry,
+		}).
+		WithKYCPolicy(memory.KYCPolicy{
+			RestrictPaid:         cfg.KYCRestrictPaid,
+			RestrictedCategories: cfg.KYCRestrictedCategories,
+			ExpiryReminders:      cfg.KYCExpiryReminders,
+		})
 
 	handlerSet := api.HandlerSet{
-		Auth:     handlers.NewAuthHandler(store),
-		Users:    handlers.NewUsersHandler(store),
-		Requests: handlers.NewRequestsHandler(store),
-		Matches:  handlers.NewMatchesHandler(store),
-		Health:   handlers.NewHealthHandler(cfg.Env),
+		Auth:          handlers.NewAuthHandler(store),
+		Users:         handlers.NewUsersHandler(store),
+		Requests:      handlers.NewRequestsHandler(store),
+		Matches:       handlers.NewMatchesHandler(store),
+		KYC:           handlers.NewKYCHandler(store),
+		Uploads:       handlers.NewUploadsHandler(store),
+		Attachments:   handlers.NewAttachmentsHandler(store),
+		Places:        handlers.NewPlacesHandler(store),
+		Series:        handlers.NewSeriesHandler(store),
+		Ops:           handlers.NewOpsHandler(store),
+		Payments:      handlers.NewPaymentsHandler(store),
+		Payouts:       handlers.NewPayoutsHandler(store),
+		Transactions:  handlers.NewTransactionsHandler(store),
+		Webhooks:      handlers.NewWebhooksHandler(store, paymentGateway, string(twilioToken), cfg.PublicBaseURL),
+		Ledger:        handlers.NewLedgerHandler(store),
+		Reports:       handlers.NewReportsHandler(store),
+		Pricing:       handlers.NewPricingHandler(store),
+		Notifications: handlers.NewNotificationsHandler(store),
+		Health:        handlers.NewHealthHandler(cfg.Env),
 	}
 
 	authMiddleware := middleware.NewAuthMiddleware(store)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(store)
 
-	router := api.NewRouter(cfg, handlerSet, authMiddleware)
+	router := api.NewRouter(cfg, handlerSet, authMiddleware, idempotencyMiddleware)
 
 	httpServer := server.NewHTTPServer(cfg.HTTPPort, router)
 
+	jobs := scheduler.New()
+	jobs.Every("generate-series-occurrences", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.GenerateSeriesOccurrences(ctx)
+		return err
+	})
+	jobs.Every("process-webhooks", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.ProcessWebhooks(ctx)
+		return err
+	})
+	jobs.Every("dispatch-due-requests", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.DispatchDue(ctx)
+		return err
+	})
+	jobs.Every("request-deadlines", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.SweepRequestDeadlines(ctx)
+		return err
+	})
+	jobs.Every("escrow-expiry", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.SweepEscrow(ctx)
+		return err
+	})
+	jobs.Every("payout-batches", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.RunPayoutBatch(ctx)
+		return err
+	})
+	jobs.Every("schedule-reminders", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.SweepScheduleReminders(ctx)
+		return err
+	})
+	jobs.Every("resume-paused-helpers", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.ResumeExpiredPauses(ctx)
+		return err
+	})
+	jobs.Every("cleanup-orphaned-uploads", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.CleanupOrphanedUploads(ctx)
+		return err
+	})
+	jobs.Every("idempotency-cleanup", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.SweepIdempotencyKeys(ctx)
+		return err
+	})
+	jobs.Every("kyc-expiry", cfg.JobInterval, func(ctx context.Context) error {
+		_, err := store.SweepKYCExpiry(ctx)
+		return err
+	})
+
 	return &App{
-		cfg:    cfg,
-		server: httpServer,
+		cfg:       cfg,
+		server:    httpServer,
+		scheduler: jobs,
 	}, nil
 }
 
 func (a *App) Run() error {
-	return a.server.Start()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a.scheduler.Start(ctx)
+	err := a.server.Start()
+
+	cancel()
+	a.scheduler.Wait()
+	return err
+}
+
+// newPricingEngine loads price books from PRICE_BOOK_FILE (a JSON array)
+// or falls back to the built-in launch price book.
+func newPricingEngine(cfg *config.Config) (*pricing.Engine, error) {
+	if cfg.PriceBookFile == "" {
+		return pricing.NewEngine(pricing.DefaultPriceBook())
+	}
+
+	data, err := os.ReadFile(cfg.PriceBookFile)
+	if err != nil {
+		return nil, fmt.Errorf("read price books: %w", err)
+	}
+
+	var books []models.PriceBook
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, fmt.Errorf("decode price books: %w", err)
+	}
+	return pricing.NewEngine(books...)
+}
+
+func newGateway(cfg *config.Config) (gateway.Gateway, error) {
+	switch cfg.PaymentGateway {
+	case "fake":
+		if cfg.Env == "production" {
+			log.Printf("PAYMENT_GATEWAY=fake: no real money will move")
+		}
+		secret, err := secretOrEphemeral(cfg, "GATEWAY_WEBHOOK_SECRET", cfg.GatewayWebhookSecret)
+		if err != nil {
+			return nil, err
+		}
+		return gateway.NewFake(secret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment gateway %q", cfg.PaymentGateway)
+	}
+}
+
+func newObjectStorage(cfg *config.Config) (memory.ObjectStorage, error) {
+	signingKey, err := secretOrEphemeral(cfg, "STORAGE_SIGNING_KEY", cfg.StorageSigningKey)
+	if err != nil {
+		return memory.ObjectStorage{}, err
+	}
+	encryptionKey, err := secretOrEphemeral(cfg, "STORAGE_ENCRYPTION_KEY", cfg.StorageEncryptionKey)
+	if err != nil {
+		return memory.ObjectStorage{}, err
+	}
+
+	local, err := storage.NewLocal(cfg.StorageDir)
+	if err != nil {
+		return memory.ObjectStorage{}, err
+	}
+
+	kyc, err := storage.NewEncrypted(local, encryptionKey)
+	if err != nil {
+		return memory.ObjectStorage{}, err
+	}
+
+	return memory.ObjectStorage{
+		KYC:         kyc,
+		Attachments: local,
+		Signer:      storage.NewSigner(signingKey),
+	}, nil
+}
+
+// secretOrEphemeral returns the configured secret, or outside production a
+// random 32-byte key that only lives as long as the process.
+func secretOrEphemeral(cfg *config.Config, name string, secret []byte) ([]byte, error) {
+	if len(secret) > 0 {
+		return secret, nil
+	}
+	if cfg.Env == "production" {
+		return nil, fmt.Errorf("%s is required in production", name)
+	}
+
+	log.Printf("%s not set; using an ephemeral key", name)
+	secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
 }
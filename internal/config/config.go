@@ -1,14 +1,67 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
 )
 
 type Config struct {
 	HTTPPort string
 
 	Env string
+
+	JobInterval time.Duration
+
+	// IdempotencyTTL is how long responses to requests sent with an
+	// Idempotency-Key are replayed to retries.
+	IdempotencyTTL time.Duration
+
+	AdminPhones []string
+
+	KYCRestrictPaid         bool
+	KYCRestrictedCategories []string
+	KYCExpiryReminders      []time.Duration
+
+	CancellationGracePeriod time.Duration
+
+	BookingHorizon    time.Duration
+	MatchingLead      time.Duration
+	ScheduleReminders []time.Duration
+	RequestExpiry     time.Duration
+
+	PriceBookFile   string
+	QuoteSigningKey []byte
+	QuoteTTL        time.Duration
+
+	StorageDir string
+	// StorageSigningKey signs upload URLs and StorageEncryptionKey (32
+	// bytes) encrypts sensitive objects at rest. Both are hex encoded in the
+	// environment and generated per process outside production when unset.
+	StorageSigningKey    []byte
+	StorageEncryptionKey []byte
+
+	// PaymentGateway selects the payment provider; only the in-process
+	// "fake" gateway is available so far. GatewayWebhookSecret (hex) signs
+	// its webhooks.
+	PaymentGateway       string
+	GatewayWebhookSecret []byte
+
+	// PublicBaseURL is the externally visible origin of the API; Twilio
+	// signs webhooks over the URL it called. TwilioAuthToken verifies them.
+	PublicBaseURL   string
+	TwilioAuthToken string
+
+	// PayoutInterval is the time between helper payout batches and
+	// PayoutMinimum the smallest balance paid out, in major units of each
+	// balance's currency.
+	PayoutInterval time.Duration
+	PayoutMinimum  money.Money
 }
 
 func Load() (*Config, error) {
@@ -22,12 +75,224 @@ func Load() (*Config, error) {
 		env = "development"
 	}
 
+	jobInterval, err := positiveDurationEnv("JOB_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyTTL, err := durationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	kycRestrictPaid, err := boolEnv("KYC_RESTRICT_PAID", true)
+	if err != nil {
+		return nil, err
+	}
+
+	kycExpiryReminders, err := durationListEnv("KYC_EXPIRY_REMINDERS", []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour})
+	if err != nil {
+		return nil, err
+	}
+
+	cancellationGrace, err := durationEnv("CANCELLATION_GRACE_PERIOD", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingHorizon, err := durationEnv("BOOKING_HORIZON", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingLead, err := durationEnv("MATCHING_LEAD", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleReminders, err := durationListEnv("SCHEDULE_REMINDERS", []time.Duration{24 * time.Hour, time.Hour})
+	if err != nil {
+		return nil, err
+	}
+
+	requestExpiry, err := durationEnv("REQUEST_EXPIRY", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	quoteSigningKey, err := hexEnv("QUOTE_SIGNING_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	quoteTTL, err := durationEnv("QUOTE_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	storageDir := os.Getenv("STORAGE_DIR")
+	if storageDir == "" {
+		storageDir = "data/objects"
+	}
+
+	signingKey, err := hexEnv("STORAGE_SIGNING_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey, err := hexEnv("STORAGE_ENCRYPTION_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	paymentGateway := os.Getenv("PAYMENT_GATEWAY")
+	if paymentGateway == "" {
+		paymentGateway = "fake"
+	}
+
+	webhookSecret, err := hexEnv("GATEWAY_WEBHOOK_SECRET")
+	if err != nil {
+		return nil, err
+	}
+
+	publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+	if publicBaseURL == "" {
+		publicBaseURL = "http://localhost:" + port
+	}
+
+	payoutInterval, err := positiveDurationEnv("PAYOUT_INTERVAL", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	payoutMinimum, err := moneyEnv("PAYOUT_MINIMUM", "500")
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		HTTPPort: port,
-		Env:      env,
+		HTTPPort:                port,
+		Env:                     env,
+		JobInterval:             jobInterval,
+		IdempotencyTTL:          idempotencyTTL,
+		AdminPhones:             listEnv("ADMIN_PHONES", nil),
+		KYCRestrictPaid:         kycRestrictPaid,
+		KYCRestrictedCategories: listEnv("KYC_RESTRICTED_CATEGORIES", []string{"MEDICAL_FIRST_AID"}),
+		KYCExpiryReminders:      kycExpiryReminders,
+		CancellationGracePeriod: cancellationGrace,
+		BookingHorizon:          bookingHorizon,
+		MatchingLead:            matchingLead,
+		ScheduleReminders:       scheduleReminders,
+		RequestExpiry:           requestExpiry,
+		PriceBookFile:           os.Getenv("PRICE_BOOK_FILE"),
+		QuoteSigningKey:         quoteSigningKey,
+		QuoteTTL:                quoteTTL,
+		StorageDir:              storageDir,
+		StorageSigningKey:       signingKey,
+		StorageEncryptionKey:    encryptionKey,
+		PaymentGateway:          paymentGateway,
+		GatewayWebhookSecret:    webhookSecret,
+		PublicBaseURL:           publicBaseURL,
+		TwilioAuthToken:         os.Getenv("TWILIO_AUTH_TOKEN"),
+		PayoutInterval:          payoutInterval,
+		PayoutMinimum:           payoutMinimum,
 	}, nil
 }
 
 func (c *Config) Address() string {
 	return fmt.Sprintf(":%s", c.HTTPPort)
 }
+
+func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func positiveDurationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	parsed, err := durationEnv(key, fallback)
+	if err != nil {
+		return 0, err
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("parse %s: must be positive, got %s", key, parsed)
+	}
+	return parsed, nil
+}
+
+func durationListEnv(key string, fallback []time.Duration) ([]time.Duration, error) {
+	raw := listEnv(key, nil)
+	if raw == nil {
+		return fallback, nil
+	}
+	values := make([]time.Duration, 0, len(raw))
+	for _, item := range raw {
+		parsed, err := time.ParseDuration(item)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", key, err)
+		}
+		values = append(values, parsed)
+	}
+	return values, nil
+}
+
+func boolEnv(key string, fallback bool) (bool, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("parse %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// moneyEnv reads a non-negative major-unit amount with no currency; it is
+// bound to one where it is used.
+func moneyEnv(key, fallback string) (money.Money, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		raw = fallback
+	}
+	parsed, err := money.Parse(raw, "")
+	if err != nil {
+		return money.Money{}, fmt.Errorf("parse %s: %w", key, err)
+	}
+	if parsed.IsNegative() {
+		return money.Money{}, fmt.Errorf("parse %s: must not be negative, got %s", key, parsed)
+	}
+	return parsed, nil
+}
+
+func listEnv(key string, fallback []string) []string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	var values []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func hexEnv(key string) ([]byte, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return nil, nil
+	}
+	decoded, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", key, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,162 @@
+package cancellation
+
+import (
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+// Lifecycle stages a cancellation can happen in.
+const (
+	StageUnmatched = "UNMATCHED"
+	StageAccepted  = "ACCEPTED"
+	StageEnRoute   = "EN_ROUTE"
+	StageArrived   = "ARRIVED"
+)
+
+// Policy decides what a cancellation costs the seeker based on who cancelled,
+// why, and how far the job had progressed. Percentages apply to the request's
+// estimated amount.
+type Policy struct {
+	// GracePeriod after a helper accepts during which the seeker may still
+	// cancel for free.
+	GracePeriod        time.Duration
+	AcceptedFeePercent float64
+	EnRouteFeePercent  float64
+	ArrivedFeePercent  float64
+	// MinimumFee applies whenever a fee is due, in major units of the
+	// request's currency.
+	MinimumFee float64
+	// ExemptReasons never incur a seeker penalty, e.g. when the helper is at
+	// fault or safety is at stake.
+	ExemptReasons []string
+	// NoShowFeePercent is charged to the seeker when an arrived helper
+	// cancels because the seeker did not show up.
+	NoShowFeePercent float64
+
+	// WithdrawalPenalty is the number of reliability points a helper loses
+	// for backing out of an accepted match, and LateWithdrawalPenalty the
+	// number once they are en route or have arrived.
+	WithdrawalPenalty     int
+	LateWithdrawalPenalty int
+	// ExemptWithdrawalReasons never cost the helper reliability.
+	ExemptWithdrawalReasons []string
+}
+
+type Input struct {
+	Initiator  string
+	Reason     string
+	Stage      string
+	AcceptedAt *time.Time
+	Now        time.Time
+	Amount     money.Money
+}
+
+// Decision is the outcome of applying the policy. Payer is empty when no
+// penalty is due. Penalty is in the currency of Input.Amount.
+type Decision struct {
+	Rule    string
+	Penalty money.Money
+	Payer   string
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		GracePeriod:        5 * time.Minute,
+		AcceptedFeePercent: 10,
+		EnRouteFeePercent:  25,
+		ArrivedFeePercent:  50,
+		MinimumFee:         50,
+		ExemptReasons:      []string{"HELPER_LATE", "SAFETY_CONCERN"},
+		NoShowFeePercent:   50,
+
+		WithdrawalPenalty:       5,
+		LateWithdrawalPenalty:   15,
+		ExemptWithdrawalReasons: []string{"UNSAFE_LOCATION"},
+	}
+}
+
+func (p Policy) Evaluate(in Input) Decision {
+	decision := p.decide(in)
+	if decision.Payer == "" {
+		decision.Penalty = money.Zero(in.Amount.Currency())
+	}
+	return decision
+}
+
+func (p Policy) decide(in Input) Decision {
+	if in.Initiator == "HELPER" {
+		if in.Reason == "SEEKER_NO_SHOW" && in.Stage == StageArrived {
+			return p.fee("SEEKER_NO_SHOW", in.Amount, p.NoShowFeePercent)
+		}
+		return Decision{Rule: "HELPER_CANCELLED"}
+	}
+
+	if in.Stage == StageUnmatched {
+		return Decision{Rule: "FREE_BEFORE_MATCH"}
+	}
+	for _, reason := range p.ExemptReasons {
+		if reason == in.Reason {
+			return Decision{Rule: "EXEMPT_REASON"}
+		}
+	}
+
+	switch in.Stage {
+	case StageAccepted:
+		if in.AcceptedAt != nil && in.Now.Sub(*in.AcceptedAt) <= p.GracePeriod {
+			return Decision{Rule: "GRACE_PERIOD"}
+		}
+		return p.fee("AFTER_ACCEPTANCE", in.Amount, p.AcceptedFeePercent)
+	case StageEnRoute:
+		return p.fee("HELPER_EN_ROUTE", in.Amount, p.EnRouteFeePercent)
+	default:
+		return p.fee("HELPER_ARRIVED", in.Amount, p.ArrivedFeePercent)
+	}
+}
+
+// ReliabilityPenalty returns the reliability points a helper loses for
+// withdrawing from a match at the given stage.
+func (p Policy) ReliabilityPenalty(stage, reason string) int {
+	for _, exempt := range p.ExemptWithdrawalReasons {
+		if exempt == reason {
+			return 0
+		}
+	}
+	if stage == StageEnRoute || stage == StageArrived {
+		return p.LateWithdrawalPenalty
+	}
+	return p.WithdrawalPenalty
+}
+
+// fee charges percent of amount, at least MinimumFee and at most the whole
+// amount, rounded to the minor unit.
+func (p Policy) fee(rule string, amount money.Money, percent float64) Decision {
+	if percent <= 0 {
+		return Decision{Rule: rule}
+	}
+	penalty, err := amount.Percent(percent)
+	if err != nil {
+		penalty = amount
+	}
+	if minimum, err := money.FromMajor(p.MinimumFee, amount.Currency()); err == nil && penalty.Amount() < minimum.Amount() {
+		penalty = minimum
+	}
+	if penalty.Amount() > amount.Amount() {
+		penalty = amount
+	}
+	return Decision{Rule: rule, Penalty: penalty, Payer: "SEEKER"}
+}
+
+// StageForMatch maps a match status to the cancellation stage.
+func StageForMatch(status string) string {
+	switch status {
+	case "ACCEPTED":
+		return StageAccepted
+	case "EN_ROUTE":
+		return StageEnRoute
+	case "ARRIVED", "IN_PROGRESS":
+		return StageArrived
+	default:
+		return StageUnmatched
+	}
+}
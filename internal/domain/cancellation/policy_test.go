@@ -0,0 +1,60 @@
+package cancellation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy := DefaultPolicy()
+	now := time.Date(2025, 2, 17, 9, 0, 0, 0, time.UTC)
+	justAccepted := now.Add(-2 * time.Minute)
+	acceptedEarlier := now.Add(-20 * time.Minute)
+
+	tests := []struct {
+		name    string
+		in      Input
+		rule    string
+		penalty float64
+	}{
+		{"before match", Input{Initiator: "SEEKER", Reason: "CHANGED_PLANS", Stage: StageUnmatched}, "FREE_BEFORE_MATCH", 0},
+		{"within grace", Input{Initiator: "SEEKER", Reason: "CHANGED_PLANS", Stage: StageAccepted, AcceptedAt: &justAccepted}, "GRACE_PERIOD", 0},
+		{"after grace", Input{Initiator: "SEEKER", Reason: "CHANGED_PLANS", Stage: StageAccepted, AcceptedAt: &acceptedEarlier}, "AFTER_ACCEPTANCE", 60},
+		{"helper en route", Input{Initiator: "SEEKER", Reason: "CHANGED_PLANS", Stage: StageEnRoute, AcceptedAt: &justAccepted}, "HELPER_EN_ROUTE", 150},
+		{"helper arrived", Input{Initiator: "SEEKER", Reason: "OTHER", Stage: StageArrived, AcceptedAt: &acceptedEarlier}, "HELPER_ARRIVED", 300},
+		{"exempt reason", Input{Initiator: "SEEKER", Reason: "HELPER_LATE", Stage: StageEnRoute, AcceptedAt: &acceptedEarlier}, "EXEMPT_REASON", 0},
+		{"helper cancels", Input{Initiator: "HELPER", Reason: "TASK_MISMATCH", Stage: StageArrived}, "HELPER_CANCELLED", 0},
+		{"seeker no-show", Input{Initiator: "HELPER", Reason: "SEEKER_NO_SHOW", Stage: StageArrived}, "SEEKER_NO_SHOW", 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.in.Now = now
+			tt.in.Amount = money.New(60000, "BDT")
+			got := policy.Evaluate(tt.in)
+			want, _ := money.FromMajor(tt.penalty, "BDT")
+			if got.Rule != tt.rule || got.Penalty != want {
+				t.Fatalf("got %+v, want rule %s penalty %v", got, tt.rule, tt.penalty)
+			}
+			if got.Penalty.IsPositive() != (got.Payer == "SEEKER") {
+				t.Fatalf("unexpected payer %q for penalty %v", got.Payer, got.Penalty)
+			}
+		})
+	}
+}
+
+func TestReliabilityPenalty(t *testing.T) {
+	policy := DefaultPolicy()
+
+	if got := policy.ReliabilityPenalty(StageAccepted, "SCHEDULE_CONFLICT"); got != 5 {
+		t.Fatalf("accepted withdrawal: got %d", got)
+	}
+	if got := policy.ReliabilityPenalty(StageEnRoute, "VEHICLE_ISSUE"); got != 15 {
+		t.Fatalf("late withdrawal: got %d", got)
+	}
+	if got := policy.ReliabilityPenalty(StageArrived, "UNSAFE_LOCATION"); got != 0 {
+		t.Fatalf("exempt withdrawal: got %d", got)
+	}
+}
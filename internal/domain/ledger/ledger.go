@@ -0,0 +1,184 @@
+// Package ledger is an append-only double-entry ledger. Every transaction
+// moves integer minor units between accounts in one currency and must sum
+// to zero, so the balances of all accounts always reconcile to zero.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+var (
+	ErrUnbalanced = errors.New("transaction does not balance")
+	ErrNoEntries  = errors.New("transaction has no entries")
+	ErrInvalid    = errors.New("invalid transaction")
+)
+
+// Platform accounts. Seekers and helpers have one account each.
+const (
+	// AccountAuthorized holds charges authorized at the gateway but not yet
+	// captured.
+	AccountAuthorized = "gateway:authorized"
+	AccountEscrow     = "escrow"
+	// AccountDisputed holds escrow frozen by a dispute.
+	AccountDisputed     = "escrow:disputed"
+	AccountPlatformFees = "platform:fees"
+	// AccountSubsidies funds the subsidised part of jobs; it runs negative.
+	AccountSubsidies = "platform:subsidies"
+	// AccountPayoutsInTransit holds helper payouts sent to the gateway but
+	// not yet confirmed paid.
+	AccountPayoutsInTransit = "payouts:in_transit"
+)
+
+// SeekerFunds is the seeker's money outside the platform. It runs negative
+// by what they have paid in.
+func SeekerFunds(userID string) string {
+	return "seeker:" + userID
+}
+
+// HelperBalance is what the platform owes a helper.
+func HelperBalance(userID string) string {
+	return "helper:" + userID
+}
+
+// HelperPaidOut is what a helper has been paid out to their destination.
+func HelperPaidOut(userID string) string {
+	return "payee:" + userID
+}
+
+// Transfer returns the two entries that move amount from one account to
+// another.
+func Transfer(from, to string, amount int64) []models.LedgerEntry {
+	return []models.LedgerEntry{{Account: from, Amount: -amount}, {Account: to, Amount: amount}}
+}
+
+// Ledger is not safe for concurrent use; callers serialise access.
+type Ledger struct {
+	transactions []models.LedgerTransaction
+	balances     map[balanceKey]int64
+	nextID       int
+}
+
+type balanceKey struct {
+	account  string
+	currency string
+}
+
+func New() *Ledger {
+	return &Ledger{balances: make(map[balanceKey]int64), nextID: 1}
+}
+
+// Post appends a transaction. Zero entries are dropped and entries for the
+// same account are merged; what remains must sum to zero.
+func (l *Ledger) Post(kind, reference, currency string, at time.Time, entries ...models.LedgerEntry) (models.LedgerTransaction, error) {
+	if kind == "" || currency == "" {
+		return models.LedgerTransaction{}, fmt.Errorf("%w: kind and currency are required", ErrInvalid)
+	}
+
+	merged := make(map[string]int64)
+	var order []string
+	var sum int64
+	for _, entry := range entries {
+		if entry.Account == "" {
+			return models.LedgerTransaction{}, fmt.Errorf("%w: entry without account", ErrInvalid)
+		}
+		if _, ok := merged[entry.Account]; !ok {
+			order = append(order, entry.Account)
+		}
+		merged[entry.Account] += entry.Amount
+		sum += entry.Amount
+	}
+	if sum != 0 {
+		return models.LedgerTransaction{}, fmt.Errorf("%w: off by %d", ErrUnbalanced, sum)
+	}
+
+	tx := models.LedgerTransaction{
+		ID:        fmt.Sprintf("ltx-%d", l.nextID),
+		Kind:      kind,
+		Reference: reference,
+		Currency:  currency,
+		PostedAt:  at,
+	}
+	for _, account := range order {
+		if merged[account] != 0 {
+			tx.Entries = append(tx.Entries, models.LedgerEntry{Account: account, Amount: merged[account]})
+		}
+	}
+	if len(tx.Entries) == 0 {
+		return models.LedgerTransaction{}, ErrNoEntries
+	}
+
+	l.nextID++
+	l.transactions = append(l.transactions, tx)
+	for _, entry := range tx.Entries {
+		l.balances[balanceKey{entry.Account, currency}] += entry.Amount
+	}
+	return copyTransaction(tx), nil
+}
+
+func (l *Ledger) Balance(account, currency string) int64 {
+	return l.balances[balanceKey{account, currency}]
+}
+
+// AccountBalances returns the account's non-zero balances, one per
+// currency, sorted by currency.
+func (l *Ledger) AccountBalances(account string) []models.AccountBalance {
+	balances := []models.AccountBalance{}
+	for key, balance := range l.balances {
+		if key.account == account && balance != 0 {
+			balances = append(balances, models.AccountBalance{Account: account, Currency: key.currency, Balance: balance})
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Currency < balances[j].Currency })
+	return balances
+}
+
+// Transactions returns the transactions for reference in posting order, or
+// every transaction when reference is empty.
+func (l *Ledger) Transactions(reference string) []models.LedgerTransaction {
+	results := []models.LedgerTransaction{}
+	for _, tx := range l.transactions {
+		if reference == "" || tx.Reference == reference {
+			results = append(results, copyTransaction(tx))
+		}
+	}
+	return results
+}
+
+// Summary reports every non-empty balance, sorted by currency and account,
+// and checks that each currency reconciles to zero.
+func (l *Ledger) Summary() models.LedgerSummary {
+	summary := models.LedgerSummary{
+		Balances: []models.AccountBalance{},
+		Totals:   make(map[string]int64),
+		Balanced: true,
+	}
+	for key, balance := range l.balances {
+		summary.Totals[key.currency] += balance
+		if balance != 0 {
+			summary.Balances = append(summary.Balances, models.AccountBalance{Account: key.account, Currency: key.currency, Balance: balance})
+		}
+	}
+	for _, total := range summary.Totals {
+		if total != 0 {
+			summary.Balanced = false
+		}
+	}
+	sort.Slice(summary.Balances, func(i, j int) bool {
+		a, b := summary.Balances[i], summary.Balances[j]
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		return a.Account < b.Account
+	})
+	return summary
+}
+
+func copyTransaction(tx models.LedgerTransaction) models.LedgerTransaction {
+	tx.Entries = append([]models.LedgerEntry(nil), tx.Entries...)
+	return tx
+}
@@ -0,0 +1,60 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+func TestPostAndReconcile(t *testing.T) {
+	l := New()
+	at := time.Date(2025, 2, 17, 9, 0, 0, 0, time.UTC)
+
+	if _, err := l.Post("PAYMENT_HELD", "pay-1", "BDT", at, Transfer(SeekerFunds("u1"), AccountEscrow, 50000)...); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	release := append(Transfer(AccountEscrow, HelperBalance("u2"), 45000), Transfer(AccountEscrow, AccountPlatformFees, 5000)...)
+	tx, err := l.Post("PAYMENT_RELEASED", "pay-1", "BDT", at, release...)
+	if err != nil {
+		t.Fatalf("post release: %v", err)
+	}
+	if len(tx.Entries) != 3 || tx.Entries[0].Account != AccountEscrow || tx.Entries[0].Amount != -50000 {
+		t.Fatalf("expected escrow legs merged, got %+v", tx.Entries)
+	}
+
+	if got := l.Balance(HelperBalance("u2"), "BDT"); got != 45000 {
+		t.Fatalf("expected helper balance 45000, got %d", got)
+	}
+	if got := l.Balance(AccountEscrow, "BDT"); got != 0 {
+		t.Fatalf("expected escrow emptied, got %d", got)
+	}
+
+	summary := l.Summary()
+	if !summary.Balanced || summary.Totals["BDT"] != 0 || len(summary.Balances) != 3 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if txs := l.Transactions("pay-1"); len(txs) != 2 {
+		t.Fatalf("expected two transactions for pay-1, got %d", len(txs))
+	}
+}
+
+func TestPostRejectsInvalidTransactions(t *testing.T) {
+	l := New()
+	at := time.Now()
+
+	unbalanced := []models.LedgerEntry{{Account: AccountEscrow, Amount: 100}, {Account: SeekerFunds("u1"), Amount: -90}}
+	if _, err := l.Post("PAYMENT_HELD", "pay-1", "BDT", at, unbalanced...); !errors.Is(err, ErrUnbalanced) {
+		t.Fatalf("expected ErrUnbalanced, got %v", err)
+	}
+	if _, err := l.Post("PAYMENT_HELD", "pay-1", "BDT", at, Transfer(AccountEscrow, SeekerFunds("u1"), 0)...); !errors.Is(err, ErrNoEntries) {
+		t.Fatalf("expected ErrNoEntries, got %v", err)
+	}
+	if _, err := l.Post("PAYMENT_HELD", "pay-1", "", at, Transfer(AccountEscrow, SeekerFunds("u1"), 5)...); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid without currency, got %v", err)
+	}
+	if len(l.Transactions("")) != 0 || !l.Summary().Balanced {
+		t.Fatalf("rejected transactions must not be recorded")
+	}
+}
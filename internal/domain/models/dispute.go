@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+// Transaction is a payment as shown to its parties, with the dispute raised
+// against it, if any.
+type Transaction struct {
+	Payment
+	Dispute *Dispute `json:"dispute,omitempty"`
+}
+
+// Dispute freezes a payment's escrow while ops review it. It is OPEN until
+// an admin resolves it, which settles the payment.
+type Dispute struct {
+	ID            string `json:"id"`
+	TransactionID string `json:"transactionId"`
+	RequestID     string `json:"requestId"`
+	OpenedBy      string `json:"openedBy"`
+	Reason        string `json:"reason"`
+	Details       string `json:"details,omitempty"`
+	Status        string `json:"status"`
+	// Evidence holds attachment keys uploaded through the attachments API.
+	Evidence   []string           `json:"evidence,omitempty"`
+	Messages   []DisputeMessage   `json:"messages"`
+	Resolution *DisputeResolution `json:"resolution,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+// DisputeMessage is one message in the thread between the seeker, the
+// helper and ops. AuthorRole is SEEKER, HELPER or OPS.
+type DisputeMessage struct {
+	AuthorID    string    `json:"authorId"`
+	AuthorRole  string    `json:"authorRole"`
+	Body        string    `json:"body"`
+	Attachments []string  `json:"attachments,omitempty"`
+	SentAt      time.Time `json:"sentAt"`
+}
+
+// DisputeResolution records how ops settled a dispute and where the money
+// went.
+type DisputeResolution struct {
+	Outcome        string      `json:"outcome"`
+	RefundedAmount money.Money `json:"refundedAmount"`
+	HelperAmount   money.Money `json:"helperAmount"`
+	Notes          string      `json:"notes,omitempty"`
+	ResolvedBy     string      `json:"resolvedBy"`
+	ResolvedAt     time.Time   `json:"resolvedAt"`
+}
+
+type OpenDisputeInput struct {
+	Reason   string   `json:"reason" binding:"required,oneof=SERVICE_NOT_COMPLETED POOR_QUALITY HELPER_NO_SHOW OVERCHARGED SAFETY_CONCERN OTHER"`
+	Details  string   `json:"details,omitempty" binding:"omitempty,max=1000"`
+	Evidence []string `json:"evidence,omitempty" binding:"omitempty,max=5"`
+}
+
+type DisputeMessageInput struct {
+	Body        string   `json:"body" binding:"required,max=2000"`
+	Attachments []string `json:"attachments,omitempty" binding:"omitempty,max=5"`
+}
+
+// ResolveDisputeInput settles a dispute. REFUND_FULL returns everything to
+// the seeker and RELEASE pays the helper as if the job was confirmed.
+// REFUND_PARTIAL refunds RefundAmount and SPLIT half of the payment; the
+// helper keeps the rest.
+type ResolveDisputeInput struct {
+	Outcome      string      `json:"outcome" binding:"required,oneof=REFUND_FULL REFUND_PARTIAL RELEASE SPLIT"`
+	RefundAmount money.Money `json:"refundAmount,omitzero"`
+	Notes        string      `json:"notes,omitempty" binding:"omitempty,max=1000"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// IdempotencyScope identifies one use of an Idempotency-Key: the same key
+// sent by another user or to another route is unrelated.
+type IdempotencyScope struct {
+	UserID string
+	Key    string
+	Route  string
+}
+
+// IdempotencyRecord remembers the first request made with a key and, once
+// it finished, its response. Response is nil while the request is in
+// flight.
+type IdempotencyRecord struct {
+	Scope       IdempotencyScope
+	RequestHash string
+	Response    *StoredResponse
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// StoredResponse is a response replayed to retries of the same request.
+type StoredResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// LedgerTransaction is one balanced posting in the double-entry ledger.
+// Amounts are integer minor units; entries of a transaction sum to zero.
+type LedgerTransaction struct {
+	ID        string        `json:"id"`
+	Kind      string        `json:"kind"`
+	Reference string        `json:"reference"`
+	Currency  string        `json:"currency"`
+	Entries   []LedgerEntry `json:"entries"`
+	PostedAt  time.Time     `json:"postedAt"`
+}
+
+// LedgerEntry moves Amount into Account; negative amounts move money out.
+type LedgerEntry struct {
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
+}
+
+type AccountBalance struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+}
+
+// LedgerSummary lists every account balance. Totals holds the sum of all
+// balances per currency, which is zero when the ledger reconciles.
+type LedgerSummary struct {
+	Balances []AccountBalance `json:"balances"`
+	Totals   map[string]int64 `json:"totals"`
+	Balanced bool             `json:"balanced"`
+}
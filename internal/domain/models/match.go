@@ -3,15 +3,19 @@ package models
 import "time"
 
 type MatchSession struct {
-	ID            string        `json:"id"`
-	RequestID     string        `json:"requestId"`
-	HelperID      string        `json:"helperId"`
-	Status        string        `json:"status"`
-	InvitedAt     time.Time     `json:"invitedAt"`
-	RespondedAt   *time.Time    `json:"respondedAt,omitempty"`
-	AcceptedAt    *time.Time    `json:"acceptedAt,omitempty"`
-	ArrivedAt     *time.Time    `json:"arrivedAt,omitempty"`
-	CompletedAt   *time.Time    `json:"completedAt,omitempty"`
+	ID          string     `json:"id"`
+	RequestID   string     `json:"requestId"`
+	HelperID    string     `json:"helperId"`
+	Status      string     `json:"status"`
+	InvitedAt   time.Time  `json:"invitedAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+	AcceptedAt  *time.Time `json:"acceptedAt,omitempty"`
+	ArrivedAt   *time.Time `json:"arrivedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	WithdrawnAt *time.Time `json:"withdrawnAt,omitempty"`
+	// SLABreachedAt flags an in-progress match that missed the request's
+	// completion deadline for ops escalation.
+	SLABreachedAt *time.Time    `json:"slaBreachedAt,omitempty"`
 	ETAMinutes    int           `json:"etaMinutes,omitempty"`
 	SmsSent       bool          `json:"smsSent"`
 	PushSent      bool          `json:"pushSent"`
@@ -24,6 +28,24 @@ type MatchMetrics struct {
 	TravelTime int     `json:"travelTime"`
 }
 
+// InvitationListFilter selects a page of a helper's matches. Status takes a
+// comma-separated set of statuses; From and To bound invitedAt. Results are
+// ordered by invitedAt, newest first unless Order is "asc".
+type InvitationListFilter struct {
+	Status string     `form:"status"`
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+	Order  string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string     `form:"cursor"`
+}
+
+// InvitationPage is one page of matches. NextCursor is empty on the last page.
+type InvitationPage struct {
+	Data       []MatchSession `json:"data"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
 type DeclineMatchInput struct {
 	Reason string `json:"reason" binding:"required"`
 }
@@ -31,3 +53,13 @@ type DeclineMatchInput struct {
 type MatchStatusUpdate struct {
 	Status string `json:"status" binding:"required"`
 }
+
+type HelperCancelInput struct {
+	Reason  string `json:"reason" binding:"required,oneof=SEEKER_NO_SHOW SEEKER_UNREACHABLE UNSAFE_LOCATION TASK_MISMATCH OTHER"`
+	Details string `json:"details,omitempty" binding:"omitempty,max=500"`
+}
+
+type WithdrawMatchInput struct {
+	Reason  string `json:"reason" binding:"required,oneof=EMERGENCY VEHICLE_ISSUE SCHEDULE_CONFLICT UNSAFE_LOCATION OTHER"`
+	Details string `json:"details,omitempty" binding:"omitempty,max=500"`
+}
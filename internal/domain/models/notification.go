@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type Notification struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"userId"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	// Delivery tracks the SMS copy of the notification, if one was sent.
+	Delivery *NotificationDelivery `json:"delivery,omitempty"`
+}
+
+// NotificationDelivery is the provider's latest delivery receipt for a
+// notification sent over SMS.
+type NotificationDelivery struct {
+	Channel     string    `json:"channel"`
+	ProviderRef string    `json:"providerRef"`
+	Status      string    `json:"status"`
+	ErrorCode   string    `json:"errorCode,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
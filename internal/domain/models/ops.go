@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SLAMetrics summarises expired requests and SLA breaches recorded by the
+// deadline sweeper, with the in-progress jobs awaiting ops follow-up.
+type SLAMetrics struct {
+	ExpiredRequests            int             `json:"expiredRequests"`
+	MatchDeadlineBreaches      int             `json:"matchDeadlineBreaches"`
+	CompletionDeadlineBreaches int             `json:"completionDeadlineBreaches"`
+	Escalations                []SLAEscalation `json:"escalations"`
+}
+
+type SLAEscalation struct {
+	RequestID  string    `json:"requestId"`
+	MatchID    string    `json:"matchId"`
+	HelperID   string    `json:"helperId"`
+	Deadline   time.Time `json:"deadline"`
+	BreachedAt time.Time `json:"breachedAt"`
+}
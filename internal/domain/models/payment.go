@@ -1,24 +1,74 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+// Payment is the seeker's payment for an accepted match. Funds move
+// PENDING → AUTHORIZED → HELD in escrow, then end RELEASED to the helper,
+// REFUNDED to the seeker, or DISPUTED while ops review the job. A charge the
+// gateway declines ends FAILED.
 type Payment struct {
-	ID            string    `json:"id"`
-	RequestID     string    `json:"requestId"`
-	HelperID      string    `json:"helperId"`
-	Amount        float64   `json:"amount"`
-	Currency      string    `json:"currency"`
-	Status        string    `json:"status"`
-	EscrowExpires time.Time `json:"escrowExpires"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID        string `json:"id"`
+	RequestID string `json:"requestId"`
+	MatchID   string `json:"matchId"`
+	SeekerID  string `json:"seekerId"`
+	HelperID  string `json:"helperId"`
+	// Amount is what the seeker pays. The helper earns Amount + Subsidy -
+	// PlatformFee once the escrow is released.
+	Amount         money.Money `json:"amount"`
+	PlatformFee    money.Money `json:"platformFee"`
+	Subsidy        money.Money `json:"subsidy,omitzero"`
+	HelperEarnings money.Money `json:"helperEarnings"`
+	// PenaltyAmount is the part of a refunded payment paid to the helper: a
+	// cancellation fee or their share of a dispute settlement.
+	// RefundedAmount is what went back to the seeker.
+	PenaltyAmount  money.Money `json:"penaltyAmount,omitzero"`
+	RefundedAmount money.Money `json:"refundedAmount,omitzero"`
+	Currency       string      `json:"currency"`
+	Status         string      `json:"status"`
+	// GatewayRef is the payment provider's intent ID.
+	GatewayRef    string         `json:"gatewayRef,omitempty"`
+	EscrowExpires time.Time      `json:"escrowExpires"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	Timeline      []PaymentEvent `json:"timeline"`
+}
+
+type PaymentEvent struct {
+	Status string    `json:"status"`
+	Note   string    `json:"note,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// CompleteSessionInput is the seeker's confirmation that a completed job was
+// done. SUCCESS releases the escrow; FAILED disputes it.
+type CompleteSessionInput struct {
+	Confirmation string   `json:"confirmation" binding:"required,oneof=SUCCESS FAILED"`
+	Reason       string   `json:"reason,omitempty" binding:"required_if=Confirmation FAILED,max=500"`
+	Evidence     []string `json:"evidence,omitempty"`
+}
+
+// PaymentMethod is a seeker's saved card or mobile wallet. Only the
+// gateway's token and masked details are stored.
+type PaymentMethod struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"-"`
+	Type       string    `json:"type"`
+	Brand      string    `json:"brand"`
+	Last4      string    `json:"last4"`
+	ExpMonth   int       `json:"expMonth,omitempty"`
+	ExpYear    int       `json:"expYear,omitempty"`
+	IsDefault  bool      `json:"isDefault"`
+	GatewayRef string    `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
-type Payout struct {
-	ID        string    `json:"id"`
-	HelperID  string    `json:"helperId"`
-	Amount    float64   `json:"amount"`
-	Currency  string    `json:"currency"`
-	Status    string    `json:"status"`
-	Processed time.Time `json:"processed"`
+// AttachPaymentMethodInput carries the token the gateway's client SDK
+// created; raw card or wallet numbers never reach the API.
+type AttachPaymentMethodInput struct {
+	PaymentMethodID string `json:"paymentMethodId" binding:"required"`
+	MakeDefault     bool   `json:"makeDefault,omitempty"`
 }
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+// Payout transfers a helper's balance to their payout destination. It moves
+// PENDING → PROCESSING → PAID, or ends FAILED with the balance restored.
+type Payout struct {
+	ID       string      `json:"id"`
+	BatchID  string      `json:"batchId"`
+	HelperID string      `json:"helperId"`
+	Amount   money.Money `json:"amount"`
+	Currency string      `json:"currency"`
+	Status   string      `json:"status"`
+	// Destination is the masked account the payout was sent to.
+	Destination   string         `json:"destination"`
+	GatewayRef    string         `json:"gatewayRef,omitempty"`
+	FailureReason string         `json:"failureReason,omitempty"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	Processed     *time.Time     `json:"processed,omitempty"`
+	Receipt       *PayoutReceipt `json:"receipt,omitempty"`
+}
+
+// PayoutReceipt is issued once a payout has been paid.
+type PayoutReceipt struct {
+	Number      string      `json:"number"`
+	IssuedAt    time.Time   `json:"issuedAt"`
+	Amount      money.Money `json:"amount"`
+	Currency    string      `json:"currency"`
+	Destination string      `json:"destination"`
+}
+
+// PayoutDestination is where a helper is paid: a bank account or a mobile
+// wallet such as bKash or Nagad. Only the masked account number is exposed.
+type PayoutDestination struct {
+	HelperID      string    `json:"helperId"`
+	Type          string    `json:"type"`
+	Provider      string    `json:"provider"`
+	AccountName   string    `json:"accountName"`
+	MaskedAccount string    `json:"maskedAccount"`
+	AccountNumber string    `json:"-"`
+	RoutingNumber string    `json:"-"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type PayoutDestinationInput struct {
+	Type          string `json:"type" binding:"required,oneof=BANK MOBILE_WALLET"`
+	Provider      string `json:"provider" binding:"required"`
+	AccountName   string `json:"accountName" binding:"required"`
+	AccountNumber string `json:"accountNumber" binding:"required,numeric,min=6,max=34"`
+	RoutingNumber string `json:"routingNumber,omitempty" binding:"required_if=Type BANK"`
+}
+
+// HelperEarnings summarises a helper's balances per currency and when the
+// next payout batch runs.
+type HelperEarnings struct {
+	Balances      []EarningsBalance `json:"balances"`
+	MinimumPayout money.Money       `json:"minimumPayout"`
+	NextPayoutAt  time.Time         `json:"nextPayoutAt"`
+}
+
+// EarningsBalance splits a helper's lifetime earnings in one currency into
+// what is available for the next payout, in transit, and already paid out.
+type EarningsBalance struct {
+	Currency  string      `json:"currency"`
+	Available money.Money `json:"available"`
+	InTransit money.Money `json:"inTransit"`
+	PaidOut   money.Money `json:"paidOut"`
+	Lifetime  money.Money `json:"lifetime"`
+}
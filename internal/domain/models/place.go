@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type SavedPlace struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"userId"`
+	Label     string          `json:"label"`
+	Location  RequestLocation `json:"location"`
+	IsDefault bool            `json:"isDefault"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+type SavedPlaceInput struct {
+	Label     string          `json:"label" binding:"required,max=50"`
+	Location  RequestLocation `json:"location" binding:"required"`
+	IsDefault bool            `json:"isDefault"`
+}
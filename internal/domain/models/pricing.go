@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+// PriceBook is an immutable, versioned set of pricing rules. The book with
+// the latest EffectiveFrom not after the quote time applies. Its amounts are
+// bound to Currency when the book is published.
+type PriceBook struct {
+	Version       string    `json:"version" binding:"required"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+	Currency      string    `json:"currency" binding:"required,len=3"`
+
+	DefaultBaseFee  money.Money            `json:"defaultBaseFee"`
+	BaseFees        map[string]money.Money `json:"baseFees,omitempty"`
+	UrgentSurcharge money.Money            `json:"urgentSurcharge"`
+
+	// Travel beyond FreeRadiusKm of the service area centre is charged per
+	// kilometre.
+	ServiceArea  GeoPoint    `json:"serviceArea"`
+	FreeRadiusKm float64     `json:"freeRadiusKm" binding:"min=0"`
+	PerKmFee     money.Money `json:"perKmFee"`
+
+	// Night hours wrap midnight when NightStartHour > NightEndHour. When
+	// both night and holiday apply only the larger multiplier is used.
+	NightStartHour    int      `json:"nightStartHour" binding:"min=0,max=23"`
+	NightEndHour      int      `json:"nightEndHour" binding:"min=0,max=23"`
+	NightMultiplier   float64  `json:"nightMultiplier" binding:"min=0"`
+	Holidays          []string `json:"holidays,omitempty"`
+	HolidayMultiplier float64  `json:"holidayMultiplier" binding:"min=0"`
+
+	PlatformFeePercent float64         `json:"platformFeePercent" binding:"min=0,max=100"`
+	Discounts          []PriceDiscount `json:"discounts,omitempty"`
+}
+
+// PriceDiscount lowers the seeker's price. Discounts with a PromoCode apply
+// only when the seeker supplies it; the rest apply to every request in the
+// listed categories (all categories when empty). Subsidies are funded by
+// the platform, so the helper's share is unaffected.
+type PriceDiscount struct {
+	Code       string      `json:"code"`
+	Label      string      `json:"label"`
+	PromoCode  string      `json:"promoCode,omitempty"`
+	Categories []string    `json:"categories,omitempty"`
+	Percent    float64     `json:"percent,omitempty"`
+	Amount     money.Money `json:"amount,omitzero"`
+	Subsidy    bool        `json:"subsidy,omitempty"`
+}
+
+type GeoPoint struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+}
+
+// PriceQuote locks a price for a short time. Passing its ID as quoteId when
+// creating the request guarantees the request is priced exactly as quoted.
+type PriceQuote struct {
+	ID        string    `json:"id"`
+	Pricing   Pricing   `json:"pricing"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ReportFilter selects the days a financial report covers. Dates are UTC;
+// From is inclusive and To exclusive, so a daily report runs from one day
+// to the next.
+type ReportFilter struct {
+	From time.Time `form:"from" time_format:"2006-01-02" time_utc:"1" binding:"required"`
+	To   time.Time `form:"to" time_format:"2006-01-02" time_utc:"1" binding:"required,gtfield=From"`
+}
+
+// Report is a tabular export. Amounts in Rows are decimal major units.
+type Report struct {
+	Name   string     `json:"name"`
+	Header []string   `json:"header"`
+	Rows   [][]string `json:"rows"`
+}
+
+// SettlementRecord is one money movement as the gateway or the ledger
+// records it. Type is CHARGE, REFUND or PAYOUT and GatewayRef the
+// provider's intent or transfer ID. Amount is in integer minor units.
+type SettlementRecord struct {
+	Type       string    `json:"type"`
+	GatewayRef string    `json:"gatewayRef"`
+	Reference  string    `json:"reference,omitempty"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	SettledAt  time.Time `json:"settledAt,omitempty"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
 
 type HelpRequest struct {
 	ID           string          `json:"id"`
@@ -12,12 +16,50 @@ type HelpRequest struct {
 	Attachments  []string        `json:"attachments,omitempty"`
 	Location     RequestLocation `json:"location"`
 	ScheduledFor *time.Time      `json:"scheduledFor,omitempty"`
-	CreatedAt    time.Time       `json:"createdAt"`
-	UpdatedAt    time.Time       `json:"updatedAt"`
-	ExpiresAt    *time.Time      `json:"expiresAt,omitempty"`
-	SLA          SLAWindows      `json:"sla"`
-	Pricing      Pricing         `json:"pricing"`
-	Cancellation *Cancellation   `json:"cancellation,omitempty"`
+	// MatchingStartsAt is set while a SCHEDULED request waits for its
+	// matching lead time.
+	MatchingStartsAt *time.Time `json:"matchingStartsAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	SLA              SLAWindows `json:"sla"`
+	Pricing          Pricing    `json:"pricing"`
+	PromoCode        string     `json:"promoCode,omitempty"`
+	// PaymentMethodID is the saved method charged when a helper accepts;
+	// empty charges the gateway's default for the seeker.
+	PaymentMethodID string `json:"paymentMethodId,omitempty"`
+	// PreferredHelperID is invited alone until PreferredUntil before the
+	// request is offered to everyone else.
+	PreferredHelperID string     `json:"preferredHelperId,omitempty"`
+	PreferredUntil    *time.Time `json:"preferredUntil,omitempty"`
+	SubmittedAt       *time.Time `json:"submittedAt,omitempty"`
+	// SeriesID and SeriesOccurrence link a request generated by a recurring
+	// series to the occurrence it was generated for.
+	SeriesID         string        `json:"seriesId,omitempty"`
+	SeriesOccurrence *time.Time    `json:"seriesOccurrence,omitempty"`
+	Cancellation     *Cancellation `json:"cancellation,omitempty"`
+	History          []RequestEdit `json:"history,omitempty"`
+	SLABreaches      []SLABreach   `json:"slaBreaches,omitempty"`
+}
+
+// SLABreach records a missed SLA deadline: MATCH_DEADLINE or
+// COMPLETION_DEADLINE.
+type SLABreach struct {
+	Kind       string    `json:"kind"`
+	Deadline   time.Time `json:"deadline"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// RequestEdit records one edit to a request and the fields it changed.
+type RequestEdit struct {
+	EditedAt time.Time     `json:"editedAt"`
+	Changes  []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
 }
 
 type RequestLocation struct {
@@ -34,16 +76,32 @@ type SLAWindows struct {
 }
 
 type Pricing struct {
-	EstimatedAmount float64 `json:"estimatedAmount"`
-	Currency        string  `json:"currency"`
-	PlatformFee     float64 `json:"platformFee"`
+	EstimatedAmount  money.Money      `json:"estimatedAmount"`
+	Currency         string           `json:"currency"`
+	PlatformFee      money.Money      `json:"platformFee"`
+	Subsidy          money.Money      `json:"subsidy,omitzero"`
+	PriceBookVersion string           `json:"priceBookVersion,omitempty"`
+	QuoteID          string           `json:"quoteId,omitempty"`
+	Breakdown        []PriceComponent `json:"breakdown,omitempty"`
+}
+
+type PriceComponent struct {
+	Code   string      `json:"code"`
+	Label  string      `json:"label"`
+	Amount money.Money `json:"amount"`
 }
 
 type Cancellation struct {
-	Reason         string    `json:"reason"`
-	Initiator      string    `json:"initiator"`
-	Timestamp      time.Time `json:"timestamp"`
-	PenaltyApplied bool      `json:"penaltyApplied"`
+	Reason         string      `json:"reason"`
+	Details        string      `json:"details,omitempty"`
+	Initiator      string      `json:"initiator"`
+	Stage          string      `json:"stage"`
+	Timestamp      time.Time   `json:"timestamp"`
+	PenaltyApplied bool        `json:"penaltyApplied"`
+	PenaltyRule    string      `json:"penaltyRule"`
+	PenaltyAmount  money.Money `json:"penaltyAmount"`
+	PenaltyPayer   string      `json:"penaltyPayer,omitempty"`
+	Currency       string      `json:"currency,omitempty"`
 }
 
 type RateRequest struct {
@@ -52,20 +110,61 @@ type RateRequest struct {
 }
 
 type CreateHelpRequestInput struct {
-	Type         string          `json:"type" binding:"required,oneof=URGENT PLANNED"`
-	Category     string          `json:"category" binding:"required"`
-	Description  string          `json:"description" binding:"required"`
-	Location     RequestLocation `json:"location" binding:"required"`
-	ScheduledFor *time.Time      `json:"scheduledFor,omitempty" binding:"omitempty"`
-	Attachments  []string        `json:"attachments,omitempty"`
+	Type         string           `json:"type" binding:"required,oneof=URGENT PLANNED"`
+	Category     string           `json:"category" binding:"required"`
+	Description  string           `json:"description" binding:"required"`
+	Location     *RequestLocation `json:"location,omitempty" binding:"required_without=SavedPlaceID"`
+	SavedPlaceID string           `json:"savedPlaceId,omitempty"`
+	ScheduledFor *time.Time       `json:"scheduledFor,omitempty" binding:"omitempty"`
+	Attachments  []string         `json:"attachments,omitempty"`
+	PromoCode    string           `json:"promoCode,omitempty"`
+	QuoteID      string           `json:"quoteId,omitempty"`
+	// PaymentMethodID selects a saved payment method; the seeker's default
+	// is used when omitted.
+	PaymentMethodID string `json:"paymentMethodId,omitempty"`
+	// Draft saves the request without submitting it for matching.
+	Draft bool `json:"draft,omitempty"`
+}
+
+// UpdateHelpRequestInput changes the given fields of a draft or unmatched
+// request. Omitted fields are left as they are.
+type UpdateHelpRequestInput struct {
+	Type         *string          `json:"type,omitempty" binding:"omitempty,oneof=URGENT PLANNED"`
+	Category     *string          `json:"category,omitempty" binding:"omitempty,min=1"`
+	Description  *string          `json:"description,omitempty" binding:"omitempty,min=1"`
+	Location     *RequestLocation `json:"location,omitempty"`
+	ScheduledFor *time.Time       `json:"scheduledFor,omitempty"`
+	PromoCode    *string          `json:"promoCode,omitempty"`
 }
 
+// RequestListFilter selects a page of the caller's requests. Status takes a
+// comma-separated set of statuses; From and To bound createdAt. Results are
+// ordered by createdAt, newest first unless Order is "asc".
 type RequestListFilter struct {
-	Status string `form:"status"`
-	Limit  int    `form:"limit"`
-	Offset int    `form:"offset"`
+	Type     string     `form:"type" binding:"omitempty,oneof=URGENT PLANNED"`
+	Category string     `form:"category"`
+	Status   string     `form:"status"`
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+	Order    string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit    int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string     `form:"cursor"`
+}
+
+// RequestPage is one page of requests. NextCursor is empty on the last page.
+type RequestPage struct {
+	Data       []HelpRequest `json:"data"`
+	NextCursor string        `json:"nextCursor,omitempty"`
 }
 
 type CancelRequestInput struct {
-	Reason string `json:"reason" binding:"required"`
+	Reason  string `json:"reason" binding:"required,oneof=HELPER_NOT_NEEDED FOUND_OTHER_HELP CHANGED_PLANS HELPER_LATE SAFETY_CONCERN DUPLICATE_REQUEST OTHER"`
+	Details string `json:"details,omitempty" binding:"omitempty,max=500"`
+}
+
+type RepeatRequestInput struct {
+	// PreferSameHelper invites the helper who took the original request
+	// before anyone else.
+	PreferSameHelper bool       `json:"preferSameHelper,omitempty"`
+	ScheduledFor     *time.Time `json:"scheduledFor,omitempty"`
 }
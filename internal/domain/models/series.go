@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// RequestSeries generates a PLANNED HelpRequest for each occurrence of its
+// recurrence rule. Each occurrence is an ordinary request and can be edited
+// or cancelled on its own.
+type RequestSeries struct {
+	ID                 string          `json:"id"`
+	RequesterID        string          `json:"requesterId"`
+	Status             string          `json:"status"`
+	Recurrence         string          `json:"recurrence"`
+	StartsAt           time.Time       `json:"startsAt"`
+	Category           string          `json:"category"`
+	Description        string          `json:"description"`
+	Location           RequestLocation `json:"location"`
+	PromoCode          string          `json:"promoCode,omitempty"`
+	PreferredHelperID  string          `json:"preferredHelperId,omitempty"`
+	SkippedOccurrences []time.Time     `json:"skippedOccurrences,omitempty"`
+	GeneratedThrough   *time.Time      `json:"generatedThrough,omitempty"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+	Occurrences        []HelpRequest   `json:"occurrences,omitempty"`
+}
+
+type CreateSeriesInput struct {
+	Category     string           `json:"category" binding:"required"`
+	Description  string           `json:"description" binding:"required"`
+	Location     *RequestLocation `json:"location,omitempty" binding:"required_without=SavedPlaceID"`
+	SavedPlaceID string           `json:"savedPlaceId,omitempty"`
+	PromoCode    string           `json:"promoCode,omitempty"`
+	// StartsAt is the first occurrence; later ones keep its local time.
+	StartsAt          time.Time `json:"startsAt" binding:"required"`
+	Recurrence        string    `json:"recurrence" binding:"required"`
+	PreferredHelperID string    `json:"preferredHelperId,omitempty"`
+}
+
+type SkipOccurrenceInput struct {
+	Occurrence time.Time `json:"occurrence" binding:"required"`
+}
+
+type CancelSeriesInput struct {
+	Reason string `json:"reason" binding:"required,oneof=HELPER_NOT_NEEDED FOUND_OTHER_HELP CHANGED_PLANS HELPER_LATE SAFETY_CONCERN DUPLICATE_REQUEST OTHER"`
+}
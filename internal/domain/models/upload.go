@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type UploadTicket struct {
+	Key          string     `json:"key"`
+	Purpose      string     `json:"purpose"`
+	OwnerID      string     `json:"-"`
+	UploadURL    string     `json:"uploadUrl"`
+	Method       string     `json:"method"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	MaxBytes     int64      `json:"maxBytes"`
+	AllowedTypes []string   `json:"allowedTypes"`
+	ContentType  string     `json:"contentType,omitempty"`
+	Size         int64      `json:"size,omitempty"`
+	ThumbnailKey string     `json:"thumbnailKey,omitempty"`
+	IssuedAt     time.Time  `json:"-"`
+	UploadedAt   *time.Time `json:"uploadedAt,omitempty"`
+	ClaimedAt    *time.Time `json:"-"`
+	AttachedTo   string     `json:"-"`
+}
+
+type UploadSignature struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+type ObjectContent struct {
+	ContentType string
+	Data        []byte
+}
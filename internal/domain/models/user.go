@@ -10,12 +10,23 @@ type User struct {
 	Language          string            `json:"language"`
 	IsHelper          bool              `json:"isHelper"`
 	HelperStatus      string            `json:"helperStatus,omitempty"`
+	PausedUntil       *time.Time        `json:"pausedUntil,omitempty"`
 	NotificationPrefs NotificationPrefs `json:"notificationPrefs"`
 	KYCStatus         string            `json:"kycStatus,omitempty"`
+	Roles             []string          `json:"roles,omitempty"`
 	CreatedAt         time.Time         `json:"createdAt"`
 	UpdatedAt         time.Time         `json:"updatedAt"`
 }
 
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 type NotificationPrefs struct {
 	QuietHours QuietHours `json:"quietHours"`
 	UrgentSMS  bool       `json:"urgentSms"`
@@ -32,9 +43,13 @@ type HelperProfile struct {
 	Availability Availability `json:"availability"`
 	Rating       float64      `json:"rating"`
 	RatingCount  int          `json:"ratingCount"`
-	Badges       []string     `json:"badges"`
-	OptedIn      bool         `json:"optedIn"`
-	UpdatedAt    time.Time    `json:"updatedAt"`
+	// ReliabilityScore starts at 100 and drops when the helper withdraws
+	// from matches they accepted.
+	ReliabilityScore int       `json:"reliabilityScore"`
+	Withdrawals      int       `json:"withdrawals"`
+	Badges           []string  `json:"badges"`
+	OptedIn          bool      `json:"optedIn"`
+	UpdatedAt        time.Time `json:"updatedAt"`
 }
 
 type Availability struct {
@@ -59,15 +74,18 @@ type ExceptionSlot struct {
 }
 
 type KYCDocument struct {
-	ID           string    `json:"id"`
-	UserID       string    `json:"userId"`
-	DocumentType string    `json:"documentType"`
-	FileURL      string    `json:"fileUrl"`
-	Status       string    `json:"status"`
-	SubmittedAt  time.Time `json:"submittedAt"`
-	ReviewedAt   time.Time `json:"reviewedAt,omitempty"`
-	ReviewerID   string    `json:"reviewerId,omitempty"`
-	Notes        string    `json:"notes,omitempty"`
+	ID           string     `json:"id"`
+	UserID       string     `json:"userId"`
+	DocumentType string     `json:"documentType"`
+	FileKey      string     `json:"fileKey"`
+	ContentType  string     `json:"contentType"`
+	Status       string     `json:"status"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	ExpiringSoon bool       `json:"expiringSoon,omitempty"`
+	SubmittedAt  time.Time  `json:"submittedAt"`
+	ReviewedAt   *time.Time `json:"reviewedAt,omitempty"`
+	ReviewerID   string     `json:"reviewerId,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
 }
 
 type UserUpdate struct {
@@ -80,6 +98,11 @@ type HelperToggle struct {
 	OptedIn bool `json:"optedIn"`
 }
 
+type HelperPauseInput struct {
+	DurationMinutes int        `json:"durationMinutes,omitempty" binding:"omitempty,min=1"`
+	Until           *time.Time `json:"until,omitempty"`
+}
+
 type SkillsUpdate struct {
 	Skills []string `json:"skills" binding:"required"`
 }
@@ -101,6 +124,13 @@ type AvailabilityExceptionInput struct {
 }
 
 type KYCDocumentUpload struct {
-	DocumentType string `json:"documentType" binding:"required"`
-	FileURL      string `json:"fileUrl" binding:"required,url"`
+	DocumentType string     `json:"documentType" binding:"required"`
+	FileKey      string     `json:"fileKey" binding:"required"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+type KYCReviewInput struct {
+	Decision  string     `json:"decision" binding:"required,oneof=APPROVED REJECTED"`
+	Notes     string     `json:"notes,omitempty" binding:"required_if=Decision REJECTED,max=1000"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
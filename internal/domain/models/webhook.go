@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// WebhookEvent is a verified provider webhook held in the inbox until it
+// has been applied. It moves RECEIVED → PROCESSED, or FAILED while retries
+// remain and DEAD once they are exhausted; DEAD events can be replayed.
+type WebhookEvent struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	// EventID is the provider's event ID used for deduplication.
+	EventID string `json:"eventId"`
+	Type    string `json:"type"`
+	// Reference is our object the event concerns, when the provider echoes
+	// one back (e.g. the notification an SMS was sent for).
+	Reference     string     `json:"reference,omitempty"`
+	Payload       []byte     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"lastError,omitempty"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	ReceivedAt    time.Time  `json:"receivedAt"`
+	ProcessedAt   *time.Time `json:"processedAt,omitempty"`
+}
+
+type WebhookInput struct {
+	Provider  string
+	EventID   string
+	Type      string
+	Reference string
+	Payload   []byte
+}
+
+type WebhookListFilter struct {
+	Provider string `form:"provider" binding:"omitempty,oneof=STRIPE TWILIO"`
+	Status   string `form:"status" binding:"omitempty,oneof=RECEIVED PROCESSED FAILED DEAD"`
+}
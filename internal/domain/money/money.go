@@ -0,0 +1,362 @@
+// Package money is an amount of a currency held as integer minor units
+// (paisa, cents) with its ISO 4217 code. Arithmetic is exact and refuses to
+// mix currencies or overflow; conversions from decimal rates and major-unit
+// floats round half away from zero to the currency's minor unit.
+//
+// In JSON a Money is a bare decimal number in major units, e.g. 562.5, so
+// documents keep their existing shape with the currency in a sibling field.
+// A decoded Money has no currency until In binds it to one.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrOverflow         = errors.New("amount out of range")
+	ErrPrecision        = errors.New("amount is more precise than the currency's minor unit")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// exponents lists the minor-unit exponent of the ISO 4217 currencies the
+// platform accepts.
+var exponents = map[string]int{
+	"BDT": 2, "INR": 2, "NPR": 2, "PKR": 2, "LKR": 2,
+	"USD": 2, "EUR": 2, "GBP": 2, "AUD": 2, "CAD": 2,
+	"SGD": 2, "MYR": 2, "AED": 2, "SAR": 2, "QAR": 2,
+	"JPY": 0, "KRW": 0, "VND": 0,
+	"BHD": 3, "KWD": 3, "OMR": 3, "JOD": 3,
+}
+
+// unboundExponent is the precision of amounts decoded without a currency.
+// It is the largest exponent in use, so binding never loses digits that a
+// real currency could hold.
+const unboundExponent = 3
+
+// Money is immutable. The zero value is zero in any currency: it can be
+// added to and compared with amounts of every currency.
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{amount: amount, currency: strings.ToUpper(currency)}
+}
+
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// FromMajor converts a major-unit amount such as a configured fee, rounding
+// half away from zero to the minor unit.
+func FromMajor(amount float64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	minor := math.Round(amount * scale(Exponent(currency)))
+	if math.IsNaN(minor) || minor >= math.MaxInt64 || minor < math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %g %s", ErrOverflow, amount, currency)
+	}
+	return Money{amount: int64(minor), currency: currency}, nil
+}
+
+// Parse reads an exact decimal in major units, e.g. "1234.50". It rejects
+// more fraction digits than the currency has.
+func Parse(value, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	amount, err := parseDecimal(strings.TrimSpace(value), Exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// ValidCurrency reports whether code is a supported ISO 4217 currency.
+func ValidCurrency(code string) bool {
+	_, ok := exponents[code]
+	return ok
+}
+
+// Exponent is the number of minor-unit digits of currency. Unsupported
+// codes default to two.
+func Exponent(currency string) int {
+	if currency == "" {
+		return unboundExponent
+	}
+	if exponent, ok := exponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Major is the amount in major units, for display and percentages only.
+func (m Money) Major() float64 {
+	return float64(m.amount) / scale(Exponent(m.currency))
+}
+
+// Decimal formats the amount with all of the currency's minor digits, e.g.
+// "500.00".
+func (m Money) Decimal() string {
+	exponent := Exponent(m.currency)
+	sign, amount := "", m.amount
+	if amount < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absolute(amount), 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	cut := len(digits) - exponent
+	return sign + digits[:cut] + "." + digits[cut:]
+}
+
+// String formats the amount with its currency, e.g. "500.00 BDT".
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.currency
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrOverflow, m, other)
+	}
+	currency := m.currency
+	if currency == "" {
+		currency = other.currency
+	}
+	return Money{amount: sum, currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrOverflow, m, other)
+	}
+	return m.Add(other.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Cmp returns -1, 0 or 1 as m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul scales the amount by factor, rounding half away from zero.
+func (m Money) Mul(factor float64) (Money, error) {
+	product := math.Round(float64(m.amount) * factor)
+	if math.IsNaN(product) || product >= math.MaxInt64 || product < math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s × %g", ErrOverflow, m, factor)
+	}
+	return Money{amount: int64(product), currency: m.currency}, nil
+}
+
+// Percent is percent/100 of the amount, rounded like Mul.
+func (m Money) Percent(percent float64) (Money, error) {
+	return m.Mul(percent / 100)
+}
+
+// Split divides the amount into n parts that differ by at most one minor
+// unit and sum exactly to the amount; earlier parts take the remainder.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	share, remainder := m.amount/int64(n), m.amount%int64(n)
+	parts := make([]Money, n)
+	for i := range parts {
+		parts[i] = Money{amount: share, currency: m.currency}
+		switch {
+		case remainder > 0:
+			parts[i].amount++
+			remainder--
+		case remainder < 0:
+			parts[i].amount--
+			remainder++
+		}
+	}
+	return parts
+}
+
+// In binds a decoded amount to currency. Amounts that already have a
+// currency must match it.
+func (m Money) In(currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if m.currency == currency {
+		return m, nil
+	}
+	if m.currency != "" {
+		return Money{}, fmt.Errorf("%w: %s is not %s", ErrCurrencyMismatch, m, currency)
+	}
+
+	amount := m.amount
+	for exponent := unboundExponent; exponent > Exponent(currency); exponent-- {
+		if amount%10 != 0 {
+			return Money{}, fmt.Errorf("%w: %s %s", ErrPrecision, m.Decimal(), currency)
+		}
+		amount /= 10
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// Sum adds values, all of which must be in currency.
+func Sum(currency string, values ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, value := range values {
+		var err error
+		if total, err = total.Add(value); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Min returns the smaller of a and b.
+func Min(a, b Money) (Money, error) {
+	cmp, err := a.Cmp(b)
+	if err != nil {
+		return Money{}, err
+	}
+	if cmp > 0 {
+		return b, nil
+	}
+	return a, nil
+}
+
+// MarshalJSON writes the amount as a major-unit number without trailing
+// zeros, the same text encoding a float64 amount produced.
+func (m Money) MarshalJSON() ([]byte, error) {
+	text := m.Decimal()
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	if text == "-0" {
+		text = "0"
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalJSON reads a major-unit number. The result has no currency; use
+// In to bind it to the document's currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if len(data) == 0 || data[0] == '"' {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+	}
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+	}
+	text := number.String()
+	if strings.ContainsAny(text, "eE") {
+		value, err := number.Float64()
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+		}
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	amount, err := parseDecimal(text, unboundExponent)
+	if err != nil {
+		return err
+	}
+	*m = Money{amount: amount}
+	return nil
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m == (Money{}) || other == (Money{}) {
+		return nil
+	}
+	if m.currency != other.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currencyName(), other.currencyName())
+	}
+	return nil
+}
+
+func (m Money) currencyName() string {
+	if m.currency == "" {
+		return "no currency"
+	}
+	return m.currency
+}
+
+// parseDecimal converts a decimal with at most exponent fraction digits to
+// minor units without going through floating point.
+func parseDecimal(value string, exponent int) (int64, error) {
+	digits := strings.TrimPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q", ErrPrecision, value)
+		}
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+	if strings.HasPrefix(value, "-") {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func scale(exponent int) float64 {
+	return math.Pow10(exponent)
+}
+
+func absolute(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
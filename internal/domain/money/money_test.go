@@ -0,0 +1,151 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	price := New(56250, "bdt")
+	fee, err := FromMajor(56.25, "BDT")
+	if err != nil || price.Currency() != "BDT" || fee.Amount() != 5625 {
+		t.Fatalf("unexpected values %v %v, %v", price, fee, err)
+	}
+
+	net, err := price.Sub(fee)
+	if err != nil || net != New(50625, "BDT") {
+		t.Fatalf("sub = %v, %v", net, err)
+	}
+	if _, err := price.Add(New(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	var decoded Money
+	json.Unmarshal([]byte("5"), &decoded)
+	if _, err := price.Cmp(decoded); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected unbound amounts to be refused, got %v", err)
+	}
+	if sum, err := (Money{}).Add(price); err != nil || sum != price {
+		t.Fatalf("expected the zero value to add to any currency, got %v, %v", sum, err)
+	}
+	if _, err := New(math.MaxInt64, "BDT").Add(New(1, "BDT")); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+	if smaller, _ := Min(price, fee); smaller != fee {
+		t.Fatalf("min = %v", smaller)
+	}
+	if total, err := Sum("BDT", price, fee.Neg()); err != nil || total != net {
+		t.Fatalf("sum = %v, %v", total, err)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	for _, tc := range []struct {
+		amount  Money
+		percent float64
+		want    int64
+	}{
+		{New(1005, "BDT"), 50, 503},
+		{New(-1005, "BDT"), 50, -503},
+		{New(60000, "BDT"), 12.5, 7500},
+		{New(333, "BDT"), 10, 33},
+	} {
+		got, err := tc.amount.Percent(tc.percent)
+		if err != nil || got.Amount() != tc.want {
+			t.Fatalf("%v × %v%% = %v, %v; want %d", tc.amount, tc.percent, got, err, tc.want)
+		}
+	}
+	if got, _ := FromMajor(0.125, "BDT"); got.Amount() != 13 {
+		t.Fatalf("expected half away from zero, got %d", got.Amount())
+	}
+	if got, _ := FromMajor(1500.4, "JPY"); got.Amount() != 1500 {
+		t.Fatalf("expected JPY to have no minor unit, got %d", got.Amount())
+	}
+	for _, amount := range []float64{1e17, -1e17, math.Inf(1), math.NaN()} {
+		if _, err := FromMajor(amount, "BDT"); !errors.Is(err, ErrOverflow) {
+			t.Fatalf("FromMajor(%g): expected ErrOverflow, got %v", amount, err)
+		}
+	}
+
+	parts := New(1001, "BDT").Split(3)
+	if len(parts) != 3 || parts[0].Amount() != 334 || parts[2].Amount() != 333 {
+		t.Fatalf("unexpected split %v", parts)
+	}
+	if total, _ := Sum("BDT", parts...); total.Amount() != 1001 {
+		t.Fatalf("split parts sum to %v", total)
+	}
+}
+
+func TestFormatAndParse(t *testing.T) {
+	for _, tc := range []struct {
+		money   Money
+		decimal string
+		json    string
+	}{
+		{New(50000, "BDT"), "500.00", "500"},
+		{New(56250, "BDT"), "562.50", "562.5"},
+		{New(-5, "BDT"), "-0.05", "-0.05"},
+		{New(1500, "JPY"), "1500", "1500"},
+		{New(1234, "KWD"), "1.234", "1.234"},
+		{Zero("BDT"), "0.00", "0"},
+	} {
+		if got := tc.money.Decimal(); got != tc.decimal {
+			t.Fatalf("Decimal(%#v) = %q, want %q", tc.money, got, tc.decimal)
+		}
+		if data, _ := json.Marshal(tc.money); string(data) != tc.json {
+			t.Fatalf("json(%v) = %s, want %s", tc.money, data, tc.json)
+		}
+		if parsed, err := Parse(tc.decimal, tc.money.Currency()); err != nil || parsed != tc.money {
+			t.Fatalf("Parse(%q) = %v, %v", tc.decimal, parsed, err)
+		}
+	}
+	if got := New(50000, "BDT").String(); got != "500.00 BDT" {
+		t.Fatalf("String = %q", got)
+	}
+	for _, bad := range []string{"", "abc", "1.234", "--1", "+1", "1e3"} {
+		if _, err := Parse(bad, "BDT"); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type document struct {
+		Amount   Money  `json:"amount"`
+		Subsidy  Money  `json:"subsidy,omitzero"`
+		Currency string `json:"currency"`
+	}
+
+	data, err := json.Marshal(document{Amount: New(56250, "BDT"), Currency: "BDT"})
+	if err != nil || string(data) != `{"amount":562.5,"currency":"BDT"}` {
+		t.Fatalf("marshal = %s, %v", data, err)
+	}
+
+	var decoded document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.Amount.Currency() != "" {
+		t.Fatalf("expected decoded amount to have no currency, got %v", decoded.Amount)
+	}
+	bound, err := decoded.Amount.In(decoded.Currency)
+	if err != nil || bound != New(56250, "BDT") {
+		t.Fatalf("In = %v, %v", bound, err)
+	}
+	if _, err := bound.In("USD"); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected rebinding to fail, got %v", err)
+	}
+
+	var yen Money
+	json.Unmarshal([]byte("1500.5"), &yen)
+	if _, err := yen.In("JPY"); !errors.Is(err, ErrPrecision) {
+		t.Fatalf("expected fractional yen to be refused, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`"500"`), &yen); err == nil {
+		t.Fatalf("expected a string amount to be refused")
+	}
+	if err := json.Unmarshal([]byte("1e3"), &yen); err != nil || yen.Major() != 1000 {
+		t.Fatalf("expected exponent notation to decode, got %v, %v", yen, err)
+	}
+}
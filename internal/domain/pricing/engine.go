@@ -0,0 +1,349 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+var (
+	ErrNoPriceBook      = errors.New("no price book in effect")
+	ErrUnknownPromoCode = errors.New("unknown promo code")
+)
+
+// Dhaka observes a fixed UTC+6 offset, which is what night and holiday
+// rules are evaluated in.
+var dhaka = time.FixedZone("Asia/Dhaka", 6*60*60)
+
+// Quote describes what is being priced.
+type Quote struct {
+	Type      string
+	Category  string
+	Location  models.RequestLocation
+	PromoCode string
+	// At selects the price book; ServiceAt is when the help is delivered and
+	// drives night and holiday multipliers.
+	At        time.Time
+	ServiceAt time.Time
+}
+
+// Engine prices requests from versioned price books. It is not safe for
+// concurrent use; callers serialise access.
+type Engine struct {
+	books []models.PriceBook
+}
+
+func NewEngine(books ...models.PriceBook) (*Engine, error) {
+	e := &Engine{}
+	for _, book := range books {
+		if _, err := e.Publish(book); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Publish adds a new price book version and returns it with its amounts
+// bound to the book's currency. Versions are immutable, so publishing an
+// existing version fails.
+func (e *Engine) Publish(book models.PriceBook) (models.PriceBook, error) {
+	if err := Validate(book); err != nil {
+		return models.PriceBook{}, err
+	}
+	book, err := bind(book)
+	if err != nil {
+		return models.PriceBook{}, err
+	}
+	for _, existing := range e.books {
+		if existing.Version == book.Version {
+			return models.PriceBook{}, fmt.Errorf("price book version %q already exists", book.Version)
+		}
+	}
+
+	e.books = append(e.books, book)
+	sort.SliceStable(e.books, func(i, j int) bool {
+		return e.books[i].EffectiveFrom.Before(e.books[j].EffectiveFrom)
+	})
+	return book, nil
+}
+
+func (e *Engine) Books() []models.PriceBook {
+	return append([]models.PriceBook{}, e.books...)
+}
+
+func (e *Engine) Active(at time.Time) (models.PriceBook, error) {
+	for i := len(e.books) - 1; i >= 0; i-- {
+		if !e.books[i].EffectiveFrom.After(at) {
+			return e.books[i], nil
+		}
+	}
+	return models.PriceBook{}, ErrNoPriceBook
+}
+
+func (e *Engine) Quote(q Quote) (models.Pricing, error) {
+	book, err := e.Active(q.At)
+	if err != nil {
+		return models.Pricing{}, err
+	}
+	return Price(book, q)
+}
+
+// Price applies a single price book to the quote. Book amounts must be bound
+// to the book's currency, as Publish does; derived components are rounded to
+// the minor unit and the totals are exact sums of the components.
+//
+// The seeker pays EstimatedAmount. PlatformFee is the platform's commission
+// on the job value and Subsidy is the part of the job value the platform
+// funds, so the helper earns EstimatedAmount + Subsidy - PlatformFee.
+func Price(book models.PriceBook, q Quote) (models.Pricing, error) {
+	serviceAt := q.ServiceAt
+	if serviceAt.IsZero() {
+		serviceAt = q.At
+	}
+
+	currency := book.Currency
+	// err keeps the first arithmetic error; amounts all share the book's
+	// currency, so only overflow can fail.
+	var err error
+	check := func(amount money.Money, opErr error) money.Money {
+		if err == nil {
+			err = opErr
+		}
+		return amount
+	}
+
+	var components []models.PriceComponent
+	add := func(code, label string, amount money.Money) {
+		components = append(components, models.PriceComponent{Code: code, Label: label, Amount: amount})
+	}
+	total := func() money.Money {
+		amounts := make([]money.Money, 0, len(components))
+		for _, c := range components {
+			amounts = append(amounts, c.Amount)
+		}
+		return check(money.Sum(currency, amounts...))
+	}
+
+	base, ok := book.BaseFees[q.Category]
+	if !ok {
+		base = book.DefaultBaseFee
+	}
+	add("BASE_FEE", "Base fee", base)
+
+	if q.Type == "URGENT" && book.UrgentSurcharge.IsPositive() {
+		add("URGENT_SURCHARGE", "Urgent request", book.UrgentSurcharge)
+	}
+
+	if book.PerKmFee.IsPositive() {
+		distance := distanceKm(book.ServiceArea, q.Location) - book.FreeRadiusKm
+		if distance > 0 {
+			add("DISTANCE", fmt.Sprintf("Travel (%.1f km)", distance), check(book.PerKmFee.Mul(distance)))
+		}
+	}
+
+	multiplier, code, label := 1.0, "", ""
+	if isNight(book, serviceAt) && book.NightMultiplier > multiplier {
+		multiplier, code, label = book.NightMultiplier, "NIGHT_SURCHARGE", "Night hours"
+	}
+	if isHoliday(book, serviceAt) && book.HolidayMultiplier > multiplier {
+		multiplier, code, label = book.HolidayMultiplier, "HOLIDAY_SURCHARGE", "Public holiday"
+	}
+	if multiplier > 1 {
+		add(code, label, check(total().Mul(multiplier-1)))
+	}
+	gross := total()
+
+	promoMatched := false
+	discount, subsidy := money.Zero(currency), money.Zero(currency)
+	for _, d := range book.Discounts {
+		if d.PromoCode != "" {
+			if !strings.EqualFold(d.PromoCode, q.PromoCode) {
+				continue
+			}
+			promoMatched = true
+		}
+		if !appliesTo(d, q.Category) {
+			continue
+		}
+
+		offered := check(check(gross.Percent(d.Percent)).Add(d.Amount))
+		remaining := check(money.Sum(currency, gross, discount.Neg(), subsidy.Neg()))
+		amount := check(money.Min(offered, remaining))
+		if !amount.IsPositive() {
+			continue
+		}
+		add(d.Code, d.Label, amount.Neg())
+		if d.Subsidy {
+			subsidy = check(subsidy.Add(amount))
+		} else {
+			discount = check(discount.Add(amount))
+		}
+	}
+	if q.PromoCode != "" && !promoMatched {
+		return models.Pricing{}, ErrUnknownPromoCode
+	}
+
+	jobValue := check(gross.Sub(discount))
+	pricing := models.Pricing{
+		EstimatedAmount:  check(jobValue.Sub(subsidy)),
+		Currency:         currency,
+		PlatformFee:      check(jobValue.Percent(book.PlatformFeePercent)),
+		Subsidy:          subsidy,
+		PriceBookVersion: book.Version,
+		Breakdown:        components,
+	}
+	if err != nil {
+		return models.Pricing{}, fmt.Errorf("price: %w", err)
+	}
+	return pricing, nil
+}
+
+func Validate(book models.PriceBook) error {
+	if book.Version == "" {
+		return fmt.Errorf("price book version required")
+	}
+	if !money.ValidCurrency(book.Currency) {
+		return fmt.Errorf("price book currency must be a supported ISO 4217 code")
+	}
+
+	amounts := []money.Money{book.DefaultBaseFee, book.UrgentSurcharge, book.PerKmFee}
+	for _, fee := range book.BaseFees {
+		amounts = append(amounts, fee)
+	}
+	for _, amount := range amounts {
+		if amount.IsNegative() {
+			return fmt.Errorf("price book amounts must not be negative")
+		}
+	}
+	if book.FreeRadiusKm < 0 {
+		return fmt.Errorf("free radius must not be negative")
+	}
+
+	for _, m := range []float64{book.NightMultiplier, book.HolidayMultiplier} {
+		if m != 0 && m < 1 {
+			return fmt.Errorf("multipliers must be at least 1")
+		}
+	}
+	if book.NightStartHour < 0 || book.NightStartHour > 23 || book.NightEndHour < 0 || book.NightEndHour > 23 {
+		return fmt.Errorf("night hours must be between 0 and 23")
+	}
+	if book.PlatformFeePercent < 0 || book.PlatformFeePercent > 100 {
+		return fmt.Errorf("platform fee percent must be between 0 and 100")
+	}
+
+	for _, day := range book.Holidays {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return fmt.Errorf("holiday %q must be YYYY-MM-DD", day)
+		}
+	}
+	for _, d := range book.Discounts {
+		if d.Code == "" {
+			return fmt.Errorf("discount code required")
+		}
+		if d.Percent < 0 || d.Percent > 100 || d.Amount.IsNegative() {
+			return fmt.Errorf("discount %s must have a percent between 0 and 100 and a non-negative amount", d.Code)
+		}
+	}
+	return nil
+}
+
+// bind binds the book's amounts to its currency, rejecting amounts with more
+// fraction digits than the currency has.
+func bind(book models.PriceBook) (models.PriceBook, error) {
+	var err error
+	in := func(field string, amount money.Money) money.Money {
+		bound, bindErr := amount.In(book.Currency)
+		if err == nil && bindErr != nil {
+			err = fmt.Errorf("price book %s: %w", field, bindErr)
+		}
+		return bound
+	}
+
+	book.DefaultBaseFee = in("defaultBaseFee", book.DefaultBaseFee)
+	book.UrgentSurcharge = in("urgentSurcharge", book.UrgentSurcharge)
+	book.PerKmFee = in("perKmFee", book.PerKmFee)
+	if book.BaseFees != nil {
+		fees := make(map[string]money.Money, len(book.BaseFees))
+		for category, fee := range book.BaseFees {
+			fees[category] = in("baseFees."+category, fee)
+		}
+		book.BaseFees = fees
+	}
+	book.Discounts = append([]models.PriceDiscount(nil), book.Discounts...)
+	for i := range book.Discounts {
+		book.Discounts[i].Amount = in("discount "+book.Discounts[i].Code, book.Discounts[i].Amount)
+	}
+	return book, err
+}
+
+// DefaultPriceBook reproduces the flat launch pricing: 500 BDT with a 10%
+// platform fee, plus urgent, travel and night surcharges.
+func DefaultPriceBook() models.PriceBook {
+	return models.PriceBook{
+		Version:            "2025-01",
+		Currency:           "BDT",
+		DefaultBaseFee:     money.New(50000, "BDT"),
+		UrgentSurcharge:    money.New(10000, "BDT"),
+		ServiceArea:        models.GeoPoint{Latitude: 23.8103, Longitude: 90.4125},
+		FreeRadiusKm:       10,
+		PerKmFee:           money.New(2000, "BDT"),
+		NightStartHour:     22,
+		NightEndHour:       6,
+		NightMultiplier:    1.25,
+		HolidayMultiplier:  1.5,
+		PlatformFeePercent: 10,
+	}
+}
+
+func appliesTo(d models.PriceDiscount, category string) bool {
+	if len(d.Categories) == 0 {
+		return true
+	}
+	for _, c := range d.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func isNight(book models.PriceBook, at time.Time) bool {
+	if book.NightStartHour == book.NightEndHour {
+		return false
+	}
+	hour := at.In(dhaka).Hour()
+	if book.NightStartHour < book.NightEndHour {
+		return hour >= book.NightStartHour && hour < book.NightEndHour
+	}
+	return hour >= book.NightStartHour || hour < book.NightEndHour
+}
+
+func isHoliday(book models.PriceBook, at time.Time) bool {
+	day := at.In(dhaka).Format(time.DateOnly)
+	for _, holiday := range book.Holidays {
+		if holiday == day {
+			return true
+		}
+	}
+	return false
+}
+
+func distanceKm(from models.GeoPoint, to models.RequestLocation) float64 {
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLat := lat2 - lat1
+	dLng := radians(to.Longitude - from.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
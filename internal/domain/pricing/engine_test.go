@@ -0,0 +1,132 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+func TestPrice(t *testing.T) {
+	book := DefaultPriceBook()
+	book.BaseFees = map[string]money.Money{"TUTORING": money.New(80000, "BDT")}
+	book.Holidays = []string{"2025-03-26"}
+	book.Discounts = []models.PriceDiscount{
+		{Code: "ELDER_SUBSIDY", Label: "Elder care subsidy", Categories: []string{"ELDER_CARE"}, Percent: 20, Subsidy: true},
+		{Code: "WELCOME", Label: "Welcome offer", PromoCode: "WELCOME50", Amount: money.New(5000, "BDT")},
+	}
+
+	dhakaCentre := models.RequestLocation{Latitude: 23.8103, Longitude: 90.4125, Address: "Dhaka"}
+	// Roughly 22 km north of the centre.
+	gazipur := models.RequestLocation{Latitude: 24.0, Longitude: 90.4125, Address: "Gazipur"}
+	noon := time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC)     // 12:00 Dhaka
+	night := time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC)   // 23:00 Dhaka
+	holiday := time.Date(2025, 3, 26, 17, 0, 0, 0, time.UTC) // 23:00 Dhaka on 26 March
+
+	tests := []struct {
+		name       string
+		quote      Quote
+		wantAmount float64
+		wantFee    float64
+		wantSub    float64
+	}{
+		{"planned default", Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: dhakaCentre, At: noon}, 500, 50, 0},
+		{"category base fee", Quote{Type: "PLANNED", Category: "TUTORING", Location: dhakaCentre, At: noon}, 800, 80, 0},
+		{"urgent surcharge", Quote{Type: "URGENT", Category: "GENERAL_HELP", Location: dhakaCentre, At: noon}, 600, 60, 0},
+		{"night multiplier", Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: dhakaCentre, At: noon, ServiceAt: night}, 625, 62.5, 0},
+		{"holiday beats night", Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: dhakaCentre, At: noon, ServiceAt: holiday}, 750, 75, 0},
+		{"promo discount", Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: dhakaCentre, At: noon, PromoCode: "welcome50"}, 450, 45, 0},
+		{"platform subsidy", Quote{Type: "PLANNED", Category: "ELDER_CARE", Location: dhakaCentre, At: noon}, 400, 50, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Price(book, tt.quote)
+			if err != nil {
+				t.Fatalf("price: %v", err)
+			}
+			want := func(amount float64) money.Money {
+				m, _ := money.FromMajor(amount, "BDT")
+				return m
+			}
+			if got.EstimatedAmount != want(tt.wantAmount) || got.PlatformFee != want(tt.wantFee) || got.Subsidy != want(tt.wantSub) {
+				t.Fatalf("got amount=%v fee=%v subsidy=%v, want %v/%v/%v", got.EstimatedAmount, got.PlatformFee, got.Subsidy, tt.wantAmount, tt.wantFee, tt.wantSub)
+			}
+
+			sum := money.Zero("BDT")
+			for _, c := range got.Breakdown {
+				sum, _ = sum.Add(c.Amount)
+			}
+			if sum != got.EstimatedAmount {
+				t.Fatalf("breakdown sums to %v, want %v", sum, got.EstimatedAmount)
+			}
+		})
+	}
+
+	far, err := Price(book, Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: gazipur, At: noon})
+	if err != nil {
+		t.Fatalf("price: %v", err)
+	}
+	if far.EstimatedAmount.Amount() <= 50000 || far.Breakdown[1].Code != "DISTANCE" {
+		t.Fatalf("expected distance component, got %+v", far)
+	}
+
+	if _, err := Price(book, Quote{Type: "PLANNED", Category: "GENERAL_HELP", At: noon, PromoCode: "NOPE"}); !errors.Is(err, ErrUnknownPromoCode) {
+		t.Fatalf("expected ErrUnknownPromoCode, got %v", err)
+	}
+}
+
+func TestEngineSelectsPriceBookByEffectiveDate(t *testing.T) {
+	launch := DefaultPriceBook()
+	revised := DefaultPriceBook()
+	revised.Version = "2025-06"
+	revised.EffectiveFrom = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	revised.DefaultBaseFee = money.New(55000, "BDT")
+
+	centre := models.RequestLocation{Latitude: 23.8103, Longitude: 90.4125, Address: "Dhaka"}
+	engine, err := NewEngine(revised, launch)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	if _, err := engine.Publish(revised); err == nil {
+		t.Fatalf("expected duplicate version to be rejected")
+	}
+
+	before, _ := engine.Quote(Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: centre, At: revised.EffectiveFrom.Add(-time.Hour)})
+	after, _ := engine.Quote(Quote{Type: "PLANNED", Category: "GENERAL_HELP", Location: centre, At: revised.EffectiveFrom.Add(6 * time.Hour)})
+	if before.PriceBookVersion != "2025-01" || after.PriceBookVersion != "2025-06" {
+		t.Fatalf("unexpected versions %s / %s", before.PriceBookVersion, after.PriceBookVersion)
+	}
+	if after.EstimatedAmount != money.New(55000, "BDT") {
+		t.Fatalf("expected revised base fee, got %v", after.EstimatedAmount)
+	}
+}
+
+func TestPublishBindsAmountsToCurrency(t *testing.T) {
+	var book models.PriceBook
+	if err := json.Unmarshal([]byte(`{"version":"2025-07","currency":"BDT","defaultBaseFee":450.5,"baseFees":{"TUTORING":800},"perKmFee":12.25,"discounts":[{"code":"WELCOME","amount":50}]}`), &book); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	engine := &Engine{}
+	published, err := engine.Publish(book)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if published.DefaultBaseFee != money.New(45050, "BDT") || published.BaseFees["TUTORING"] != money.New(80000, "BDT") ||
+		published.PerKmFee != money.New(1225, "BDT") || published.Discounts[0].Amount != money.New(5000, "BDT") {
+		t.Fatalf("expected amounts bound to BDT, got %+v", published)
+	}
+	if book.BaseFees["TUTORING"].Currency() != "" {
+		t.Fatalf("expected the caller's book to be left alone")
+	}
+
+	book.Version = "2025-08"
+	book.PerKmFee, _ = money.Parse("12.255", "")
+	if _, err := engine.Publish(book); !errors.Is(err, money.ErrPrecision) {
+		t.Fatalf("expected ErrPrecision for sub-paisa amounts, got %v", err)
+	}
+}
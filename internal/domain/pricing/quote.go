@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+var errQuoteMalformed = errors.New("quote id is malformed or has been tampered with")
+
+// QuoteClaims is everything a quote ID vouches for.
+type QuoteClaims struct {
+	UserID      string         `json:"uid"`
+	Fingerprint string         `json:"fp"`
+	ExpiresAt   time.Time      `json:"exp"`
+	Pricing     models.Pricing `json:"pricing"`
+}
+
+// QuoteSigner turns quote claims into a self-contained, HMAC-signed quote
+// ID so quotes survive restarts and can be honoured by any instance.
+type QuoteSigner struct {
+	secret []byte
+}
+
+func NewQuoteSigner(secret []byte) *QuoteSigner {
+	return &QuoteSigner{secret: secret}
+}
+
+func (s *QuoteSigner) Sign(claims QuoteClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode quote: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Parse verifies the signature and decodes the claims. Expiry and ownership
+// are left to the caller.
+func (s *QuoteSigner) Parse(id string) (QuoteClaims, error) {
+	encoded, signature, ok := strings.Cut(id, ".")
+	if !ok {
+		return QuoteClaims{}, errQuoteMalformed
+	}
+
+	given, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(given, s.mac(encoded)) {
+		return QuoteClaims{}, errQuoteMalformed
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return QuoteClaims{}, errQuoteMalformed
+	}
+
+	var claims QuoteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return QuoteClaims{}, errQuoteMalformed
+	}
+	if claims.Pricing, err = bindPricing(claims.Pricing); err != nil {
+		return QuoteClaims{}, errQuoteMalformed
+	}
+	return claims, nil
+}
+
+// bindPricing attaches the pricing's currency to its decoded amounts, which
+// carry only the number in JSON.
+func bindPricing(pricing models.Pricing) (models.Pricing, error) {
+	var err error
+	bind := func(amount money.Money) money.Money {
+		bound, bindErr := amount.In(pricing.Currency)
+		if err == nil {
+			err = bindErr
+		}
+		return bound
+	}
+
+	pricing.EstimatedAmount = bind(pricing.EstimatedAmount)
+	pricing.PlatformFee = bind(pricing.PlatformFee)
+	pricing.Subsidy = bind(pricing.Subsidy)
+	pricing.Breakdown = append([]models.PriceComponent(nil), pricing.Breakdown...)
+	for i := range pricing.Breakdown {
+		pricing.Breakdown[i].Amount = bind(pricing.Breakdown[i].Amount)
+	}
+	return pricing, err
+}
+
+func (s *QuoteSigner) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
+
+// Fingerprint summarises the request fields that affect the price, so a
+// quote cannot be redeemed for a different request.
+func Fingerprint(input models.CreateHelpRequestInput) string {
+	scheduled := ""
+	if input.ScheduledFor != nil {
+		scheduled = input.ScheduledFor.UTC().Format(time.RFC3339)
+	}
+
+	var location models.RequestLocation
+	if input.Location != nil {
+		location = *input.Location
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%.6f|%.6f|%s|%s",
+		input.Type,
+		input.Category,
+		location.Latitude,
+		location.Longitude,
+		scheduled,
+		strings.ToUpper(input.PromoCode),
+	)
+	return hex.EncodeToString(h.Sum(nil))
+}
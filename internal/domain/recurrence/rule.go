@@ -0,0 +1,169 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods bounds how many periods are walked when expanding a rule so a
+// malformed or very long rule cannot loop forever.
+const maxPeriods = 5000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is the subset of RFC 5545 RRULE we support: FREQ (DAILY, WEEKLY or
+// MONTHLY), INTERVAL, BYDAY (weekly rules only), COUNT and UNTIL.
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// A leading "RRULE:" is accepted.
+func Parse(raw string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "RRULE:")
+	if raw == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	for _, part := range strings.Split(raw, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdays[strings.ToUpper(code)]
+				if !ok {
+					return Rule{}, fmt.Errorf("%w: unknown BYDAY %q", ErrInvalidRule, code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "MONTHLY":
+		if len(rule.ByDay) > 0 {
+			return Rule{}, fmt.Errorf("%w: BYDAY is only supported for WEEKLY rules", ErrInvalidRule)
+		}
+	case "WEEKLY":
+	default:
+		return Rule{}, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102", time.RFC3339} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must be a date or UTC date-time", ErrInvalidRule)
+}
+
+// Between returns the occurrences of the rule anchored at start that fall
+// after `after` and no later than `before`, in order. Occurrences keep the
+// wall-clock time of start in its location, and COUNT counts from start.
+func (r Rule) Between(start, after, before time.Time) []time.Time {
+	var out []time.Time
+	seen := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, occurrence := range r.period(start, period) {
+			if occurrence.Before(start) {
+				continue
+			}
+			if occurrence.After(before) || (r.Until != nil && occurrence.After(*r.Until)) {
+				return out
+			}
+			seen++
+			if r.Count > 0 && seen > r.Count {
+				return out
+			}
+			if occurrence.After(after) {
+				out = append(out, occurrence)
+			}
+		}
+	}
+	return out
+}
+
+// period returns the candidate occurrences in the n-th period of the rule.
+func (r Rule) period(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+	switch r.Freq {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, step)}
+	case "MONTHLY":
+		// Months without the start's day of month are skipped, as in RFC
+		// 5545, rather than rolling over into the next month.
+		candidate := start.AddDate(0, step, 0)
+		if candidate.Day() != start.Day() {
+			return nil
+		}
+		return []time.Time{candidate}
+	default:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// Weeks start on Monday.
+		offset := (int(start.Weekday()) + 6) % 7
+		weekStart := start.AddDate(0, 0, 7*step-offset)
+
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			candidates = append(candidates, weekStart.AddDate(0, 0, (int(day)+6)%7))
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		return candidates
+	}
+}
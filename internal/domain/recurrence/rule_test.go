@@ -0,0 +1,60 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBetween(t *testing.T) {
+	dhaka := time.FixedZone("Asia/Dhaka", 6*60*60)
+	// Wednesday 10:00 in Dhaka.
+	start := time.Date(2025, 3, 5, 10, 0, 0, 0, dhaka)
+	far := start.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  []string
+	}{
+		{"weekly on start day", "FREQ=WEEKLY;COUNT=3", start.Add(-time.Second), []string{"2025-03-05", "2025-03-12", "2025-03-19"}},
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=4", start.Add(-time.Second), []string{"2025-03-05", "2025-03-07", "2025-03-10", "2025-03-12"}},
+		{"fortnightly until", "FREQ=WEEKLY;INTERVAL=2;UNTIL=20250405", start.Add(-time.Second), []string{"2025-03-05", "2025-03-19", "2025-04-02"}},
+		{"daily after", "RRULE:FREQ=DAILY;COUNT=5", start.AddDate(0, 0, 2), []string{"2025-03-08", "2025-03-09"}},
+		{"monthly skips short months", "FREQ=MONTHLY;COUNT=3", time.Time{}, []string{"2025-03-05", "2025-04-05", "2025-05-05"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			got := rule.Between(start, tt.after, far)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i, occurrence := range got {
+				if occurrence.Format("2006-01-02") != tt.want[i] || occurrence.Hour() != 10 {
+					t.Fatalf("occurrence %d = %v, want %s 10:00", i, occurrence, tt.want[i])
+				}
+			}
+		})
+	}
+
+	monthEnd := time.Date(2025, 1, 31, 9, 0, 0, 0, dhaka)
+	rule, _ := Parse("FREQ=MONTHLY;COUNT=2")
+	got := rule.Between(monthEnd, time.Time{}, far)
+	if len(got) != 2 || got[1].Month() != time.March {
+		t.Fatalf("expected February to be skipped, got %v", got)
+	}
+}
+
+func TestParseRejectsUnsupportedRules(t *testing.T) {
+	for _, raw := range []string{"", "FREQ=YEARLY", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;INTERVAL=0", "FREQ=WEEKLY;COUNT=2;UNTIL=20250101", "FREQ=WEEKLY;BYHOUR=9"} {
+		if _, err := Parse(raw); !errors.Is(err, ErrInvalidRule) {
+			t.Fatalf("Parse(%q) = %v, want ErrInvalidRule", raw, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package reports formats financial reports as CSV and reconciles the
+// ledger's gateway movements against the gateway's settlement records.
+// Amounts are integer minor units internally and decimal major units in
+// CSV files.
+package reports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+)
+
+var (
+	ErrUnknownReport     = errors.New("unknown report")
+	ErrInvalidSettlement = errors.New("invalid settlement file")
+)
+
+// Report kinds served for a date range.
+const (
+	KindTransactions   = "transactions"
+	KindFees           = "fees"
+	KindPayouts        = "payouts"
+	KindRefunds        = "refunds"
+	KindDisputes       = "disputes"
+	KindReconciliation = "reconciliation"
+)
+
+// Settlement record types.
+const (
+	TypeCharge = "CHARGE"
+	TypeRefund = "REFUND"
+	TypePayout = "PAYOUT"
+)
+
+// Reconciliation statuses.
+const (
+	StatusMatched          = "MATCHED"
+	StatusAmountMismatch   = "AMOUNT_MISMATCH"
+	StatusCurrencyMismatch = "CURRENCY_MISMATCH"
+	// StatusMissingSettlement is a ledger movement the gateway has not
+	// settled.
+	StatusMissingSettlement = "MISSING_IN_SETTLEMENT"
+	// StatusMissingLedger is a settled movement with no ledger entry.
+	StatusMissingLedger = "MISSING_IN_LEDGER"
+)
+
+// WriteCSV writes the report's header and rows.
+func WriteCSV(w io.Writer, report models.Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(report.Header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(report.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// FormatAmount renders minor units of currency as a decimal with all of
+// the currency's minor digits, e.g. 123450 BDT as "1234.50".
+func FormatAmount(minor int64, currency string) string {
+	return money.New(minor, currency).Decimal()
+}
+
+// ParseAmount parses a decimal in major units of currency into minor units
+// without going through floating point.
+func ParseAmount(value, currency string) (int64, error) {
+	amount, err := money.Parse(value, currency)
+	if err != nil {
+		return 0, err
+	}
+	return amount.Amount(), nil
+}
+
+// ParseSettlements reads a gateway settlement export. The first row names
+// the columns: type, gateway_ref, amount and currency are required;
+// reference and settled_at (RFC 3339 or a date) are optional. Column order
+// and case do not matter.
+func ParseSettlements(r io.Reader) ([]models.SettlementRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidSettlement)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSettlement, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"type", "gateway_ref", "amount", "currency"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidSettlement, name)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	records := []models.SettlementRecord{}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSettlement, err)
+		}
+
+		record := models.SettlementRecord{
+			Type:       strings.ToUpper(field(row, "type")),
+			GatewayRef: field(row, "gateway_ref"),
+			Reference:  field(row, "reference"),
+			Currency:   strings.ToUpper(field(row, "currency")),
+		}
+		switch record.Type {
+		case TypeCharge, TypeRefund, TypePayout:
+		default:
+			return nil, fmt.Errorf("%w: line %d: unknown type %q", ErrInvalidSettlement, line, record.Type)
+		}
+		if record.GatewayRef == "" || record.Currency == "" {
+			return nil, fmt.Errorf("%w: line %d: gateway_ref and currency are required", ErrInvalidSettlement, line)
+		}
+		if record.Amount, err = ParseAmount(field(row, "amount"), record.Currency); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSettlement, line, err)
+		}
+		if settled := field(row, "settled_at"); settled != "" {
+			if record.SettledAt, err = parseTime(settled); err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid settled_at %q", ErrInvalidSettlement, line, settled)
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+type settlementKey struct {
+	typ string
+	ref string
+}
+
+type settlementTotal struct {
+	key       settlementKey
+	reference string
+	currency  string
+	amount    int64
+}
+
+// Reconcile compares the ledger's expected gateway movements with the
+// settled ones. Records are matched on type and gateway reference, summing
+// repeats, and each pair becomes one row: ledger movements in order, then
+// settled movements the ledger does not know about.
+func Reconcile(expected, settled []models.SettlementRecord) models.Report {
+	report := models.Report{
+		Name:   KindReconciliation,
+		Header: []string{"type", "gateway_ref", "reference", "currency", "ledger_amount", "settled_amount", "difference", "status"},
+		Rows:   [][]string{},
+	}
+
+	ledgerTotals := totalSettlements(expected)
+	settledTotals := totalSettlements(settled)
+	settledByKey := make(map[settlementKey]*settlementTotal, len(settledTotals))
+	for _, total := range settledTotals {
+		settledByKey[total.key] = total
+	}
+
+	for _, want := range ledgerTotals {
+		got, ok := settledByKey[want.key]
+		row := []string{want.key.typ, want.key.ref, want.reference, want.currency, FormatAmount(want.amount, want.currency)}
+		switch {
+		case !ok:
+			row = append(row, "", FormatAmount(-want.amount, want.currency), StatusMissingSettlement)
+		case got.currency != want.currency:
+			row = append(row, money.New(got.amount, got.currency).String(), "", StatusCurrencyMismatch)
+		case got.amount != want.amount:
+			row = append(row, FormatAmount(got.amount, got.currency), FormatAmount(got.amount-want.amount, want.currency), StatusAmountMismatch)
+		default:
+			row = append(row, FormatAmount(got.amount, got.currency), FormatAmount(0, want.currency), StatusMatched)
+		}
+		delete(settledByKey, want.key)
+		report.Rows = append(report.Rows, row)
+	}
+	for _, got := range settledTotals {
+		if _, ok := settledByKey[got.key]; !ok {
+			continue
+		}
+		report.Rows = append(report.Rows, []string{
+			got.key.typ, got.key.ref, got.reference, got.currency,
+			"", FormatAmount(got.amount, got.currency), FormatAmount(got.amount, got.currency), StatusMissingLedger,
+		})
+	}
+	return report
+}
+
+// totalSettlements sums records sharing a type and gateway reference,
+// keeping first-seen order.
+func totalSettlements(records []models.SettlementRecord) []*settlementTotal {
+	totals := []*settlementTotal{}
+	byKey := make(map[settlementKey]*settlementTotal)
+	for _, record := range records {
+		key := settlementKey{typ: record.Type, ref: record.GatewayRef}
+		total, ok := byKey[key]
+		if !ok {
+			total = &settlementTotal{key: key, reference: record.Reference, currency: record.Currency}
+			byKey[key] = total
+			totals = append(totals, total)
+		}
+		total.amount += record.Amount
+	}
+	return totals
+}
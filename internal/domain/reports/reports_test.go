@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+func TestAmounts(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		currency string
+		minor    int64
+	}{
+		{"0.00", "BDT", 0},
+		{"1234.50", "BDT", 123450},
+		{"-0.05", "BDT", -5},
+		{"7.00", "USD", 700},
+		{"1500", "JPY", 1500},
+		{"1.234", "KWD", 1234},
+	} {
+		if got := FormatAmount(tc.minor, tc.currency); got != tc.text {
+			t.Fatalf("FormatAmount(%d, %s) = %q, want %q", tc.minor, tc.currency, got, tc.text)
+		}
+		if got, err := ParseAmount(tc.text, tc.currency); err != nil || got != tc.minor {
+			t.Fatalf("ParseAmount(%q, %s) = %d, %v, want %d", tc.text, tc.currency, got, err, tc.minor)
+		}
+	}
+	if got, _ := ParseAmount("12.5", "BDT"); got != 1250 {
+		t.Fatalf("expected one fraction digit to be tenths, got %d", got)
+	}
+	for _, bad := range []string{"", "1.234", "abc", "1.-5", "--1", "+1"} {
+		if _, err := ParseAmount(bad, "BDT"); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestParseSettlements(t *testing.T) {
+	file := "Currency,Type,Gateway_Ref,Amount,Settled_At\n" +
+		"bdt,charge,pi_1,500.00,2025-02-17\n" +
+		"BDT,PAYOUT,po_1,450,2025-02-17T10:00:00Z\n"
+	records, err := ParseSettlements(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(records) != 2 || records[0].Type != TypeCharge || records[0].Currency != "BDT" || records[0].Amount != 50000 || records[1].Amount != 45000 {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	if records[1].SettledAt.Hour() != 10 {
+		t.Fatalf("expected settled_at to be parsed, got %v", records[1].SettledAt)
+	}
+
+	for _, bad := range []string{
+		"",
+		"type,amount,currency\nCHARGE,1,BDT\n",
+		"type,gateway_ref,amount,currency\nFEE,x,1,BDT\n",
+		"type,gateway_ref,amount,currency\nCHARGE,x,1.001,BDT\n",
+	} {
+		if _, err := ParseSettlements(strings.NewReader(bad)); !errors.Is(err, ErrInvalidSettlement) {
+			t.Fatalf("expected ErrInvalidSettlement for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	expected := []models.SettlementRecord{
+		{Type: TypeCharge, GatewayRef: "pi_1", Reference: "pay-1", Amount: 50000, Currency: "BDT"},
+		{Type: TypeCharge, GatewayRef: "pi_2", Reference: "pay-2", Amount: 30000, Currency: "BDT"},
+		{Type: TypeRefund, GatewayRef: "pi_2", Reference: "pay-2", Amount: 30000, Currency: "BDT"},
+		{Type: TypePayout, GatewayRef: "po_1", Reference: "po-1", Amount: 45000, Currency: "BDT"},
+	}
+	settled := []models.SettlementRecord{
+		{Type: TypeCharge, GatewayRef: "pi_1", Amount: 50000, Currency: "BDT"},
+		{Type: TypeRefund, GatewayRef: "pi_2", Amount: 10000, Currency: "BDT"},
+		{Type: TypeRefund, GatewayRef: "pi_2", Amount: 10000, Currency: "BDT"},
+		{Type: TypePayout, GatewayRef: "po_1", Amount: 45000, Currency: "USD"},
+		{Type: TypeCharge, GatewayRef: "pi_9", Amount: 100, Currency: "BDT"},
+	}
+
+	report := Reconcile(expected, settled)
+	statuses := make([]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		statuses = append(statuses, row[1]+":"+row[len(row)-1])
+	}
+	want := "pi_1:MATCHED pi_2:MISSING_IN_SETTLEMENT pi_2:AMOUNT_MISMATCH po_1:CURRENCY_MISMATCH pi_9:MISSING_IN_LEDGER"
+	if got := strings.Join(statuses, " "); got != want {
+		t.Fatalf("unexpected reconciliation:\n got %s\nwant %s", got, want)
+	}
+	if diff := report.Rows[2][6]; diff != "-100.00" {
+		t.Fatalf("expected summed refunds 100.00 short, got %q", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || lines[0] != "type,gateway_ref,reference,currency,ledger_amount,settled_amount,difference,status" {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,22 @@
+package services
+
+import "errors"
+
+// Sentinel errors shared by service implementations. Implementations wrap
+// them with context (fmt.Errorf("%w: ...")) so handlers can map them to
+// stable API error codes with errors.Is.
+var (
+	ErrHelperNotEligible    = errors.New("helper not eligible")
+	ErrSignatureInvalid     = errors.New("invalid or expired signature")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrPayloadTooLarge      = errors.New("payload too large")
+	ErrQuoteInvalid         = errors.New("invalid quote")
+	ErrQuoteExpired         = errors.New("quote expired")
+	ErrInvalidState         = errors.New("invalid state")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrPaymentDeclined      = errors.New("payment declined")
+	ErrLimitExceeded        = errors.New("limit exceeded")
+	ErrDisputeAlreadyOpen   = errors.New("dispute already open")
+	ErrIdempotencyMismatch  = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyInFlight  = errors.New("request with this idempotency key is still in progress")
+)
@@ -0,0 +1,281 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/cancellation"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+func (s *Store) WithCancellationPolicy(policy cancellation.Policy) *Store {
+	s.cancellationPolicy = policy
+	return s
+}
+
+// Withdraw lets a helper back out of a match they accepted before the work
+// starts. The helper loses reliability according to the cancellation policy,
+// the request goes back to matching without them, and the seeker is told.
+func (s *Store) Withdraw(ctx context.Context, helperID, matchID string, input models.WithdrawMatchInput) (*models.MatchSession, error) {
+	match, refund, err := s.planWithdraw(helperID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sendRefund(ctx, refund)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseRequestLocked(match.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if refund != nil {
+		if err := s.applyRefundLocked(refund); err != nil {
+			return nil, err
+		}
+	}
+
+	req, ok := s.requests[match.RequestID]
+	if !ok {
+		return nil, errRequestNotFound
+	}
+
+	now := s.now()
+	stage := cancellation.StageForMatch(match.Status)
+	match.Status = "WITHDRAWN"
+	match.DeclineReason = input.Reason
+	match.WithdrawnAt = &now
+
+	profile := s.ensureHelperProfile(helperID)
+	profile.Withdrawals++
+	profile.ReliabilityScore -= s.cancellationPolicy.ReliabilityPenalty(stage, input.Reason)
+	if profile.ReliabilityScore < 0 {
+		profile.ReliabilityScore = 0
+	}
+	profile.UpdatedAt = now
+
+	// The withdrawing helper already holds an invitation for the request, so
+	// dispatching again reaches everyone else who is now eligible.
+	req.Status = "SUBMITTED"
+	req.SLA.MatchDeadline = s.slaLocked(req).MatchDeadline
+	req.ExpiresAt = s.expiryLocked(req)
+	req.UpdatedAt = now
+	s.dispatchLocked(req)
+
+	s.notifyLocked(req.RequesterID, "HELPER_WITHDREW", "Your helper withdrew",
+		"Your helper can no longer make it. We are finding you another helper.",
+		map[string]string{"requestId": req.ID, "reason": input.Reason})
+
+	copyMatch := *match
+	return &copyMatch, nil
+}
+
+// planWithdraw checks the helper may withdraw from the match, reserves its
+// request and plans the refund of the seeker's payment, if any.
+func (s *Store) planWithdraw(helperID, matchID string) (*models.MatchSession, *refundPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match, ok := s.matches[matchID]
+	if !ok || match.HelperID != helperID {
+		return nil, nil, errMatchNotFound
+	}
+	switch match.Status {
+	case "ACCEPTED", "EN_ROUTE", "ARRIVED":
+	default:
+		return nil, nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+	if _, ok := s.requests[match.RequestID]; !ok {
+		return nil, nil, errRequestNotFound
+	}
+
+	var refund *refundPlan
+	if payment := s.paymentForMatchLocked(match.ID); payment != nil && paymentOpen(payment.Status) {
+		plan, err := s.planRefundLocked(payment, money.Zero(payment.Currency), "Helper withdrew")
+		if err != nil {
+			return nil, nil, err
+		}
+		refund = plan
+	}
+	if err := s.reserveRequestLocked(match.RequestID); err != nil {
+		return nil, nil, err
+	}
+	return match, refund, nil
+}
+
+// CancelMatch lets the helper on an active match cancel the job. The request
+// is cancelled rather than returned to matching; the seeker only pays when
+// the policy puts the fault on them, such as a no-show after arrival.
+func (s *Store) CancelMatch(ctx context.Context, helperID, matchID string, input models.HelperCancelInput) (*models.HelpRequest, error) {
+	plan, err := s.planHelperCancel(helperID, matchID, input)
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, plan)
+}
+
+func (s *Store) planHelperCancel(helperID, matchID string, input models.HelperCancelInput) (*cancelPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match, ok := s.matches[matchID]
+	if !ok || match.HelperID != helperID {
+		return nil, errMatchNotFound
+	}
+	if !matchActive(match.Status) {
+		return nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+
+	req, ok := s.requests[match.RequestID]
+	if !ok {
+		return nil, errRequestNotFound
+	}
+	return s.planCancelLocked(req, "HELPER", input.Reason, input.Details)
+}
+
+// cancelPlan is a cancellation worked out under s.mu. Its request stays
+// reserved until the cancellation is applied or abandoned.
+type cancelPlan struct {
+	req       *models.HelpRequest
+	initiator string
+	reason    string
+	details   string
+	stage     string
+	decision  cancellation.Decision
+	active    *models.MatchSession
+	refund    *refundPlan
+}
+
+// planCancelLocked applies the cancellation policy to the request, plans the
+// refund of its open payment less any fee the seeker owes, and reserves it.
+func (s *Store) planCancelLocked(req *models.HelpRequest, initiator, reason, details string) (*cancelPlan, error) {
+	switch req.Status {
+	case "CANCELLED", "COMPLETED", "EXPIRED":
+		return nil, fmt.Errorf("%w: request already %s", services.ErrInvalidState, req.Status)
+	}
+
+	plan := &cancelPlan{
+		req:       req,
+		initiator: initiator,
+		reason:    reason,
+		details:   details,
+		stage:     cancellation.StageUnmatched,
+		active:    s.activeMatchLocked(req.ID),
+	}
+	var input cancellation.Input
+	if plan.active != nil {
+		plan.stage = cancellation.StageForMatch(plan.active.Status)
+		input.AcceptedAt = plan.active.AcceptedAt
+	}
+	input.Initiator = initiator
+	input.Reason = reason
+	input.Stage = plan.stage
+	input.Now = s.now()
+	input.Amount = req.Pricing.EstimatedAmount
+	plan.decision = s.cancellationPolicy.Evaluate(input)
+
+	if payment := s.latestPaymentLocked(req.ID); payment != nil && paymentOpen(payment.Status) {
+		fee := money.Zero(req.Pricing.Currency)
+		if plan.decision.Payer == "SEEKER" {
+			fee = plan.decision.Penalty
+		}
+		refund, err := s.planRefundLocked(payment, fee, "Request cancelled")
+		if err != nil {
+			return nil, err
+		}
+		plan.refund = refund
+	}
+	if err := s.reserveRequestLocked(req.ID); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// cancel sends a planned cancellation's refund and applies it. It is called
+// without s.mu.
+func (s *Store) cancel(ctx context.Context, plan *cancelPlan) (*models.HelpRequest, error) {
+	err := s.sendRefund(ctx, plan.refund)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseRequestLocked(plan.req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCancelLocked(plan); err != nil {
+		return nil, err
+	}
+
+	copyReq := *plan.req
+	return &copyReq, nil
+}
+
+// applyCancelLocked records a cancellation whose refund the gateway
+// accepted, closes the request's matches and tells the other party.
+func (s *Store) applyCancelLocked(plan *cancelPlan) error {
+	if plan.refund != nil {
+		if err := s.applyRefundLocked(plan.refund); err != nil {
+			return err
+		}
+	}
+
+	now := s.now()
+	req, decision := plan.req, plan.decision
+	req.Status = "CANCELLED"
+	req.Cancellation = &models.Cancellation{
+		Reason:         plan.reason,
+		Details:        plan.details,
+		Initiator:      plan.initiator,
+		Stage:          plan.stage,
+		Timestamp:      now,
+		PenaltyApplied: decision.Penalty.IsPositive(),
+		PenaltyRule:    decision.Rule,
+		PenaltyAmount:  decision.Penalty,
+		PenaltyPayer:   decision.Payer,
+		Currency:       req.Pricing.Currency,
+	}
+	req.UpdatedAt = now
+
+	for _, match := range s.matches {
+		if match.RequestID != req.ID {
+			continue
+		}
+		if match.Status == "INVITED" || matchActive(match.Status) {
+			match.Status = "CANCELLED"
+			match.RespondedAt = &now
+		}
+	}
+
+	data := map[string]string{"requestId": req.ID, "reason": plan.reason}
+	if plan.initiator == "HELPER" {
+		body := "Your helper cancelled the request."
+		if decision.Penalty.IsPositive() {
+			body = fmt.Sprintf("Your helper cancelled the request. A cancellation fee of %s applies.", decision.Penalty)
+		}
+		s.notifyLocked(req.RequesterID, "REQUEST_CANCELLED", "Request cancelled", body, data)
+	} else if plan.active != nil {
+		s.notifyLocked(plan.active.HelperID, "REQUEST_CANCELLED", "Request cancelled",
+			"The seeker cancelled the request you accepted.", data)
+	}
+	return nil
+}
+
+// activeMatchLocked returns the accepted match that is still in progress.
+func (s *Store) activeMatchLocked(requestID string) *models.MatchSession {
+	for _, match := range s.matches {
+		if match.RequestID == requestID && matchActive(match.Status) {
+			return match
+		}
+	}
+	return nil
+}
+
+func matchActive(status string) bool {
+	switch status {
+	case "ACCEPTED", "EN_ROUTE", "ARRIVED", "IN_PROGRESS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+var errTransactionNotFound = errors.New("transaction not found")
+
+// GetTransaction returns a payment and its dispute to the seeker, the
+// helper or an admin.
+func (s *Store) GetTransaction(_ context.Context, userID, transactionID string) (*models.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payment, ok := s.payments[transactionID]
+	if !ok {
+		return nil, errTransactionNotFound
+	}
+	if _, ok := s.disputeRoleLocked(payment, userID); !ok {
+		return nil, errTransactionNotFound
+	}
+	return s.transactionLocked(payment), nil
+}
+
+// OpenDispute freezes the payment's escrow for ops review. Either party may
+// dispute a payment held in escrow, once.
+func (s *Store) OpenDispute(_ context.Context, userID, transactionID string, input models.OpenDisputeInput) (*models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[transactionID]
+	if !ok || (payment.SeekerID != userID && payment.HelperID != userID) {
+		return nil, errTransactionNotFound
+	}
+	if _, err := s.openDisputeLocked(payment, userID, input); err != nil {
+		return nil, err
+	}
+	return s.transactionLocked(payment), nil
+}
+
+// PostDisputeMessage adds a message to an open dispute's thread and
+// notifies the other participants.
+func (s *Store) PostDisputeMessage(_ context.Context, userID, transactionID string, input models.DisputeMessageInput) (*models.Dispute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[transactionID]
+	if !ok {
+		return nil, errTransactionNotFound
+	}
+	role, ok := s.disputeRoleLocked(payment, userID)
+	if !ok {
+		return nil, errTransactionNotFound
+	}
+	dispute := s.disputeForPaymentLocked(payment.ID)
+	if dispute == nil || dispute.Status != "OPEN" {
+		return nil, fmt.Errorf("%w: no open dispute", services.ErrInvalidState)
+	}
+	if err := s.claimEvidenceLocked(userID, payment.RequestID, input.Attachments); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	dispute.Messages = append(dispute.Messages, models.DisputeMessage{
+		AuthorID:    userID,
+		AuthorRole:  role,
+		Body:        input.Body,
+		Attachments: append([]string(nil), input.Attachments...),
+		SentAt:      now,
+	})
+	dispute.UpdatedAt = now
+
+	data := map[string]string{"transactionId": payment.ID, "disputeId": dispute.ID}
+	for _, recipient := range []string{payment.SeekerID, payment.HelperID} {
+		if recipient != userID {
+			s.notifyLocked(recipient, "DISPUTE_MESSAGE", "New message on your dispute", input.Body, data)
+		}
+	}
+	if role != "OPS" {
+		s.notifyAdminsLocked("DISPUTE_MESSAGE", "New message on a dispute", input.Body, data)
+	}
+	return copyDispute(dispute), nil
+}
+
+// ResolveDispute settles a disputed payment. Refunds go back through the
+// gateway and any part the seeker does not get back is paid to the helper.
+func (s *Store) ResolveDispute(ctx context.Context, adminID, transactionID string, input models.ResolveDisputeInput) (*models.Transaction, error) {
+	payment, refund, err := s.planResolution(transactionID, input)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sendRefund(ctx, refund)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseRequestLocked(payment.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &models.DisputeResolution{
+		Outcome:    input.Outcome,
+		Notes:      input.Notes,
+		ResolvedBy: adminID,
+	}
+	if refund == nil {
+		if err := s.releasePaymentLocked(payment, "Dispute resolved: "+input.Outcome); err != nil {
+			return nil, err
+		}
+		resolution.HelperAmount = payment.HelperEarnings
+	} else {
+		if err := s.applyRefundLocked(refund); err != nil {
+			return nil, err
+		}
+		resolution.RefundedAmount = payment.RefundedAmount
+		resolution.HelperAmount = payment.PenaltyAmount
+	}
+
+	now := s.now()
+	resolution.ResolvedAt = now
+	dispute := s.disputeForPaymentLocked(payment.ID)
+	dispute.Resolution = resolution
+	dispute.Status = "RESOLVED"
+	dispute.UpdatedAt = now
+
+	body := fmt.Sprintf("Outcome: %s. %s refunded to the seeker, %s paid to the helper.",
+		input.Outcome, resolution.RefundedAmount, resolution.HelperAmount)
+	data := map[string]string{"transactionId": payment.ID, "disputeId": dispute.ID}
+	s.notifyLocked(payment.SeekerID, "DISPUTE_RESOLVED", "Dispute resolved", body, data)
+	s.notifyLocked(payment.HelperID, "DISPUTE_RESOLVED", "Dispute resolved", body, data)
+	return s.transactionLocked(payment), nil
+}
+
+// planResolution checks the payment has an open dispute, plans the refund
+// the outcome calls for, if any, and reserves the payment's request.
+func (s *Store) planResolution(transactionID string, input models.ResolveDisputeInput) (*models.Payment, *refundPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[transactionID]
+	if !ok {
+		return nil, nil, errTransactionNotFound
+	}
+	dispute := s.disputeForPaymentLocked(payment.ID)
+	if dispute == nil || dispute.Status != "OPEN" {
+		return nil, nil, fmt.Errorf("%w: no open dispute", services.ErrInvalidState)
+	}
+
+	var plan *refundPlan
+	if input.Outcome != "RELEASE" {
+		refund := payment.Amount
+		switch input.Outcome {
+		case "REFUND_PARTIAL":
+			var err error
+			if refund, err = input.RefundAmount.In(payment.Currency); err != nil {
+				return nil, nil, fmt.Errorf("refund amount: %w", err)
+			}
+			if !refund.IsPositive() {
+				return nil, nil, errors.New("refund amount must be positive")
+			}
+			if refund.Amount() >= payment.Amount.Amount() {
+				return nil, nil, fmt.Errorf("refund amount must be less than %s; use REFUND_FULL", payment.Amount)
+			}
+		case "SPLIT":
+			// Any odd minor unit goes to the seeker.
+			refund = payment.Amount.Split(2)[0]
+		}
+		helperShare, err := payment.Amount.Sub(refund)
+		if err != nil {
+			return nil, nil, err
+		}
+		if plan, err = s.planRefundLocked(payment, helperShare, "Dispute resolved: "+input.Outcome); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.reserveRequestLocked(payment.RequestID); err != nil {
+		return nil, nil, err
+	}
+	return payment, plan, nil
+}
+
+// openDisputeLocked records a dispute and moves the payment's funds to
+// frozen escrow.
+func (s *Store) openDisputeLocked(payment *models.Payment, userID string, input models.OpenDisputeInput) (*models.Dispute, error) {
+	if existing := s.disputeForPaymentLocked(payment.ID); existing != nil && existing.Status == "OPEN" {
+		return nil, fmt.Errorf("%w: dispute %s", services.ErrDisputeAlreadyOpen, existing.ID)
+	}
+	if payment.Status != "HELD" {
+		return nil, fmt.Errorf("%w: payment is %s", services.ErrInvalidState, payment.Status)
+	}
+	if err := s.checkUnreservedLocked(payment.RequestID); err != nil {
+		return nil, err
+	}
+	if err := s.claimEvidenceLocked(userID, payment.RequestID, input.Evidence); err != nil {
+		return nil, err
+	}
+
+	note := input.Details
+	if note == "" {
+		note = input.Reason
+	}
+	if err := s.transitionPaymentLocked(payment, "DISPUTED", note); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	dispute := &models.Dispute{
+		ID:            fmt.Sprintf("dsp-%d", s.nextDisputeID),
+		TransactionID: payment.ID,
+		RequestID:     payment.RequestID,
+		OpenedBy:      userID,
+		Reason:        input.Reason,
+		Details:       input.Details,
+		Status:        "OPEN",
+		Evidence:      append([]string(nil), input.Evidence...),
+		Messages:      []models.DisputeMessage{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	s.nextDisputeID++
+	s.disputes[dispute.ID] = dispute
+
+	data := map[string]string{"requestId": payment.RequestID, "paymentId": payment.ID, "disputeId": dispute.ID}
+	other := payment.HelperID
+	if userID == payment.HelperID {
+		other = payment.SeekerID
+	}
+	s.notifyLocked(other, "PAYMENT_DISPUTED", "Payment disputed",
+		"The payment for your job is on hold while our team reviews a dispute.", data)
+	s.notifyAdminsLocked("PAYMENT_DISPUTED", "Payment disputed",
+		fmt.Sprintf("Request %s: %s", payment.RequestID, note), data)
+	return dispute, nil
+}
+
+// claimEvidenceLocked attaches uploaded files to the disputed request so
+// both parties and ops can fetch them.
+func (s *Store) claimEvidenceLocked(userID, requestID string, keys []string) error {
+	if err := s.checkClaimableLocked(userID, "ATTACHMENT", keys); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := s.claimUploadLocked(userID, "ATTACHMENT", key, requestID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disputeRoleLocked returns the user's role in a payment's dispute: SEEKER,
+// HELPER or OPS for admins.
+func (s *Store) disputeRoleLocked(payment *models.Payment, userID string) (string, bool) {
+	switch userID {
+	case payment.SeekerID:
+		return "SEEKER", true
+	case payment.HelperID:
+		return "HELPER", true
+	}
+	if user, ok := s.users[userID]; ok && user.HasRole("ADMIN") {
+		return "OPS", true
+	}
+	return "", false
+}
+
+func (s *Store) disputeForPaymentLocked(paymentID string) *models.Dispute {
+	for _, dispute := range s.disputes {
+		if dispute.TransactionID == paymentID {
+			return dispute
+		}
+	}
+	return nil
+}
+
+func (s *Store) transactionLocked(payment *models.Payment) *models.Transaction {
+	transaction := &models.Transaction{Payment: *copyPayment(payment)}
+	if dispute := s.disputeForPaymentLocked(payment.ID); dispute != nil {
+		transaction.Dispute = copyDispute(dispute)
+	}
+	return transaction
+}
+
+func copyDispute(dispute *models.Dispute) *models.Dispute {
+	copied := *dispute
+	copied.Evidence = append([]string(nil), dispute.Evidence...)
+	copied.Messages = append([]models.DisputeMessage{}, dispute.Messages...)
+	if dispute.Resolution != nil {
+		resolution := *dispute.Resolution
+		copied.Resolution = &resolution
+	}
+	return &copied
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// Update edits a draft, or a submitted request no helper has accepted yet.
+// Changes to anything that affects the price re-price the request, replacing
+// any quoted price. Changes to the category or location of a submitted
+// request re-run matching, and changes to its type or schedule re-schedule
+// it. Every edit is appended to the request's history.
+func (s *Store) Update(_ context.Context, userID, requestID string, input models.UpdateHelpRequestInput) (*models.HelpRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := s.requests[requestID]
+	if !ok || req.RequesterID != userID {
+		return nil, errRequestNotFound
+	}
+	switch req.Status {
+	case "DRAFT", "SUBMITTED", "SCHEDULED":
+	default:
+		return nil, fmt.Errorf("%w: request is %s and can no longer be edited", services.ErrInvalidState, req.Status)
+	}
+	if s.activeMatchLocked(req.ID) != nil {
+		return nil, fmt.Errorf("%w: request already matched", services.ErrInvalidState)
+	}
+	if err := s.checkUnreservedLocked(req.ID); err != nil {
+		return nil, err
+	}
+
+	updated := *req
+	var changes []models.FieldChange
+	record := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, models.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	if input.Type != nil {
+		record("type", updated.Type, *input.Type)
+		updated.Type = *input.Type
+		if updated.Type == "URGENT" && input.ScheduledFor == nil && updated.ScheduledFor != nil {
+			record("scheduledFor", formatTime(updated.ScheduledFor), "")
+			updated.ScheduledFor = nil
+		}
+	}
+	if input.Category != nil {
+		record("category", updated.Category, *input.Category)
+		updated.Category = *input.Category
+	}
+	if input.Description != nil {
+		record("description", updated.Description, *input.Description)
+		updated.Description = *input.Description
+	}
+	if input.Location != nil {
+		record("location", formatLocation(updated.Location), formatLocation(*input.Location))
+		updated.Location = *input.Location
+	}
+	if input.ScheduledFor != nil {
+		record("scheduledFor", formatTime(updated.ScheduledFor), formatTime(input.ScheduledFor))
+		updated.ScheduledFor = input.ScheduledFor
+	}
+	if input.PromoCode != nil {
+		record("promoCode", updated.PromoCode, *input.PromoCode)
+		updated.PromoCode = *input.PromoCode
+	}
+
+	if len(changes) == 0 {
+		copyReq := *req
+		return &copyReq, nil
+	}
+
+	var repriced, rematch, rescheduled bool
+	for _, change := range changes {
+		switch change.Field {
+		case "category", "location":
+			repriced, rematch = true, true
+		case "type", "scheduledFor":
+			repriced, rescheduled = true, true
+		case "promoCode":
+			repriced = true
+		}
+	}
+
+	if rescheduled {
+		if err := s.validateScheduleLocked(updated.Type, updated.ScheduledFor); err != nil {
+			return nil, err
+		}
+	}
+
+	if repriced {
+		priceInput := requestInput(&updated)
+		priceInput.QuoteID = ""
+		quote, err := s.priceLocked(priceInput)
+		if err != nil {
+			return nil, err
+		}
+		updated.Pricing = quote
+	}
+
+	now := s.now()
+	updated.UpdatedAt = now
+	updated.History = append(append([]models.RequestEdit{}, req.History...), models.RequestEdit{EditedAt: now, Changes: changes})
+	*req = updated
+
+	switch {
+	case req.Status == "DRAFT":
+	case rescheduled:
+		s.scheduleLocked(req)
+	case rematch && req.Status == "SUBMITTED":
+		req.SLA = s.slaLocked(req)
+		s.rematchLocked(req)
+	}
+
+	copyReq := *req
+	return &copyReq, nil
+}
+
+// Submit sends a draft for matching. A quoted price is honoured only while
+// the quote is still valid; once it has expired the draft is priced afresh.
+func (s *Store) Submit(_ context.Context, userID, requestID string) (*models.HelpRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := s.requests[requestID]
+	if !ok || req.RequesterID != userID {
+		return nil, errRequestNotFound
+	}
+	if req.Status != "DRAFT" {
+		return nil, fmt.Errorf("%w: request already %s", services.ErrInvalidState, req.Status)
+	}
+	if err := s.validateScheduleLocked(req.Type, req.ScheduledFor); err != nil {
+		return nil, err
+	}
+
+	input := requestInput(req)
+	quote, err := s.pricingForRequestLocked(userID, input)
+	if errors.Is(err, services.ErrQuoteExpired) {
+		input.QuoteID = ""
+		quote, err = s.priceLocked(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Pricing = quote
+	s.submitLocked(req)
+
+	copyReq := *req
+	return &copyReq, nil
+}
+
+// submitLocked starts the request's SLA clock and schedules its matching.
+// Callers must hold s.mu for writing.
+func (s *Store) submitLocked(req *models.HelpRequest) {
+	now := s.now()
+	req.SubmittedAt = &now
+	s.scheduleLocked(req)
+}
+
+// rematchLocked withdraws invitations from helpers no longer eligible for
+// the edited request and invites any newly eligible ones, including helpers
+// whose invitation an earlier edit withdrew.
+func (s *Store) rematchLocked(req *models.HelpRequest) {
+	now := s.now()
+	for _, match := range s.matches {
+		if match.RequestID != req.ID || (match.Status != "INVITED" && match.Status != "CANCELLED") {
+			continue
+		}
+		helper, ok := s.users[match.HelperID]
+		eligible := ok && s.helperEligibleLocked(helper, req)
+		switch {
+		case match.Status == "INVITED" && !eligible:
+			match.Status = "CANCELLED"
+			match.RespondedAt = &now
+		case match.Status == "CANCELLED" && eligible:
+			match.Status = "INVITED"
+			match.InvitedAt = now
+			match.RespondedAt = nil
+		}
+	}
+
+	s.dispatchLocked(req)
+}
+
+// requestInput rebuilds the creation input for a stored request so it can be
+// priced again.
+func requestInput(req *models.HelpRequest) models.CreateHelpRequestInput {
+	location := req.Location
+	return models.CreateHelpRequestInput{
+		Type:            req.Type,
+		Category:        req.Category,
+		Description:     req.Description,
+		Location:        &location,
+		ScheduledFor:    req.ScheduledFor,
+		Attachments:     req.Attachments,
+		PromoCode:       req.PromoCode,
+		PaymentMethodID: req.PaymentMethodID,
+		QuoteID:         req.Pricing.QuoteID,
+	}
+}
+
+func formatLocation(loc models.RequestLocation) string {
+	return fmt.Sprintf("%.6f,%.6f %s", loc.Latitude, loc.Longitude, loc.Address)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
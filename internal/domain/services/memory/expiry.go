@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+// SweepRequestDeadlines expires unmatched requests past their ExpiresAt,
+// records match deadline breaches, and flags accepted jobs that miss their
+// completion deadline for ops escalation.
+func (s *Store) SweepRequestDeadlines(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	changed := 0
+	for _, req := range s.requests {
+		if s.reserved[req.ID] {
+			continue
+		}
+		switch req.Status {
+		case "SUBMITTED", "SCHEDULED":
+			if req.Status == "SUBMITTED" && !now.Before(req.SLA.MatchDeadline) && !hasBreach(req, "MATCH_DEADLINE") {
+				s.recordBreachLocked(req, "MATCH_DEADLINE", req.SLA.MatchDeadline)
+				changed++
+			}
+			if req.ExpiresAt != nil && !now.Before(*req.ExpiresAt) {
+				s.expireLocked(req)
+				changed++
+			}
+		case "ACCEPTED":
+			if now.Before(req.SLA.CompletionDeadline) || hasBreach(req, "COMPLETION_DEADLINE") {
+				continue
+			}
+			s.recordBreachLocked(req, "COMPLETION_DEADLINE", req.SLA.CompletionDeadline)
+			if match := s.activeMatchLocked(req.ID); match != nil {
+				match.SLABreachedAt = &now
+				s.notifyAdminsLocked("SLA_BREACH", "Job missed its completion deadline",
+					fmt.Sprintf("Request %s with helper %s was due by %s.", req.ID, match.HelperID, req.SLA.CompletionDeadline.Format(time.RFC3339)),
+					map[string]string{"requestId": req.ID, "matchId": match.ID})
+			}
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// SLAMetrics summarises what the deadline sweeper has recorded.
+func (s *Store) SLAMetrics(_ context.Context) (*models.SLAMetrics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metrics := &models.SLAMetrics{Escalations: []models.SLAEscalation{}}
+	for _, req := range s.requests {
+		if req.Status == "EXPIRED" {
+			metrics.ExpiredRequests++
+		}
+		for _, breach := range req.SLABreaches {
+			switch breach.Kind {
+			case "MATCH_DEADLINE":
+				metrics.MatchDeadlineBreaches++
+			case "COMPLETION_DEADLINE":
+				metrics.CompletionDeadlineBreaches++
+			}
+		}
+	}
+
+	for _, match := range s.matches {
+		if match.SLABreachedAt == nil || !matchActive(match.Status) {
+			continue
+		}
+		escalation := models.SLAEscalation{
+			RequestID:  match.RequestID,
+			MatchID:    match.ID,
+			HelperID:   match.HelperID,
+			BreachedAt: *match.SLABreachedAt,
+		}
+		if req, ok := s.requests[match.RequestID]; ok {
+			escalation.Deadline = req.SLA.CompletionDeadline
+		}
+		metrics.Escalations = append(metrics.Escalations, escalation)
+	}
+	sort.Slice(metrics.Escalations, func(i, j int) bool {
+		return metrics.Escalations[i].BreachedAt.Before(metrics.Escalations[j].BreachedAt)
+	})
+	return metrics, nil
+}
+
+func (s *Store) expireLocked(req *models.HelpRequest) {
+	now := s.now()
+	req.Status = "EXPIRED"
+	req.UpdatedAt = now
+	for _, match := range s.matches {
+		if match.RequestID == req.ID && match.Status == "INVITED" {
+			match.Status = "EXPIRED"
+			match.RespondedAt = &now
+		}
+	}
+	s.notifyLocked(req.RequesterID, "REQUEST_EXPIRED", "Request expired",
+		"No helper was available for your request in time. You can repeat it to try again.",
+		map[string]string{"requestId": req.ID})
+}
+
+func (s *Store) recordBreachLocked(req *models.HelpRequest, kind string, deadline time.Time) {
+	req.SLABreaches = append(req.SLABreaches, models.SLABreach{Kind: kind, Deadline: deadline, DetectedAt: s.now()})
+}
+
+func (s *Store) notifyAdminsLocked(kind, title, body string, data map[string]string) {
+	for _, user := range s.users {
+		if user.HasRole("ADMIN") {
+			s.notifyLocked(user.ID, kind, title, body, data)
+		}
+	}
+}
+
+func hasBreach(req *models.HelpRequest, kind string) bool {
+	for _, breach := range req.SLABreaches {
+		if breach.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// defaultIdempotencyTTL is how long responses are kept for replay.
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// WithIdempotencyTTL sets how long a response is replayed to retries.
+func (s *Store) WithIdempotencyTTL(ttl time.Duration) *Store {
+	s.idempotencyTTL = ttl
+	return s
+}
+
+func (s *Store) BeginIdempotent(_ context.Context, scope models.IdempotencyScope, requestHash string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if record, ok := s.idempotency[scope]; ok && now.Before(record.ExpiresAt) {
+		if record.RequestHash != requestHash {
+			return nil, fmt.Errorf("%w: key %q", services.ErrIdempotencyMismatch, scope.Key)
+		}
+		if record.Response == nil {
+			return nil, fmt.Errorf("%w: key %q", services.ErrIdempotencyInFlight, scope.Key)
+		}
+		return copyIdempotencyRecord(record), nil
+	}
+
+	s.idempotency[scope] = &models.IdempotencyRecord{
+		Scope:       scope,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.idempotencyTTL),
+	}
+	return nil, nil
+}
+
+func (s *Store) CompleteIdempotent(_ context.Context, scope models.IdempotencyScope, response models.StoredResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.idempotency[scope]
+	if !ok {
+		return fmt.Errorf("idempotency key %q was not reserved", scope.Key)
+	}
+	response.Body = append([]byte(nil), response.Body...)
+	record.Response = &response
+	return nil
+}
+
+func (s *Store) AbandonIdempotent(_ context.Context, scope models.IdempotencyScope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.idempotency[scope]; ok && record.Response == nil {
+		delete(s.idempotency, scope)
+	}
+	return nil
+}
+
+// SweepIdempotencyKeys forgets keys whose TTL has passed.
+func (s *Store) SweepIdempotencyKeys(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	removed := 0
+	for scope, record := range s.idempotency {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.idempotency, scope)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func copyIdempotencyRecord(record *models.IdempotencyRecord) *models.IdempotencyRecord {
+	copied := *record
+	if record.Response != nil {
+		response := *record.Response
+		response.Body = append([]byte(nil), record.Response.Body...)
+		copied.Response = &response
+	}
+	return &copied
+}
@@ -0,0 +1,208 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+var errKYCDocumentNotFound = errors.New("kyc document not found")
+
+// KYCPolicy decides which requests an unverified helper may take. The zero
+// value places no restrictions.
+type KYCPolicy struct {
+	// RestrictPaid keeps unverified helpers away from requests that carry
+	// a price.
+	RestrictPaid bool
+	// RestrictedCategories lists request categories that always require a
+	// verified helper.
+	RestrictedCategories []string
+	// ExpiryReminders are the lead times before a document's expiry at which
+	// the helper is reminded to renew it.
+	ExpiryReminders []time.Duration
+}
+
+func (s *Store) WithKYCPolicy(policy KYCPolicy) *Store {
+	s.kycPolicy = policy
+	return s
+}
+
+// KYCReviewService implementation
+
+func (s *Store) ListKYCDocuments(_ context.Context, status string) ([]models.KYCDocument, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make([]models.KYCDocument, 0)
+	for _, doc := range s.kycDocuments {
+		if status != "" && doc.Status != status {
+			continue
+		}
+		docs = append(docs, *doc)
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		if !docs[i].SubmittedAt.Equal(docs[j].SubmittedAt) {
+			return docs[i].SubmittedAt.Before(docs[j].SubmittedAt)
+		}
+		return docs[i].ID < docs[j].ID
+	})
+	return docs, nil
+}
+
+func (s *Store) ReviewKYC(_ context.Context, reviewerID, documentID string, input models.KYCReviewInput) (*models.KYCDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.kycDocuments[documentID]
+	if !ok {
+		return nil, errKYCDocumentNotFound
+	}
+
+	if doc.Status != "PENDING" {
+		return nil, fmt.Errorf("%w: document already reviewed", services.ErrInvalidState)
+	}
+	if doc.UserID == reviewerID {
+		return nil, fmt.Errorf("%w: reviewers cannot review their own documents", services.ErrInvalidState)
+	}
+
+	now := s.now()
+	expiresAt := doc.ExpiresAt
+	if input.ExpiresAt != nil {
+		expiresAt = input.ExpiresAt
+	}
+	if input.Decision == "APPROVED" && expiresAt != nil && !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: cannot approve an expired document", services.ErrInvalidState)
+	}
+
+	doc.ExpiresAt = expiresAt
+	doc.Status = input.Decision
+	doc.ReviewerID = reviewerID
+	doc.ReviewedAt = &now
+	doc.Notes = input.Notes
+
+	s.refreshKYCStatusLocked(doc.UserID)
+
+	copyDoc := *doc
+	return &copyDoc, nil
+}
+
+// SweepKYCExpiry expires approved documents past their expiry date and
+// reminds helpers whose documents are about to expire, once per configured
+// lead time.
+func (s *Store) SweepKYCExpiry(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	leads := append([]time.Duration{}, s.kycPolicy.ExpiryReminders...)
+	sort.Slice(leads, func(i, j int) bool { return leads[i] < leads[j] })
+
+	changed := 0
+	for _, doc := range s.kycDocuments {
+		if doc.Status != "APPROVED" || doc.ExpiresAt == nil {
+			continue
+		}
+
+		if !doc.ExpiresAt.After(now) {
+			doc.Status = "EXPIRED"
+			doc.ExpiringSoon = false
+			delete(s.kycReminders, doc.ID)
+			s.refreshKYCStatusLocked(doc.UserID)
+			s.notifyLocked(doc.UserID, "KYC_EXPIRED", "Verification document expired",
+				fmt.Sprintf("Your %s expired on %s. Upload a renewed document to keep helping.", doc.DocumentType, doc.ExpiresAt.Format("2006-01-02")),
+				map[string]string{"documentId": doc.ID})
+			changed++
+			continue
+		}
+
+		// Only the tightest lead time already reached is sent, so a sweep
+		// that runs late does not deliver a burst of stale reminders.
+		remaining := doc.ExpiresAt.Sub(now)
+		for _, lead := range leads {
+			if remaining > lead {
+				continue
+			}
+			if sent, ok := s.kycReminders[doc.ID]; ok && sent <= lead {
+				break
+			}
+			s.kycReminders[doc.ID] = lead
+			doc.ExpiringSoon = true
+			s.notifyLocked(doc.UserID, "KYC_EXPIRING", "Verification document expiring soon",
+				fmt.Sprintf("Your %s expires on %s. Renew it to avoid interruptions.", doc.DocumentType, doc.ExpiresAt.Format("2006-01-02")),
+				map[string]string{"documentId": doc.ID})
+			changed++
+			break
+		}
+	}
+
+	return changed, nil
+}
+
+// refreshKYCStatusLocked derives the user's KYC status from their document
+// outcomes: any approved document verifies the user, otherwise a document
+// still under review keeps them pending, an expired one requires
+// re-verification, and only rejections mark them rejected.
+func (s *Store) refreshKYCStatusLocked(userID string) {
+	user, ok := s.users[userID]
+	if !ok {
+		return
+	}
+
+	var approved, pending, expired, rejected bool
+	for _, doc := range s.kycDocuments {
+		if doc.UserID != userID {
+			continue
+		}
+		switch doc.Status {
+		case "APPROVED":
+			approved = true
+		case "PENDING":
+			pending = true
+		case "EXPIRED":
+			expired = true
+		case "REJECTED":
+			rejected = true
+		}
+	}
+
+	status := "PENDING"
+	switch {
+	case approved:
+		status = "VERIFIED"
+	case pending:
+		status = "PENDING"
+	case expired:
+		status = "REVERIFICATION_REQUIRED"
+	case rejected:
+		status = "REJECTED"
+	}
+
+	if user.KYCStatus != status {
+		user.KYCStatus = status
+		user.UpdatedAt = s.now()
+	}
+}
+
+// checkKYCPolicyLocked reports whether the helper's verification status
+// allows them to take the request under the configured policy.
+func (s *Store) checkKYCPolicyLocked(helper *models.User, req *models.HelpRequest) error {
+	if helper.KYCStatus == "VERIFIED" {
+		return nil
+	}
+
+	if s.kycPolicy.RestrictPaid && req.Pricing.EstimatedAmount.IsPositive() {
+		return fmt.Errorf("%w: kyc verification required for paid requests", services.ErrHelperNotEligible)
+	}
+	for _, category := range s.kycPolicy.RestrictedCategories {
+		if category == req.Category {
+			return fmt.Errorf("%w: kyc verification required for %s requests", services.ErrHelperNotEligible, category)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// paginate orders items by their (timestamp, ID) key, newest first unless
+// order is "asc", and returns the page following cursor with the cursor for
+// the next one. Cursors are opaque to clients: they encode the order and the
+// key of the last item served, so pages stay stable as new items arrive.
+func paginate[T any](items []T, key func(T) cursorKey, order, cursor string, limit int) ([]T, string, error) {
+	ascending := order == "asc"
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return keyBefore(key(items[i]), key(items[j]), ascending)
+	})
+
+	start := 0
+	if cursor != "" {
+		after, err := decodeCursor(cursor, ascending)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return keyBefore(after, key(items[i]), ascending)
+		})
+	}
+
+	end := start + limit
+	if end >= len(items) {
+		return append([]T{}, items[start:]...), "", nil
+	}
+	return items[start:end], encodeCursor(ascending, key(items[end-1])), nil
+}
+
+// keyBefore reports whether a sorts before b. IDs break timestamp ties and
+// compare shorter-first so that sequential IDs such as req-9 and req-10 keep
+// their numeric order.
+func keyBefore(a, b cursorKey, ascending bool) bool {
+	if !a.at.Equal(b.at) {
+		return a.at.Before(b.at) == ascending
+	}
+	if len(a.id) != len(b.id) {
+		return (len(a.id) < len(b.id)) == ascending
+	}
+	if a.id == b.id {
+		return false
+	}
+	return (a.id < b.id) == ascending
+}
+
+// cursorKey is the sort key of a listed item: its timestamp and ID.
+type cursorKey struct {
+	at time.Time
+	id string
+}
+
+func encodeCursor(ascending bool, key cursorKey) string {
+	order := "desc"
+	if ascending {
+		order = "asc"
+	}
+	raw := order + "|" + strconv.FormatInt(key.at.UnixNano(), 10) + "|" + key.id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string, ascending bool) (cursorKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return cursorKey{}, fmt.Errorf("%w: malformed", services.ErrInvalidCursor)
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return cursorKey{}, fmt.Errorf("%w: malformed", services.ErrInvalidCursor)
+	}
+	if (parts[0] == "asc") != ascending || (parts[0] != "asc" && parts[0] != "desc") {
+		return cursorKey{}, fmt.Errorf("%w: cursor was issued for a different order", services.ErrInvalidCursor)
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return cursorKey{}, fmt.Errorf("%w: malformed", services.ErrInvalidCursor)
+	}
+	return cursorKey{at: time.Unix(0, nanos).UTC(), id: parts[2]}, nil
+}
+
+// statusSet parses a comma-separated status filter.
+func statusSet(raw string) map[string]bool {
+	set := make(map[string]bool)
+	for _, status := range strings.Split(raw, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			set[strings.ToUpper(status)] = true
+		}
+	}
+	return set
+}
+
+// withinRange reports whether t falls in [from, to); nil bounds are open.
+func withinRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+	return true
+}
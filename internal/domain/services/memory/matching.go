@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+// dispatchLocked invites every eligible helper to the request, or only its
+// preferred helper while they still have it to themselves. Callers must hold
+// s.mu for writing.
+func (s *Store) dispatchLocked(req *models.HelpRequest) []*models.MatchSession {
+	if req.PreferredUntil != nil && s.now().Before(*req.PreferredUntil) {
+		if helper, ok := s.users[req.PreferredHelperID]; ok && s.helperEligibleLocked(helper, req) {
+			if s.hasInvitationLocked(helper.ID, req.ID) {
+				return nil
+			}
+			return []*models.MatchSession{s.inviteLocked(helper.ID, req.ID)}
+		}
+		req.PreferredHelperID = ""
+		req.PreferredUntil = nil
+	}
+
+	helperIDs := make([]string, 0, len(s.users))
+	for id, user := range s.users {
+		if s.helperEligibleLocked(user, req) {
+			helperIDs = append(helperIDs, id)
+		}
+	}
+	sort.Strings(helperIDs)
+
+	invited := make([]*models.MatchSession, 0, len(helperIDs))
+	for _, helperID := range helperIDs {
+		if s.hasInvitationLocked(helperID, req.ID) {
+			continue
+		}
+		invited = append(invited, s.inviteLocked(helperID, req.ID))
+	}
+	return invited
+}
+
+func (s *Store) helperEligibleLocked(helper *models.User, req *models.HelpRequest) bool {
+	if helper.ID == req.RequesterID {
+		return false
+	}
+	if !helper.IsHelper || helper.HelperStatus != "ACTIVE" {
+		return false
+	}
+
+	profile, ok := s.helperProfiles[helper.ID]
+	if !ok || !profile.OptedIn {
+		return false
+	}
+
+	if err := s.checkKYCPolicyLocked(helper, req); err != nil {
+		return false
+	}
+
+	for _, skill := range profile.Skills {
+		if skill == req.Category || skill == "GENERAL_HELP" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchedHelperLocked returns the helper who has accepted the request and
+// has not since dropped out.
+func (s *Store) matchedHelperLocked(requestID string) (string, bool) {
+	for _, match := range s.matches {
+		if match.RequestID != requestID {
+			continue
+		}
+		switch match.Status {
+		case "ACCEPTED", "EN_ROUTE", "ARRIVED", "IN_PROGRESS", "COMPLETED":
+			return match.HelperID, true
+		}
+	}
+	return "", false
+}
+
+func (s *Store) hasInvitationLocked(helperID, requestID string) bool {
+	for _, match := range s.matches {
+		if match.HelperID == helperID && match.RequestID == requestID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) inviteLocked(helperID, requestID string) *models.MatchSession {
+	id := fmt.Sprintf("match-%d", s.nextMatchID)
+	s.nextMatchID++
+
+	match := &models.MatchSession{
+		ID:        id,
+		RequestID: requestID,
+		HelperID:  helperID,
+		Status:    "INVITED",
+		InvitedAt: s.now(),
+	}
+
+	s.matches[id] = match
+	return match
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+// NotificationService implementation
+
+func (s *Store) ListNotifications(_ context.Context, userID string) ([]models.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]models.Notification, 0)
+	for i := len(s.notifications) - 1; i >= 0; i-- {
+		if s.notifications[i].UserID == userID {
+			results = append(results, *s.notifications[i])
+		}
+	}
+	return results, nil
+}
+
+// notifyLocked records an in-app notification for the user. Push and SMS
+// fan-out hang off this outbox. Callers must hold s.mu for writing.
+func (s *Store) notifyLocked(userID, kind, title, body string, data map[string]string) *models.Notification {
+	notification := &models.Notification{
+		ID:        fmt.Sprintf("ntf-%d", len(s.notifications)+1),
+		UserID:    userID,
+		Type:      kind,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+		CreatedAt: s.now(),
+	}
+	s.notifications = append(s.notifications, notification)
+	return notification
+}
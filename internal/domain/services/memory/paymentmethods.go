@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+)
+
+// maxPaymentMethods is how many payment methods a seeker may save.
+const maxPaymentMethods = 5
+
+var errPaymentMethodNotFound = errors.New("payment method not found")
+
+// AttachPaymentMethod saves a gateway token for the seeker. The first
+// method, or one attached with MakeDefault, becomes the default.
+func (s *Store) AttachPaymentMethod(ctx context.Context, userID string, input models.AttachPaymentMethodInput) (*models.PaymentMethod, error) {
+	if err := s.checkPaymentMethodLimit(userID); err != nil {
+		return nil, err
+	}
+
+	attached, err := s.gateway.AttachPaymentMethod(ctx, userID, input.PaymentMethodID)
+	if errors.Is(err, gateway.ErrDeclined) {
+		return nil, fmt.Errorf("%w: %v", services.ErrPaymentDeclined, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("attach payment method: %w", err)
+	}
+
+	method, err := s.savePaymentMethod(userID, attached, input.MakeDefault)
+	if err != nil {
+		// Another method was saved while this one was being attached.
+		if detachErr := s.gateway.DetachPaymentMethod(ctx, attached.ID); detachErr != nil && !errors.Is(detachErr, gateway.ErrMethodNotFound) {
+			err = errors.Join(err, fmt.Errorf("detach payment method: %w", detachErr))
+		}
+		return nil, err
+	}
+	return method, nil
+}
+
+func (s *Store) checkPaymentMethodLimit(userID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return errUserNotFound
+	}
+	if len(s.paymentMethodsLocked(userID)) >= maxPaymentMethods {
+		return fmt.Errorf("%w: at most %d payment methods may be saved", services.ErrLimitExceeded, maxPaymentMethods)
+	}
+	return nil
+}
+
+// savePaymentMethod records a method the gateway attached, checking the
+// limit again since the gateway call ran without s.mu.
+func (s *Store) savePaymentMethod(userID string, attached *gateway.PaymentMethod, makeDefault bool) (*models.PaymentMethod, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	methods := s.paymentMethodsLocked(userID)
+	if len(methods) >= maxPaymentMethods {
+		return nil, fmt.Errorf("%w: at most %d payment methods may be saved", services.ErrLimitExceeded, maxPaymentMethods)
+	}
+
+	method := &models.PaymentMethod{
+		ID:         fmt.Sprintf("pm-%d", s.nextPaymentMethodID),
+		UserID:     userID,
+		Type:       attached.Type,
+		Brand:      attached.Brand,
+		Last4:      attached.Last4,
+		ExpMonth:   attached.ExpMonth,
+		ExpYear:    attached.ExpYear,
+		GatewayRef: attached.ID,
+		CreatedAt:  s.now(),
+	}
+	s.nextPaymentMethodID++
+	s.paymentMethods[method.ID] = method
+	if makeDefault || len(methods) == 0 {
+		s.setDefaultPaymentMethodLocked(method)
+	}
+
+	copyMethod := *method
+	return &copyMethod, nil
+}
+
+func (s *Store) ListPaymentMethods(_ context.Context, userID string) ([]models.PaymentMethod, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	methods := []models.PaymentMethod{}
+	for _, method := range s.paymentMethodsLocked(userID) {
+		methods = append(methods, *method)
+	}
+	return methods, nil
+}
+
+func (s *Store) SetDefaultPaymentMethod(_ context.Context, userID, methodID string) (*models.PaymentMethod, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	method, ok := s.paymentMethods[methodID]
+	if !ok || method.UserID != userID {
+		return nil, errPaymentMethodNotFound
+	}
+	s.setDefaultPaymentMethodLocked(method)
+
+	copyMethod := *method
+	return &copyMethod, nil
+}
+
+// RemovePaymentMethod detaches a saved method unless a request that has
+// not been settled yet will be charged to it. Removing the default promotes
+// the most recently added remaining method.
+func (s *Store) RemovePaymentMethod(ctx context.Context, userID, methodID string) error {
+	method, err := s.takePaymentMethod(userID, methodID)
+	if err != nil {
+		return err
+	}
+	err = s.gateway.DetachPaymentMethod(ctx, method.GatewayRef)
+	if err == nil || errors.Is(err, gateway.ErrMethodNotFound) {
+		return nil
+	}
+
+	// Put the method back so the seeker can try again.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paymentMethods[method.ID] = method
+	if method.IsDefault {
+		s.setDefaultPaymentMethodLocked(method)
+	}
+	return fmt.Errorf("detach payment method: %w", err)
+}
+
+// takePaymentMethod removes a method that may be detached, so no request
+// picks it up while the gateway detaches it.
+func (s *Store) takePaymentMethod(userID, methodID string) (*models.PaymentMethod, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	method, ok := s.paymentMethods[methodID]
+	if !ok || method.UserID != userID {
+		return nil, errPaymentMethodNotFound
+	}
+	for _, req := range s.requests {
+		if req.PaymentMethodID == methodID && s.requestPaymentPendingLocked(req) {
+			return nil, fmt.Errorf("%w: payment method is used by pending request %s", services.ErrInvalidState, req.ID)
+		}
+	}
+
+	delete(s.paymentMethods, methodID)
+	if method.IsDefault {
+		if remaining := s.paymentMethodsLocked(userID); len(remaining) > 0 {
+			s.setDefaultPaymentMethodLocked(remaining[0])
+		}
+	}
+	return method, nil
+}
+
+// resolvePaymentMethodLocked returns the saved method a new request will be
+// charged to: the one given, or the seeker's default.
+func (s *Store) resolvePaymentMethodLocked(userID, methodID string) (string, error) {
+	if methodID != "" {
+		method, ok := s.paymentMethods[methodID]
+		if !ok || method.UserID != userID {
+			return "", errPaymentMethodNotFound
+		}
+		return methodID, nil
+	}
+	for _, method := range s.paymentMethodsLocked(userID) {
+		if method.IsDefault {
+			return method.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// requestPaymentPendingLocked reports whether the request may still charge
+// or hold money.
+func (s *Store) requestPaymentPendingLocked(req *models.HelpRequest) bool {
+	switch req.Status {
+	case "DRAFT", "SCHEDULED", "SUBMITTED", "ACCEPTED":
+		return true
+	}
+	payment := s.latestPaymentLocked(req.ID)
+	return payment != nil && (paymentOpen(payment.Status) || payment.Status == "DISPUTED")
+}
+
+// paymentMethodsLocked returns the user's methods, newest first.
+func (s *Store) paymentMethodsLocked(userID string) []*models.PaymentMethod {
+	var methods []*models.PaymentMethod
+	for _, method := range s.paymentMethods {
+		if method.UserID == userID {
+			methods = append(methods, method)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return keyBefore(cursorKey{at: methods[i].CreatedAt, id: methods[i].ID}, cursorKey{at: methods[j].CreatedAt, id: methods[j].ID}, false)
+	})
+	return methods
+}
+
+func (s *Store) setDefaultPaymentMethodLocked(method *models.PaymentMethod) {
+	for _, other := range s.paymentMethods {
+		if other.UserID == method.UserID {
+			other.IsDefault = other.ID == method.ID
+		}
+	}
+}
@@ -0,0 +1,697 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/ledger"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+)
+
+var errPaymentNotFound = errors.New("payment not found")
+
+// escrowGrace is how long after a request's completion deadline escrow is
+// held waiting for the job to be completed and confirmed.
+const escrowGrace = 24 * time.Hour
+
+// confirmationWindow is how long the seeker has to confirm or dispute a job
+// the helper marked COMPLETED before escrow is released without them.
+const confirmationWindow = 48 * time.Hour
+
+// paymentTransitions lists the statuses each payment status may move to.
+var paymentTransitions = map[string][]string{
+	"PENDING":    {"AUTHORIZED", "REFUNDED", "FAILED"},
+	"AUTHORIZED": {"HELD", "REFUNDED"},
+	"HELD":       {"RELEASED", "REFUNDED", "DISPUTED"},
+	"DISPUTED":   {"RELEASED", "REFUNDED"},
+}
+
+// WithGateway sets the payment provider that charges seekers, holds escrow
+// and refunds them.
+func (s *Store) WithGateway(g gateway.Gateway) *Store {
+	s.gateway = g
+	return s
+}
+
+// GetRequestPayment returns the latest payment for a request to its seeker
+// or to the helper being paid.
+func (s *Store) GetRequestPayment(_ context.Context, userID, requestID string) (*models.Payment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payment := s.latestPaymentLocked(requestID)
+	if payment == nil || (payment.SeekerID != userID && payment.HelperID != userID) {
+		return nil, errPaymentNotFound
+	}
+	return copyPayment(payment), nil
+}
+
+// ConfirmCompletion records the seeker's verdict on a job the helper marked
+// completed. SUCCESS releases the escrow to the helper; FAILED opens a
+// dispute that freezes it for ops review.
+func (s *Store) ConfirmCompletion(ctx context.Context, seekerID, matchID string, input models.CompleteSessionInput) (*models.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match, ok := s.matches[matchID]
+	if !ok {
+		return nil, errMatchNotFound
+	}
+	req, ok := s.requests[match.RequestID]
+	if !ok || req.RequesterID != seekerID {
+		return nil, errMatchNotFound
+	}
+	if match.Status != "COMPLETED" {
+		return nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+	if err := s.checkUnreservedLocked(req.ID); err != nil {
+		return nil, err
+	}
+
+	payment := s.paymentForMatchLocked(matchID)
+	if payment == nil {
+		return nil, errPaymentNotFound
+	}
+
+	if input.Confirmation == "FAILED" {
+		if _, err := s.openDisputeLocked(payment, seekerID, models.OpenDisputeInput{
+			Reason:   "SERVICE_NOT_COMPLETED",
+			Details:  input.Reason,
+			Evidence: input.Evidence,
+		}); err != nil {
+			return nil, err
+		}
+		return copyPayment(payment), nil
+	}
+
+	if err := s.releasePaymentLocked(payment, "Seeker confirmed completion"); err != nil {
+		return nil, err
+	}
+	return copyPayment(payment), nil
+}
+
+// SweepEscrow releases completed jobs the seeker neither confirmed nor
+// disputed within the confirmation window and refunds payments whose
+// escrow expired before the job was completed, closing the unfinished job.
+func (s *Store) SweepEscrow(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	now := s.now()
+	settled := 0
+	var errs []error
+	var refunds []*refundPlan
+	for _, payment := range s.payments {
+		if !paymentOpen(payment.Status) || s.reserved[payment.RequestID] {
+			continue
+		}
+
+		match, ok := s.matches[payment.MatchID]
+		switch {
+		case ok && match.Status == "COMPLETED" && payment.Status == "HELD":
+			if match.CompletedAt == nil || now.Before(match.CompletedAt.Add(confirmationWindow)) {
+				continue
+			}
+			if err := s.releasePaymentLocked(payment, "Released automatically after the confirmation window"); err != nil {
+				errs = append(errs, fmt.Errorf("payment %s: %w", payment.ID, err))
+				continue
+			}
+			settled++
+		case now.Before(payment.EscrowExpires):
+		default:
+			plan, err := s.planRefundLocked(payment, money.Zero(payment.Currency), "Escrow expired before the job was completed")
+			if err != nil {
+				errs = append(errs, fmt.Errorf("payment %s: %w", payment.ID, err))
+				continue
+			}
+			s.reserved[payment.RequestID] = true
+			refunds = append(refunds, plan)
+		}
+	}
+	s.mu.Unlock()
+
+	sent := make([]error, len(refunds))
+	for i, plan := range refunds {
+		sent[i] = s.sendRefund(ctx, plan)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, plan := range refunds {
+		payment := plan.payment
+		s.releaseRequestLocked(payment.RequestID)
+		err := sent[i]
+		if err == nil {
+			err = s.applyRefundLocked(plan)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("payment %s: %w", payment.ID, err))
+			continue
+		}
+		if match, ok := s.matches[payment.MatchID]; ok && matchActive(match.Status) {
+			s.closeUnpaidMatchLocked(match)
+		}
+		settled++
+	}
+	return settled, errors.Join(errs...)
+}
+
+// closeUnpaidMatchLocked expires an active match whose escrow was refunded,
+// and its request, so the helper does not go on to do a job that will no
+// longer be paid.
+func (s *Store) closeUnpaidMatchLocked(match *models.MatchSession) {
+	now := s.now()
+	match.Status = "EXPIRED"
+	if req, ok := s.requests[match.RequestID]; ok {
+		req.Status = "EXPIRED"
+		req.UpdatedAt = now
+		for _, other := range s.matches {
+			if other.RequestID == req.ID && other.Status == "INVITED" {
+				other.Status = "EXPIRED"
+				other.RespondedAt = &now
+			}
+		}
+	}
+	s.notifyLocked(match.HelperID, "ESCROW_EXPIRED", "Job closed",
+		"The job was not completed before the seeker's payment hold expired, so it has been closed.",
+		map[string]string{"matchId": match.ID, "requestId": match.RequestID})
+}
+
+// openPaymentLocked creates the payment for a match the helper is accepting
+// and the intent that charges the seeker for it, which chargePayment sends.
+// Fully subsidised jobs have nothing to charge and go straight to escrow
+// with no intent.
+func (s *Store) openPaymentLocked(req *models.HelpRequest, match *models.MatchSession) (*models.Payment, *gateway.IntentRequest, error) {
+	now := s.now()
+	pricing := req.Pricing
+	earnings, err := money.Sum(pricing.Currency, pricing.EstimatedAmount, pricing.Subsidy, pricing.PlatformFee.Neg())
+	if err != nil {
+		return nil, nil, fmt.Errorf("open payment: %w", err)
+	}
+	payment := &models.Payment{
+		ID:             fmt.Sprintf("pay-%d", s.nextPaymentID),
+		RequestID:      req.ID,
+		MatchID:        match.ID,
+		SeekerID:       req.RequesterID,
+		HelperID:       match.HelperID,
+		Amount:         pricing.EstimatedAmount,
+		PlatformFee:    pricing.PlatformFee,
+		Subsidy:        pricing.Subsidy,
+		HelperEarnings: earnings,
+		Currency:       pricing.Currency,
+		Status:         "PENDING",
+		EscrowExpires:  req.SLA.CompletionDeadline.Add(escrowGrace),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Timeline:       []models.PaymentEvent{{Status: "PENDING", At: now}},
+	}
+	s.nextPaymentID++
+	s.payments[payment.ID] = payment
+
+	if payment.Amount.IsZero() {
+		// Nothing to charge for fully subsidised jobs.
+		s.transitionPaymentLocked(payment, "AUTHORIZED", "")
+		s.transitionPaymentLocked(payment, "HELD", "Nothing to charge")
+		return payment, nil, nil
+	}
+
+	intent := &gateway.IntentRequest{
+		Reference: payment.ID,
+		Customer:  payment.SeekerID,
+		Amount:    payment.Amount.Amount(),
+		Currency:  payment.Currency,
+	}
+	if method, ok := s.paymentMethods[req.PaymentMethodID]; ok {
+		intent.PaymentMethod = method.GatewayRef
+	}
+	return payment, intent, nil
+}
+
+// chargePayment sends an opened payment's intent and captures the charge
+// into escrow. A declined charge fails the payment and returns
+// services.ErrPaymentDeclined; a charge the gateway is still processing
+// leaves the payment PENDING until its webhook arrives. It is called without
+// s.mu, with the payment's request reserved.
+func (s *Store) chargePayment(ctx context.Context, payment *models.Payment, req gateway.IntentRequest) error {
+	intent, err := s.gateway.CreateIntent(ctx, req)
+
+	s.mu.Lock()
+	switch {
+	case errors.Is(err, gateway.ErrDeclined):
+		s.failPaymentLocked(payment, err.Error())
+		err = fmt.Errorf("%w: %v", services.ErrPaymentDeclined, err)
+	case err != nil:
+		delete(s.payments, payment.ID)
+		err = fmt.Errorf("create payment intent: %w", err)
+	default:
+		payment.GatewayRef = intent.ID
+	}
+	s.mu.Unlock()
+
+	if err != nil || intent.Status == gateway.IntentProcessing {
+		return err
+	}
+	return s.capturePayment(ctx, payment)
+}
+
+// capturePayment records a payment's authorization and captures it into
+// escrow. A capture that fails voids the authorization, so none is left
+// behind on a match nobody accepted; a declined one returns
+// services.ErrPaymentDeclined. It is called without s.mu, with the
+// payment's request reserved.
+func (s *Store) capturePayment(ctx context.Context, payment *models.Payment) error {
+	s.mu.Lock()
+	err := s.transitionPaymentLocked(payment, "AUTHORIZED", "")
+	gatewayRef := payment.GatewayRef
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	intent, err := s.gateway.Capture(ctx, gatewayRef)
+	if err != nil {
+		note, captureErr := "Capture failed", fmt.Errorf("capture payment: %w", err)
+		if errors.Is(err, gateway.ErrDeclined) {
+			note, captureErr = err.Error(), fmt.Errorf("%w: %v", services.ErrPaymentDeclined, err)
+		}
+		if refundErr := s.refund(ctx, payment, money.Zero(payment.Currency), note); refundErr != nil {
+			return errors.Join(captureErr, refundErr)
+		}
+		return captureErr
+	}
+	if intent.Status == gateway.IntentProcessing {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transitionPaymentLocked(payment, "HELD", "Funds held in escrow")
+}
+
+func (s *Store) failPaymentLocked(payment *models.Payment, reason string) {
+	if err := s.transitionPaymentLocked(payment, "FAILED", reason); err != nil {
+		return
+	}
+	s.notifyLocked(payment.SeekerID, "PAYMENT_FAILED", "Payment failed",
+		"We could not charge your payment method. Please update it to keep your request.",
+		map[string]string{"paymentId": payment.ID, "requestId": payment.RequestID})
+}
+
+// HandleGatewayEvent applies a verified gateway webhook to its payment.
+// Events are deduplicated by ID, and events describing a state the payment
+// has already reached are ignored, so redelivery is safe.
+func (s *Store) HandleGatewayEvent(ctx context.Context, event *gateway.Event) error {
+	s.mu.Lock()
+	if s.gatewayEvents[event.ID] {
+		s.mu.Unlock()
+		return nil
+	}
+	var followUp *models.Payment
+	var err error
+	switch event.Type {
+	case gateway.EventPayoutPaid, gateway.EventPayoutFailed:
+		err = s.applyPayoutEventLocked(event)
+	default:
+		followUp, err = s.applyPaymentEventLocked(event)
+	}
+	s.mu.Unlock()
+
+	if err == nil && followUp != nil {
+		err = s.settleChargeEvent(ctx, event, followUp)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gatewayEvents[event.ID] = true
+	return nil
+}
+
+// applyPaymentEventLocked applies a payment event that needs no further
+// gateway call. An authorization that still has to be captured, or a failed
+// capture that still has to be voided, reserves the payment's request and
+// returns the payment for settleChargeEvent.
+func (s *Store) applyPaymentEventLocked(event *gateway.Event) (*models.Payment, error) {
+	// Webhooks for synchronously declined charges arrive before we learned
+	// the intent ID, so fall back to the reference we passed the gateway.
+	payment := s.payments[event.Reference]
+	for _, candidate := range s.payments {
+		if candidate.GatewayRef != "" && candidate.GatewayRef == event.ObjectID {
+			payment = candidate
+			break
+		}
+	}
+	switch event.Type {
+	case gateway.EventIntentAuthorized, gateway.EventIntentCaptured, gateway.EventIntentFailed:
+		if payment == nil {
+			return nil, errPaymentNotFound
+		}
+		if err := s.checkUnreservedLocked(payment.RequestID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	switch {
+	case event.Type == gateway.EventIntentAuthorized && payment.Status == "PENDING",
+		event.Type == gateway.EventIntentFailed && payment.Status == "AUTHORIZED":
+		s.reserved[payment.RequestID] = true
+		return payment, nil
+	case event.Type == gateway.EventIntentCaptured && payment.Status == "AUTHORIZED":
+		return nil, s.transitionPaymentLocked(payment, "HELD", "Funds held in escrow")
+	case event.Type == gateway.EventIntentFailed && payment.Status == "PENDING":
+		s.failPaymentLocked(payment, "Charge declined")
+		s.revertAcceptanceLocked(payment)
+	}
+	return nil, nil
+}
+
+// settleChargeEvent captures a charge authorized late or voids one whose
+// capture failed, as the event calls for. A charge that ends up refunded
+// takes the helper's acceptance back. It is called without s.mu.
+func (s *Store) settleChargeEvent(ctx context.Context, event *gateway.Event, payment *models.Payment) error {
+	var err error
+	if event.Type == gateway.EventIntentAuthorized {
+		err = s.capturePayment(ctx, payment)
+	} else {
+		err = s.refund(ctx, payment, money.Zero(payment.Currency), "Capture failed")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseRequestLocked(payment.RequestID)
+	if payment.Status != "REFUNDED" {
+		return err
+	}
+	s.revertAcceptanceLocked(payment)
+	return nil
+}
+
+// revertAcceptanceLocked puts the match of a payment that failed before
+// reaching escrow back to an open invitation, as if the helper had never
+// accepted, so the request can be matched again.
+func (s *Store) revertAcceptanceLocked(payment *models.Payment) {
+	match, ok := s.matches[payment.MatchID]
+	if !ok || !matchActive(match.Status) {
+		return
+	}
+	req, ok := s.requests[match.RequestID]
+	if !ok {
+		return
+	}
+
+	now := s.now()
+	match.Status = "INVITED"
+	match.AcceptedAt = nil
+	match.ArrivedAt = nil
+	match.RespondedAt = nil
+
+	req.Status = "SUBMITTED"
+	req.SLA.MatchDeadline = s.slaLocked(req).MatchDeadline
+	req.ExpiresAt = s.expiryLocked(req)
+	req.UpdatedAt = now
+
+	s.notifyLocked(match.HelperID, "PAYMENT_FAILED", "Job on hold",
+		"The seeker's payment did not go through, so this job is no longer accepted.",
+		map[string]string{"matchId": match.ID, "requestId": req.ID})
+}
+
+func (s *Store) releasePaymentLocked(payment *models.Payment, note string) error {
+	if err := s.transitionPaymentLocked(payment, "RELEASED", note); err != nil {
+		return err
+	}
+	s.notifyLocked(payment.HelperID, "PAYMENT_RELEASED", "Payment released",
+		fmt.Sprintf("%s has been released to you.", payment.HelperEarnings),
+		map[string]string{"paymentId": payment.ID, "requestId": payment.RequestID})
+	return nil
+}
+
+// refundPlan is a refund worked out under s.mu and sent to the gateway
+// without it.
+type refundPlan struct {
+	payment    *models.Payment
+	gatewayRef string
+	penalty    money.Money
+	refund     money.Money
+	note       string
+}
+
+// planRefundLocked works out how much of the payment goes back to the
+// seeker, keeping penalty as the cancellation fee owed to the helper.
+func (s *Store) planRefundLocked(payment *models.Payment, penalty money.Money, note string) (*refundPlan, error) {
+	if !canTransitionPayment(payment, "REFUNDED") {
+		return nil, fmt.Errorf("%w: payment is %s", services.ErrInvalidState, payment.Status)
+	}
+	penalty, err := money.Min(penalty, payment.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("refund payment: %w", err)
+	}
+	refund, err := payment.Amount.Sub(penalty)
+	if err != nil {
+		return nil, fmt.Errorf("refund payment: %w", err)
+	}
+	return &refundPlan{payment: payment, gatewayRef: payment.GatewayRef, penalty: penalty, refund: refund, note: note}, nil
+}
+
+// sendRefund asks the gateway to return a planned refund; a nil plan sends
+// nothing. It is called without s.mu, with the payment's request reserved.
+func (s *Store) sendRefund(ctx context.Context, plan *refundPlan) error {
+	if plan == nil || plan.gatewayRef == "" || !plan.refund.IsPositive() {
+		return nil
+	}
+	if _, err := s.gateway.Refund(ctx, plan.gatewayRef, plan.refund.Amount()); err != nil {
+		return fmt.Errorf("refund payment: %w", err)
+	}
+	return nil
+}
+
+// applyRefundLocked records a refund the gateway accepted.
+func (s *Store) applyRefundLocked(plan *refundPlan) error {
+	payment := plan.payment
+	payment.PenaltyAmount = plan.penalty
+	payment.RefundedAmount = plan.refund
+	if err := s.transitionPaymentLocked(payment, "REFUNDED", plan.note); err != nil {
+		return err
+	}
+	s.notifyLocked(payment.SeekerID, "PAYMENT_REFUNDED", "Payment refunded",
+		fmt.Sprintf("%s has been refunded to you.", payment.RefundedAmount),
+		map[string]string{"paymentId": payment.ID, "requestId": payment.RequestID})
+	return nil
+}
+
+// refund plans, sends and applies a refund in one go. It is called without
+// s.mu, with the payment's request reserved.
+func (s *Store) refund(ctx context.Context, payment *models.Payment, penalty money.Money, note string) error {
+	s.mu.Lock()
+	plan, err := s.planRefundLocked(payment, penalty, note)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := s.sendRefund(ctx, plan); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applyRefundLocked(plan)
+}
+
+// Gateway calls are made without holding s.mu, so a slow provider only
+// holds up the operation waiting for it. The operation first reserves the
+// request under the lock. Until it releases it, operations that would
+// change the request, its matches or its payments fail with
+// services.ErrInvalidState and sweeps pass over it, so the state the call
+// was made for still holds when its result is applied under the lock again.
+
+// reserveRequestLocked claims a request for a gateway call.
+func (s *Store) reserveRequestLocked(requestID string) error {
+	if err := s.checkUnreservedLocked(requestID); err != nil {
+		return err
+	}
+	s.reserved[requestID] = true
+	return nil
+}
+
+func (s *Store) releaseRequestLocked(requestID string) {
+	delete(s.reserved, requestID)
+}
+
+// checkUnreservedLocked fails while a gateway call for the request is in
+// flight.
+func (s *Store) checkUnreservedLocked(requestID string) error {
+	if s.reserved[requestID] {
+		return fmt.Errorf("%w: a payment for this request is being processed, try again", services.ErrInvalidState)
+	}
+	return nil
+}
+
+// transitionPaymentLocked moves the payment to status and posts the money
+// movement to the ledger.
+func (s *Store) transitionPaymentLocked(payment *models.Payment, status, note string) error {
+	if !canTransitionPayment(payment, status) {
+		return fmt.Errorf("%w: payment is %s", services.ErrInvalidState, payment.Status)
+	}
+	if err := s.postPaymentLocked(payment, status); err != nil {
+		return err
+	}
+
+	now := s.now()
+	payment.Status = status
+	payment.UpdatedAt = now
+	payment.Timeline = append(payment.Timeline, models.PaymentEvent{Status: status, Note: note, At: now})
+	return nil
+}
+
+// postPaymentLocked records the ledger transaction for the payment moving
+// from its current status to status. Authorization moves the seeker's funds
+// to the gateway, capture into escrow and a dispute into frozen escrow.
+// Release pays the helper their earnings (topped up by any subsidy) and the
+// platform its fee; a refund returns funds to the seeker less the
+// cancellation fee, which goes to the helper.
+func (s *Store) postPaymentLocked(payment *models.Payment, status string) error {
+	amount := payment.Amount.Amount()
+	seeker := ledger.SeekerFunds(payment.SeekerID)
+	helper := ledger.HelperBalance(payment.HelperID)
+	source := heldAccount(payment.Status)
+
+	var entries []models.LedgerEntry
+	switch status {
+	case "AUTHORIZED":
+		entries = ledger.Transfer(seeker, ledger.AccountAuthorized, amount)
+	case "HELD":
+		entries = ledger.Transfer(ledger.AccountAuthorized, ledger.AccountEscrow, amount)
+	case "DISPUTED":
+		entries = ledger.Transfer(ledger.AccountEscrow, ledger.AccountDisputed, amount)
+	case "RELEASED":
+		fee := payment.PlatformFee.Amount()
+		entries = append(entries, ledger.Transfer(source, helper, amount-fee)...)
+		entries = append(entries, ledger.Transfer(source, ledger.AccountPlatformFees, fee)...)
+		entries = append(entries, ledger.Transfer(ledger.AccountSubsidies, helper, payment.Subsidy.Amount())...)
+	case "REFUNDED":
+		if source == "" {
+			// Nothing was authorized, so nothing moves.
+			return nil
+		}
+		penalty := payment.PenaltyAmount.Amount()
+		entries = append(entries, ledger.Transfer(source, seeker, amount-penalty)...)
+		entries = append(entries, ledger.Transfer(source, helper, penalty)...)
+	default:
+		return nil
+	}
+
+	_, err := s.ledger.Post("PAYMENT_"+status, payment.ID, payment.Currency, s.now(), entries...)
+	if errors.Is(err, ledger.ErrNoEntries) {
+		return nil
+	}
+	return err
+}
+
+// heldAccount is the ledger account holding a payment's funds while it is
+// in status, or "" before the charge is authorized.
+func heldAccount(status string) string {
+	switch status {
+	case "AUTHORIZED":
+		return ledger.AccountAuthorized
+	case "HELD":
+		return ledger.AccountEscrow
+	case "DISPUTED":
+		return ledger.AccountDisputed
+	}
+	return ""
+}
+
+// LedgerSummary returns every ledger balance and whether they reconcile.
+func (s *Store) LedgerSummary(_ context.Context) (*models.LedgerSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := s.ledger.Summary()
+	return &summary, nil
+}
+
+// LedgerTransactions returns the ledger transactions for a reference such as
+// a payment ID, or all of them.
+func (s *Store) LedgerTransactions(_ context.Context, reference string) ([]models.LedgerTransaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ledger.Transactions(reference), nil
+}
+
+func canTransitionPayment(payment *models.Payment, status string) bool {
+	for _, next := range paymentTransitions[payment.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// paymentForMatchLocked returns the match's unsettled payment, or its
+// latest one when all are settled; an accept that was declined and retried
+// leaves a FAILED payment next to the one that went through.
+func (s *Store) paymentForMatchLocked(matchID string) *models.Payment {
+	var found *models.Payment
+	for _, payment := range s.payments {
+		if payment.MatchID != matchID {
+			continue
+		}
+		if found == nil {
+			found = payment
+			continue
+		}
+		unsettled, foundUnsettled := paymentUnsettled(payment.Status), paymentUnsettled(found.Status)
+		if unsettled != foundUnsettled {
+			if unsettled {
+				found = payment
+			}
+			continue
+		}
+		if keyBefore(cursorKey{at: found.CreatedAt, id: found.ID}, cursorKey{at: payment.CreatedAt, id: payment.ID}, true) {
+			found = payment
+		}
+	}
+	return found
+}
+
+// latestPaymentLocked returns the most recent payment for a request; a
+// request that was re-matched after a withdrawal has one per accepted match.
+func (s *Store) latestPaymentLocked(requestID string) *models.Payment {
+	var latest *models.Payment
+	for _, payment := range s.payments {
+		if payment.RequestID != requestID {
+			continue
+		}
+		if latest == nil || keyBefore(cursorKey{at: latest.CreatedAt, id: latest.ID}, cursorKey{at: payment.CreatedAt, id: payment.ID}, true) {
+			latest = payment
+		}
+	}
+	return latest
+}
+
+func paymentOpen(status string) bool {
+	switch status {
+	case "PENDING", "AUTHORIZED", "HELD":
+		return true
+	}
+	return false
+}
+
+// paymentUnsettled reports whether a payment still holds or may still
+// collect the seeker's funds.
+func paymentUnsettled(status string) bool {
+	return paymentOpen(status) || status == "DISPUTED"
+}
+
+func copyPayment(payment *models.Payment) *models.Payment {
+	copied := *payment
+	copied.Timeline = append([]models.PaymentEvent(nil), payment.Timeline...)
+	return &copied
+}
@@ -0,0 +1,381 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/ledger"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+)
+
+var (
+	errPayoutNotFound            = errors.New("payout not found")
+	errPayoutDestinationNotFound = errors.New("payout destination not set")
+)
+
+// PayoutPolicy schedules payout batches every Interval from Anchor and only
+// pays balances of at least Minimum.
+type PayoutPolicy struct {
+	Interval time.Duration
+	Anchor   time.Time
+	Minimum  money.Money
+}
+
+// DefaultPayoutPolicy pays out weekly on Mondays at 09:00 Dhaka time once a
+// helper has earned at least 500.
+func DefaultPayoutPolicy() PayoutPolicy {
+	minimum, _ := money.Parse("500", "")
+	return PayoutPolicy{
+		Interval: 7 * 24 * time.Hour,
+		Anchor:   time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+		Minimum:  minimum,
+	}
+}
+
+// next returns the first batch time after t.
+func (p PayoutPolicy) next(t time.Time) time.Time {
+	if t.Before(p.Anchor) {
+		return p.Anchor
+	}
+	periods := t.Sub(p.Anchor)/p.Interval + 1
+	return p.Anchor.Add(periods * p.Interval)
+}
+
+func (s *Store) WithPayoutPolicy(policy PayoutPolicy) *Store {
+	s.payoutPolicy = policy
+	return s
+}
+
+func (s *Store) GetPayoutDestination(_ context.Context, helperID string) (*models.PayoutDestination, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dest, ok := s.payoutDestinations[helperID]
+	if !ok {
+		return nil, errPayoutDestinationNotFound
+	}
+	copyDest := *dest
+	return &copyDest, nil
+}
+
+// SetPayoutDestination saves where the helper is paid. Only KYC-verified
+// helpers may receive payouts.
+func (s *Store) SetPayoutDestination(_ context.Context, helperID string, input models.PayoutDestinationInput) (*models.PayoutDestination, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[helperID]
+	if !ok {
+		return nil, errUserNotFound
+	}
+	if user.KYCStatus != "VERIFIED" {
+		return nil, fmt.Errorf("%w: KYC verification required for payouts", services.ErrHelperNotEligible)
+	}
+
+	dest := &models.PayoutDestination{
+		HelperID:      helperID,
+		Type:          input.Type,
+		Provider:      strings.ToUpper(strings.TrimSpace(input.Provider)),
+		AccountName:   strings.TrimSpace(input.AccountName),
+		MaskedAccount: maskAccount(input.AccountNumber),
+		AccountNumber: input.AccountNumber,
+		RoutingNumber: input.RoutingNumber,
+		UpdatedAt:     s.now(),
+	}
+	s.payoutDestinations[helperID] = dest
+
+	copyDest := *dest
+	return &copyDest, nil
+}
+
+func (s *Store) ListPayouts(_ context.Context, helperID string) ([]models.Payout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payouts := []models.Payout{}
+	for _, payout := range s.payouts {
+		if payout.HelperID == helperID {
+			payouts = append(payouts, *payout)
+		}
+	}
+	sort.Slice(payouts, func(i, j int) bool {
+		return keyBefore(cursorKey{at: payouts[i].CreatedAt, id: payouts[i].ID}, cursorKey{at: payouts[j].CreatedAt, id: payouts[j].ID}, false)
+	})
+	return payouts, nil
+}
+
+func (s *Store) GetPayout(_ context.Context, helperID, payoutID string) (*models.Payout, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payout, ok := s.payouts[payoutID]
+	if !ok || payout.HelperID != helperID {
+		return nil, errPayoutNotFound
+	}
+	copyPayout := *payout
+	return &copyPayout, nil
+}
+
+// Earnings derives the helper's balances from the ledger: released escrow
+// and cancellation fees are available until a payout batch moves them in
+// transit and then out.
+func (s *Store) Earnings(_ context.Context, helperID string) (*models.HelperEarnings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byCurrency := make(map[string]*models.EarningsBalance)
+	balanceFor := func(currency string) *models.EarningsBalance {
+		if _, ok := byCurrency[currency]; !ok {
+			byCurrency[currency] = &models.EarningsBalance{Currency: currency}
+		}
+		return byCurrency[currency]
+	}
+	for _, b := range s.ledger.AccountBalances(ledger.HelperBalance(helperID)) {
+		balanceFor(b.Currency).Available = money.New(b.Balance, b.Currency)
+	}
+	for _, b := range s.ledger.AccountBalances(ledger.HelperPaidOut(helperID)) {
+		balanceFor(b.Currency).PaidOut = money.New(b.Balance, b.Currency)
+	}
+	for _, payout := range s.payouts {
+		if payout.HelperID == helperID && payoutInTransit(payout.Status) {
+			balance := balanceFor(payout.Currency)
+			inTransit, err := balance.InTransit.Add(payout.Amount)
+			if err != nil {
+				return nil, err
+			}
+			balance.InTransit = inTransit
+		}
+	}
+
+	earnings := &models.HelperEarnings{
+		Balances:      []models.EarningsBalance{},
+		MinimumPayout: s.payoutPolicy.Minimum,
+		NextPayoutAt:  s.payoutsDueAt,
+	}
+	if earnings.NextPayoutAt.IsZero() {
+		earnings.NextPayoutAt = s.payoutPolicy.next(s.now())
+	}
+	for currency, balance := range byCurrency {
+		lifetime, err := money.Sum(currency, balance.Available, balance.InTransit, balance.PaidOut)
+		if err != nil {
+			return nil, err
+		}
+		balance.Lifetime = lifetime
+		earnings.Balances = append(earnings.Balances, *balance)
+	}
+	sort.Slice(earnings.Balances, func(i, j int) bool {
+		return earnings.Balances[i].Currency < earnings.Balances[j].Currency
+	})
+	return earnings, nil
+}
+
+// RunPayoutBatch pays every verified helper with a destination whose
+// balance has reached the policy minimum, once the scheduled batch time has
+// come.
+func (s *Store) RunPayoutBatch(ctx context.Context) (int, error) {
+	payouts, errs := s.openPayoutBatch()
+	transfers := make([]*gateway.Transfer, len(payouts))
+	sent := make([]error, len(payouts))
+	for i, payout := range payouts {
+		transfers[i], sent[i] = s.gateway.Payout(ctx, payout.request)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	started := 0
+	for i, payout := range payouts {
+		if err := s.applyPayoutLocked(payout.payout, transfers[i], sent[i]); err != nil {
+			errs = append(errs, fmt.Errorf("payout to %s: %w", payout.payout.HelperID, err))
+			continue
+		}
+		started++
+	}
+	return started, errors.Join(errs...)
+}
+
+// pendingPayout is a payout opened under s.mu and sent to the gateway
+// without it.
+type pendingPayout struct {
+	payout  *models.Payout
+	request gateway.PayoutRequest
+}
+
+// openPayoutBatch opens a payout for every helper balance due one, if the
+// batch time has come.
+func (s *Store) openPayoutBatch() ([]pendingPayout, []error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.payoutsDueAt.IsZero() {
+		s.payoutsDueAt = s.payoutPolicy.next(now)
+	}
+	if now.Before(s.payoutsDueAt) {
+		return nil, nil
+	}
+	s.payoutsDueAt = s.payoutPolicy.next(now)
+
+	helperIDs := make([]string, 0, len(s.payoutDestinations))
+	for helperID := range s.payoutDestinations {
+		helperIDs = append(helperIDs, helperID)
+	}
+	sort.Strings(helperIDs)
+
+	batchID := fmt.Sprintf("batch-%d", s.nextPayoutBatchID)
+	s.nextPayoutBatchID++
+
+	var payouts []pendingPayout
+	var errs []error
+	for _, helperID := range helperIDs {
+		if user, ok := s.users[helperID]; !ok || user.KYCStatus != "VERIFIED" {
+			continue
+		}
+		for _, balance := range s.ledger.AccountBalances(ledger.HelperBalance(helperID)) {
+			minimum, err := s.payoutPolicy.Minimum.In(balance.Currency)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("payout to %s: minimum: %w", helperID, err))
+				continue
+			}
+			if balance.Balance < minimum.Amount() {
+				continue
+			}
+			amount := money.New(balance.Balance, balance.Currency)
+			payout, err := s.openPayoutLocked(batchID, s.payoutDestinations[helperID], amount)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("payout to %s: %w", helperID, err))
+				continue
+			}
+			payouts = append(payouts, *payout)
+		}
+	}
+	return payouts, errs
+}
+
+// openPayoutLocked moves amount from the helper's balance in transit and
+// records a PENDING payout for it.
+func (s *Store) openPayoutLocked(batchID string, dest *models.PayoutDestination, amount money.Money) (*pendingPayout, error) {
+	now := s.now()
+	currency := amount.Currency()
+	payout := &models.Payout{
+		ID:          fmt.Sprintf("po-%d", s.nextPayoutID),
+		BatchID:     batchID,
+		HelperID:    dest.HelperID,
+		Amount:      amount,
+		Currency:    currency,
+		Status:      "PENDING",
+		Destination: dest.Provider + " " + dest.MaskedAccount,
+		CreatedAt:   now,
+	}
+	entries := ledger.Transfer(ledger.HelperBalance(dest.HelperID), ledger.AccountPayoutsInTransit, amount.Amount())
+	if _, err := s.ledger.Post("PAYOUT_INITIATED", payout.ID, currency, now, entries...); err != nil {
+		return nil, err
+	}
+	s.nextPayoutID++
+	s.payouts[payout.ID] = payout
+
+	return &pendingPayout{
+		payout: payout,
+		request: gateway.PayoutRequest{
+			Reference:   payout.ID,
+			Destination: strings.ToLower(dest.Provider) + ":" + dest.AccountNumber,
+			Amount:      amount.Amount(),
+			Currency:    currency,
+		},
+	}, nil
+}
+
+// applyPayoutLocked records the gateway's answer to a PENDING payout.
+func (s *Store) applyPayoutLocked(payout *models.Payout, transfer *gateway.Transfer, err error) error {
+	if err != nil {
+		return s.failPayoutLocked(payout, err.Error())
+	}
+	payout.GatewayRef = transfer.ID
+	payout.Status = "PROCESSING"
+	if transfer.Status == "PAID" {
+		return s.completePayoutLocked(payout)
+	}
+	return nil
+}
+
+func (s *Store) completePayoutLocked(payout *models.Payout) error {
+	now := s.now()
+	entries := ledger.Transfer(ledger.AccountPayoutsInTransit, ledger.HelperPaidOut(payout.HelperID), payout.Amount.Amount())
+	if _, err := s.ledger.Post("PAYOUT_PAID", payout.ID, payout.Currency, now, entries...); err != nil {
+		return err
+	}
+	payout.Status = "PAID"
+	payout.Processed = &now
+	payout.Receipt = &models.PayoutReceipt{
+		Number:      "RCPT-" + strings.ToUpper(payout.ID),
+		IssuedAt:    now,
+		Amount:      payout.Amount,
+		Currency:    payout.Currency,
+		Destination: payout.Destination,
+	}
+	s.notifyLocked(payout.HelperID, "PAYOUT_PAID", "Payout sent",
+		fmt.Sprintf("%s has been sent to %s.", payout.Amount, payout.Destination),
+		map[string]string{"payoutId": payout.ID})
+	return nil
+}
+
+// failPayoutLocked returns the payout to the helper's balance so the next
+// batch retries it.
+func (s *Store) failPayoutLocked(payout *models.Payout, reason string) error {
+	now := s.now()
+	entries := ledger.Transfer(ledger.AccountPayoutsInTransit, ledger.HelperBalance(payout.HelperID), payout.Amount.Amount())
+	if _, err := s.ledger.Post("PAYOUT_FAILED", payout.ID, payout.Currency, now, entries...); err != nil {
+		return err
+	}
+	payout.Status = "FAILED"
+	payout.FailureReason = reason
+	payout.Processed = &now
+	s.notifyLocked(payout.HelperID, "PAYOUT_FAILED", "Payout failed",
+		"We could not send your payout. Please check your payout details; we will retry with the next batch.",
+		map[string]string{"payoutId": payout.ID})
+	return nil
+}
+
+func (s *Store) applyPayoutEventLocked(event *gateway.Event) error {
+	payout := s.payouts[event.Reference]
+	for _, candidate := range s.payouts {
+		if candidate.GatewayRef != "" && candidate.GatewayRef == event.ObjectID {
+			payout = candidate
+			break
+		}
+	}
+	if payout == nil {
+		return errPayoutNotFound
+	}
+	if payout.Status == "PENDING" {
+		// The gateway has not answered the payout request yet; the webhook
+		// is retried.
+		return fmt.Errorf("%w: payout is still being sent", services.ErrInvalidState)
+	}
+	if payout.Status != "PROCESSING" {
+		return nil
+	}
+	if event.Type == gateway.EventPayoutFailed {
+		return s.failPayoutLocked(payout, "Rejected by the payment provider")
+	}
+	return s.completePayoutLocked(payout)
+}
+
+func payoutInTransit(status string) bool {
+	return status == "PENDING" || status == "PROCESSING"
+}
+
+// maskAccount keeps only the last four digits of an account number.
+func maskAccount(number string) string {
+	if len(number) <= 4 {
+		return number
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+const maxSavedPlaces = 20
+
+var (
+	errPlaceNotFound    = errors.New("saved place not found")
+	errLocationRequired = errors.New("location or saved place required")
+)
+
+// PlaceService implementation
+
+func (s *Store) ListPlaces(_ context.Context, userID string) ([]models.SavedPlace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	places := make([]models.SavedPlace, 0)
+	for _, place := range s.placesForLocked(userID) {
+		places = append(places, *place)
+	}
+	return places, nil
+}
+
+func (s *Store) CreatePlace(_ context.Context, userID string, input models.SavedPlaceInput) (*models.SavedPlace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.placesForLocked(userID)
+	if len(existing) >= maxSavedPlaces {
+		return nil, fmt.Errorf("at most %d saved places allowed", maxSavedPlaces)
+	}
+
+	id := fmt.Sprintf("place-%d", s.nextPlaceID)
+	s.nextPlaceID++
+
+	now := s.now()
+	place := &models.SavedPlace{
+		ID:        id,
+		UserID:    userID,
+		Label:     input.Label,
+		Location:  input.Location,
+		IsDefault: input.IsDefault || len(existing) == 0,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.places[id] = place
+	if place.IsDefault {
+		s.setDefaultPlaceLocked(place)
+	}
+
+	copied := *place
+	return &copied, nil
+}
+
+func (s *Store) UpdatePlace(_ context.Context, userID, placeID string, input models.SavedPlaceInput) (*models.SavedPlace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	place, ok := s.places[placeID]
+	if !ok || place.UserID != userID {
+		return nil, errPlaceNotFound
+	}
+
+	place.Label = input.Label
+	place.Location = input.Location
+	place.UpdatedAt = s.now()
+	if input.IsDefault {
+		s.setDefaultPlaceLocked(place)
+	}
+
+	copied := *place
+	return &copied, nil
+}
+
+// DeletePlace removes a saved place. Deleting the default makes the oldest
+// remaining place the default. Requests keep the location they were created
+// with.
+func (s *Store) DeletePlace(_ context.Context, userID, placeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	place, ok := s.places[placeID]
+	if !ok || place.UserID != userID {
+		return errPlaceNotFound
+	}
+
+	delete(s.places, placeID)
+	if place.IsDefault {
+		if remaining := s.placesForLocked(userID); len(remaining) > 0 {
+			s.setDefaultPlaceLocked(remaining[0])
+		}
+	}
+	return nil
+}
+
+// placesForLocked returns the user's places, oldest first.
+func (s *Store) placesForLocked(userID string) []*models.SavedPlace {
+	var places []*models.SavedPlace
+	for _, place := range s.places {
+		if place.UserID == userID {
+			places = append(places, place)
+		}
+	}
+	sort.Slice(places, func(i, j int) bool {
+		return keyBefore(cursorKey{at: places[i].CreatedAt, id: places[i].ID}, cursorKey{at: places[j].CreatedAt, id: places[j].ID}, true)
+	})
+	return places
+}
+
+func (s *Store) setDefaultPlaceLocked(place *models.SavedPlace) {
+	for _, other := range s.places {
+		if other.UserID == place.UserID {
+			other.IsDefault = other.ID == place.ID
+		}
+	}
+}
+
+// resolvePlaceLocked fills the input's location from the referenced saved
+// place, which takes precedence over any location given alongside it.
+func (s *Store) resolvePlaceLocked(userID string, input *models.CreateHelpRequestInput) error {
+	if input.SavedPlaceID != "" {
+		place, ok := s.places[input.SavedPlaceID]
+		if !ok || place.UserID != userID {
+			return errPlaceNotFound
+		}
+		location := place.Location
+		input.Location = &location
+	}
+	if input.Location == nil {
+		return errLocationRequired
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/pricing"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+const defaultQuoteTTL = 15 * time.Minute
+
+func (s *Store) WithPricing(engine *pricing.Engine) *Store {
+	s.pricing = engine
+	return s
+}
+
+// WithQuotes sets the key quote IDs are signed with and how long a quote
+// stays valid.
+func (s *Store) WithQuotes(signer *pricing.QuoteSigner, ttl time.Duration) *Store {
+	s.quoteSigner = signer
+	s.quoteTTL = ttl
+	return s
+}
+
+// PricingService implementation
+
+func (s *Store) EstimatePrice(_ context.Context, userID string, input models.CreateHelpRequestInput) (*models.Pricing, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errUserNotFound
+	}
+	if err := s.resolvePlaceLocked(userID, &input); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.priceLocked(input)
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (s *Store) CreateQuote(_ context.Context, userID string, input models.CreateHelpRequestInput) (*models.PriceQuote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errUserNotFound
+	}
+	if err := s.resolvePlaceLocked(userID, &input); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.priceLocked(input)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.now().Add(s.quoteTTL).Truncate(time.Second)
+	id, err := s.quoteSigner.Sign(pricing.QuoteClaims{
+		UserID:      userID,
+		Fingerprint: pricing.Fingerprint(input),
+		ExpiresAt:   expiresAt,
+		Pricing:     quote,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	quote.QuoteID = id
+	return &models.PriceQuote{ID: id, Pricing: quote, ExpiresAt: expiresAt}, nil
+}
+
+func (s *Store) ListPriceBooks(_ context.Context) ([]models.PriceBook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pricing.Books(), nil
+}
+
+func (s *Store) PublishPriceBook(_ context.Context, book models.PriceBook) (*models.PriceBook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if book.EffectiveFrom.IsZero() {
+		book.EffectiveFrom = now
+	}
+	if book.EffectiveFrom.Before(now) {
+		return nil, fmt.Errorf("price books cannot take effect in the past")
+	}
+
+	published, err := s.pricing.Publish(book)
+	if err != nil {
+		return nil, err
+	}
+	return &published, nil
+}
+
+// pricingForRequestLocked honours the quote referenced by the input, or
+// prices the request afresh when there is none.
+func (s *Store) pricingForRequestLocked(userID string, input models.CreateHelpRequestInput) (models.Pricing, error) {
+	if input.QuoteID == "" {
+		return s.priceLocked(input)
+	}
+
+	claims, err := s.quoteSigner.Parse(input.QuoteID)
+	if err != nil {
+		return models.Pricing{}, fmt.Errorf("%w: %v", services.ErrQuoteInvalid, err)
+	}
+	if claims.UserID != userID {
+		return models.Pricing{}, fmt.Errorf("%w: quote was issued to another user", services.ErrQuoteInvalid)
+	}
+	if claims.Fingerprint != pricing.Fingerprint(input) {
+		return models.Pricing{}, fmt.Errorf("%w: request differs from the quoted request", services.ErrQuoteInvalid)
+	}
+	if !s.now().Before(claims.ExpiresAt) {
+		return models.Pricing{}, fmt.Errorf("%w: quote expired at %s, request a new one", services.ErrQuoteExpired, claims.ExpiresAt.Format(time.RFC3339))
+	}
+
+	quote := claims.Pricing
+	quote.QuoteID = input.QuoteID
+	return quote, nil
+}
+
+func (s *Store) priceLocked(input models.CreateHelpRequestInput) (models.Pricing, error) {
+	if input.Location == nil {
+		return models.Pricing{}, errLocationRequired
+	}
+
+	now := s.now()
+	quote := pricing.Quote{
+		Type:      input.Type,
+		Category:  input.Category,
+		Location:  *input.Location,
+		PromoCode: input.PromoCode,
+		At:        now,
+		ServiceAt: now,
+	}
+	if input.ScheduledFor != nil {
+		quote.ServiceAt = *input.ScheduledFor
+	}
+	return s.pricing.Quote(quote)
+}
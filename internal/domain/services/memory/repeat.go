@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// Repeat submits a new request cloned from one of the seeker's past
+// requests at today's prices. Attachments are not copied. When asked, the
+// helper who took the original is invited alone first once matching starts;
+// if they are no longer eligible the request goes straight to everyone.
+func (s *Store) Repeat(_ context.Context, userID, requestID string, input models.RepeatRequestInput) (*models.HelpRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.requests[requestID]
+	if !ok || source.RequesterID != userID {
+		return nil, errRequestNotFound
+	}
+	if source.Status == "DRAFT" {
+		return nil, fmt.Errorf("%w: submit the draft instead of repeating it", services.ErrInvalidState)
+	}
+
+	clone := requestInput(source)
+	clone.Attachments = nil
+	clone.QuoteID = ""
+	clone.ScheduledFor = input.ScheduledFor
+
+	req, err := s.createLocked(userID, clone)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.PreferSameHelper {
+		if helperID, ok := s.matchedHelperLocked(source.ID); ok {
+			req.PreferredHelperID = helperID
+		}
+	}
+	s.submitLocked(req)
+
+	copyReq := *req
+	return &copyReq, nil
+}
@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/ledger"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/reports"
+)
+
+// FinancialReport builds the named report over [filter.From, filter.To).
+// Ledger-based reports select transactions by posting time; the payout and
+// dispute reports select records created in the range.
+func (s *Store) FinancialReport(_ context.Context, kind string, filter models.ReportFilter) (*models.Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var report models.Report
+	switch kind {
+	case reports.KindTransactions:
+		report = s.transactionsReportLocked(filter)
+	case reports.KindFees:
+		report = s.feesReportLocked(filter)
+	case reports.KindPayouts:
+		report = s.payoutsReportLocked(filter)
+	case reports.KindRefunds:
+		report = s.refundsReportLocked(filter)
+	case reports.KindDisputes:
+		report = s.disputesReportLocked(filter)
+	default:
+		return nil, fmt.Errorf("%w: %q", reports.ErrUnknownReport, kind)
+	}
+	return &report, nil
+}
+
+// ReconciliationReport compares the gateway movements the ledger posted in
+// the range with the gateway's settlement records for it.
+func (s *Store) ReconciliationReport(_ context.Context, filter models.ReportFilter, settlements []models.SettlementRecord) (*models.Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := reports.Reconcile(s.gatewayMovementsLocked(filter), settlements)
+	return &report, nil
+}
+
+// gatewayMovementsLocked lists the money the gateway should have moved for
+// ledger postings in the range: captures into escrow, refunds out of
+// captured funds and paid payouts. Refunds of uncaptured authorizations
+// are voids and settle nothing.
+func (s *Store) gatewayMovementsLocked(filter models.ReportFilter) []models.SettlementRecord {
+	movements := []models.SettlementRecord{}
+	for _, tx := range s.ledgerTransactionsLocked(filter) {
+		record := models.SettlementRecord{Reference: tx.Reference, Currency: tx.Currency, SettledAt: tx.PostedAt}
+		switch tx.Kind {
+		case "PAYMENT_HELD":
+			payment, ok := s.payments[tx.Reference]
+			if !ok {
+				continue
+			}
+			record.Type, record.GatewayRef = reports.TypeCharge, payment.GatewayRef
+			record.Amount = entryAmount(tx, ledger.AccountEscrow)
+		case "PAYMENT_REFUNDED":
+			payment, ok := s.payments[tx.Reference]
+			if !ok || entryAmount(tx, ledger.AccountAuthorized) != 0 {
+				continue
+			}
+			record.Type, record.GatewayRef = reports.TypeRefund, payment.GatewayRef
+			record.Amount = entryAmount(tx, ledger.SeekerFunds(payment.SeekerID))
+		case "PAYOUT_PAID":
+			payout, ok := s.payouts[tx.Reference]
+			if !ok {
+				continue
+			}
+			record.Type, record.GatewayRef = reports.TypePayout, payout.GatewayRef
+			record.Amount = entryAmount(tx, ledger.HelperPaidOut(payout.HelperID))
+		default:
+			continue
+		}
+		if record.GatewayRef != "" && record.Amount != 0 {
+			movements = append(movements, record)
+		}
+	}
+	return movements
+}
+
+func (s *Store) transactionsReportLocked(filter models.ReportFilter) models.Report {
+	report := models.Report{
+		Name:   reports.KindTransactions,
+		Header: []string{"posted_at", "transaction_id", "kind", "reference", "account", "currency", "amount"},
+		Rows:   [][]string{},
+	}
+	for _, tx := range s.ledgerTransactionsLocked(filter) {
+		for _, entry := range tx.Entries {
+			report.Rows = append(report.Rows, []string{
+				formatReportTime(tx.PostedAt), tx.ID, tx.Kind, tx.Reference, entry.Account, tx.Currency, reports.FormatAmount(entry.Amount, tx.Currency),
+			})
+		}
+	}
+	return report
+}
+
+// feesReportLocked lists each posting that earned a platform fee or spent
+// a subsidy; net is the platform's revenue from it.
+func (s *Store) feesReportLocked(filter models.ReportFilter) models.Report {
+	report := models.Report{
+		Name:   reports.KindFees,
+		Header: []string{"posted_at", "transaction_id", "payment_id", "request_id", "currency", "fee", "subsidy", "net"},
+		Rows:   [][]string{},
+	}
+	for _, tx := range s.ledgerTransactionsLocked(filter) {
+		fee := entryAmount(tx, ledger.AccountPlatformFees)
+		subsidy := -entryAmount(tx, ledger.AccountSubsidies)
+		if fee == 0 && subsidy == 0 {
+			continue
+		}
+		var requestID string
+		if payment, ok := s.payments[tx.Reference]; ok {
+			requestID = payment.RequestID
+		}
+		report.Rows = append(report.Rows, []string{
+			formatReportTime(tx.PostedAt), tx.ID, tx.Reference, requestID, tx.Currency,
+			reports.FormatAmount(fee, tx.Currency), reports.FormatAmount(subsidy, tx.Currency), reports.FormatAmount(fee-subsidy, tx.Currency),
+		})
+	}
+	return report
+}
+
+func (s *Store) payoutsReportLocked(filter models.ReportFilter) models.Report {
+	report := models.Report{
+		Name:   reports.KindPayouts,
+		Header: []string{"created_at", "payout_id", "batch_id", "helper_id", "status", "currency", "amount", "destination", "gateway_ref", "processed_at", "receipt", "failure_reason"},
+		Rows:   [][]string{},
+	}
+	payouts := []*models.Payout{}
+	for _, payout := range s.payouts {
+		if inReportRange(filter, payout.CreatedAt) {
+			payouts = append(payouts, payout)
+		}
+	}
+	sort.Slice(payouts, func(i, j int) bool {
+		return keyBefore(cursorKey{at: payouts[i].CreatedAt, id: payouts[i].ID}, cursorKey{at: payouts[j].CreatedAt, id: payouts[j].ID}, true)
+	})
+	for _, payout := range payouts {
+		var processed, receipt string
+		if payout.Processed != nil {
+			processed = formatReportTime(*payout.Processed)
+		}
+		if payout.Receipt != nil {
+			receipt = payout.Receipt.Number
+		}
+		report.Rows = append(report.Rows, []string{
+			formatReportTime(payout.CreatedAt), payout.ID, payout.BatchID, payout.HelperID, payout.Status, payout.Currency,
+			payout.Amount.Decimal(), payout.Destination, payout.GatewayRef, processed, receipt, payout.FailureReason,
+		})
+	}
+	return report
+}
+
+// refundsReportLocked lists refund postings: what went back to the seeker
+// and what the helper kept as a cancellation fee or dispute share.
+func (s *Store) refundsReportLocked(filter models.ReportFilter) models.Report {
+	report := models.Report{
+		Name:   reports.KindRefunds,
+		Header: []string{"refunded_at", "payment_id", "request_id", "seeker_id", "helper_id", "currency", "amount", "refunded", "helper_share", "gateway_ref", "dispute_id"},
+		Rows:   [][]string{},
+	}
+	for _, tx := range s.ledgerTransactionsLocked(filter) {
+		payment, ok := s.payments[tx.Reference]
+		if tx.Kind != "PAYMENT_REFUNDED" || !ok {
+			continue
+		}
+		refunded := entryAmount(tx, ledger.SeekerFunds(payment.SeekerID))
+		helperShare := entryAmount(tx, ledger.HelperBalance(payment.HelperID))
+		var disputeID string
+		if dispute := s.disputeForPaymentLocked(payment.ID); dispute != nil {
+			disputeID = dispute.ID
+		}
+		report.Rows = append(report.Rows, []string{
+			formatReportTime(tx.PostedAt), payment.ID, payment.RequestID, payment.SeekerID, payment.HelperID, tx.Currency,
+			reports.FormatAmount(refunded+helperShare, tx.Currency), reports.FormatAmount(refunded, tx.Currency), reports.FormatAmount(helperShare, tx.Currency), payment.GatewayRef, disputeID,
+		})
+	}
+	return report
+}
+
+func (s *Store) disputesReportLocked(filter models.ReportFilter) models.Report {
+	report := models.Report{
+		Name:   reports.KindDisputes,
+		Header: []string{"opened_at", "dispute_id", "transaction_id", "request_id", "opened_by", "reason", "status", "currency", "amount", "outcome", "refunded", "helper_amount", "resolved_by", "resolved_at"},
+		Rows:   [][]string{},
+	}
+	disputes := []*models.Dispute{}
+	for _, dispute := range s.disputes {
+		if inReportRange(filter, dispute.CreatedAt) {
+			disputes = append(disputes, dispute)
+		}
+	}
+	sort.Slice(disputes, func(i, j int) bool {
+		return keyBefore(cursorKey{at: disputes[i].CreatedAt, id: disputes[i].ID}, cursorKey{at: disputes[j].CreatedAt, id: disputes[j].ID}, true)
+	})
+	for _, dispute := range disputes {
+		var currency, amount string
+		if payment, ok := s.payments[dispute.TransactionID]; ok {
+			currency, amount = payment.Currency, payment.Amount.Decimal()
+		}
+		row := []string{
+			formatReportTime(dispute.CreatedAt), dispute.ID, dispute.TransactionID, dispute.RequestID, dispute.OpenedBy,
+			dispute.Reason, dispute.Status, currency, amount,
+		}
+		if resolution := dispute.Resolution; resolution != nil {
+			row = append(row, resolution.Outcome, resolution.RefundedAmount.Decimal(),
+				resolution.HelperAmount.Decimal(), resolution.ResolvedBy, formatReportTime(resolution.ResolvedAt))
+		} else {
+			row = append(row, "", "", "", "", "")
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// ledgerTransactionsLocked returns the ledger transactions posted in the
+// range, in posting order.
+func (s *Store) ledgerTransactionsLocked(filter models.ReportFilter) []models.LedgerTransaction {
+	selected := []models.LedgerTransaction{}
+	for _, tx := range s.ledger.Transactions("") {
+		if inReportRange(filter, tx.PostedAt) {
+			selected = append(selected, tx)
+		}
+	}
+	return selected
+}
+
+// entryAmount is the net amount the transaction moved into account.
+func entryAmount(tx models.LedgerTransaction, account string) int64 {
+	var amount int64
+	for _, entry := range tx.Entries {
+		if entry.Account == account {
+			amount += entry.Amount
+		}
+	}
+	return amount
+}
+
+func inReportRange(filter models.ReportFilter, at time.Time) bool {
+	return !at.Before(filter.From) && at.Before(filter.To)
+}
+
+func formatReportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
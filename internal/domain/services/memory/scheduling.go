@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+)
+
+// preferredHelperWindow is how long a preferred helper has the request to
+// themselves before it is offered to everyone.
+const preferredHelperWindow = 5 * time.Minute
+
+// SchedulingPolicy governs when requests are matched and when unmatched
+// ones expire.
+type SchedulingPolicy struct {
+	// BookingHorizon is how far ahead a request may be scheduled.
+	BookingHorizon time.Duration
+	// MatchingLead is how long before the scheduled time helpers are
+	// invited.
+	MatchingLead time.Duration
+	// Reminders are the lead times before the scheduled time at which the
+	// seeker and the matched helper are reminded.
+	Reminders []time.Duration
+	// UnmatchedExpiry is how long an immediate request may wait for a
+	// helper before it expires. Scheduled requests expire at their slot.
+	UnmatchedExpiry time.Duration
+}
+
+func DefaultSchedulingPolicy() SchedulingPolicy {
+	return SchedulingPolicy{
+		BookingHorizon:  30 * 24 * time.Hour,
+		MatchingLead:    24 * time.Hour,
+		Reminders:       []time.Duration{24 * time.Hour, time.Hour},
+		UnmatchedExpiry: time.Hour,
+	}
+}
+
+func (s *Store) WithSchedulingPolicy(policy SchedulingPolicy) *Store {
+	s.scheduling = policy
+	return s
+}
+
+// DispatchDue starts matching for scheduled requests whose lead time has
+// arrived and offers requests to every eligible helper once their preferred
+// helper's window has passed.
+func (s *Store) DispatchDue(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	changed := 0
+	for _, req := range s.requests {
+		if s.reserved[req.ID] {
+			continue
+		}
+		switch {
+		case req.Status == "SCHEDULED" && req.MatchingStartsAt != nil && !now.Before(*req.MatchingStartsAt):
+			s.startMatchingLocked(req)
+			changed++
+		case req.Status == "SUBMITTED" && req.PreferredUntil != nil && !now.Before(*req.PreferredUntil):
+			s.clearPreferenceLocked(req)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// SweepScheduleReminders reminds the seeker and the matched helper of an
+// upcoming scheduled request, once per configured lead time.
+func (s *Store) SweepScheduleReminders(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	leads := append([]time.Duration{}, s.scheduling.Reminders...)
+	sort.Slice(leads, func(i, j int) bool { return leads[i] < leads[j] })
+
+	reminded := 0
+	for _, req := range s.requests {
+		if req.ScheduledFor == nil || !req.ScheduledFor.After(now) {
+			continue
+		}
+		switch req.Status {
+		case "SCHEDULED", "SUBMITTED", "ACCEPTED":
+		default:
+			continue
+		}
+
+		// As with KYC expiry, only the tightest lead time already reached is
+		// sent so a late sweep does not deliver stale reminders.
+		remaining := req.ScheduledFor.Sub(now)
+		for _, lead := range leads {
+			if remaining > lead {
+				continue
+			}
+			if sent, ok := s.scheduleReminders[req.ID]; ok && sent <= lead {
+				break
+			}
+			s.scheduleReminders[req.ID] = lead
+			s.remindLocked(req)
+			reminded++
+			break
+		}
+	}
+	return reminded, nil
+}
+
+func (s *Store) remindLocked(req *models.HelpRequest) {
+	when := req.ScheduledFor.Format(time.RFC3339)
+	data := map[string]string{"requestId": req.ID, "scheduledFor": when}
+
+	match := s.activeMatchLocked(req.ID)
+	body := fmt.Sprintf("Your %s request is scheduled for %s.", req.Category, when)
+	if match == nil {
+		body += " We are still looking for a helper."
+	}
+	s.notifyLocked(req.RequesterID, "SCHEDULE_REMINDER", "Upcoming request", body, data)
+
+	if match != nil {
+		s.notifyLocked(match.HelperID, "SCHEDULE_REMINDER", "Upcoming job",
+			fmt.Sprintf("You are booked for a %s request at %s.", req.Category, when), data)
+	}
+}
+
+// validateScheduleLocked checks that only PLANNED requests carry a
+// scheduled time and that it falls within the booking horizon.
+func (s *Store) validateScheduleLocked(requestType string, scheduledFor *time.Time) error {
+	if requestType != "PLANNED" {
+		if scheduledFor != nil {
+			return fmt.Errorf("scheduledFor is only allowed for PLANNED requests")
+		}
+		return nil
+	}
+
+	if scheduledFor == nil {
+		return fmt.Errorf("scheduledFor is required for PLANNED requests")
+	}
+	now := s.now()
+	if !scheduledFor.After(now) {
+		return fmt.Errorf("scheduledFor must be in the future")
+	}
+	if scheduledFor.After(now.Add(s.scheduling.BookingHorizon)) {
+		return fmt.Errorf("scheduledFor must be within %s", s.scheduling.BookingHorizon)
+	}
+	return nil
+}
+
+// scheduleLocked sets the request's SLA and either starts matching or, for
+// requests scheduled beyond the matching lead time, parks it as SCHEDULED.
+// Reminders already sent were for the old time, so they are sent again.
+// Callers must hold s.mu for writing.
+func (s *Store) scheduleLocked(req *models.HelpRequest) {
+	now := s.now()
+	req.UpdatedAt = now
+	req.SLA = s.slaLocked(req)
+	req.ExpiresAt = s.expiryLocked(req)
+	delete(s.scheduleReminders, req.ID)
+
+	if req.ScheduledFor != nil {
+		start := req.ScheduledFor.Add(-s.scheduling.MatchingLead)
+		if now.Before(start) {
+			req.Status = "SCHEDULED"
+			req.MatchingStartsAt = &start
+			for _, match := range s.matches {
+				if match.RequestID == req.ID && match.Status == "INVITED" {
+					match.Status = "CANCELLED"
+					match.RespondedAt = &now
+				}
+			}
+			return
+		}
+	}
+
+	wasMatching := req.Status == "SUBMITTED"
+	req.Status = "SUBMITTED"
+	req.MatchingStartsAt = nil
+	if wasMatching {
+		s.rematchLocked(req)
+		return
+	}
+	s.startMatchingLocked(req)
+}
+
+// startMatchingLocked moves a request into matching and invites helpers,
+// opening the preferred helper's window if there is one.
+func (s *Store) startMatchingLocked(req *models.HelpRequest) {
+	now := s.now()
+	req.Status = "SUBMITTED"
+	req.MatchingStartsAt = nil
+	req.UpdatedAt = now
+	if req.PreferredHelperID != "" {
+		until := now.Add(preferredHelperWindow)
+		req.PreferredUntil = &until
+	}
+	s.rematchLocked(req)
+}
+
+// slaLocked computes the SLA windows. Requests scheduled for later count
+// from the scheduled time rather than from now, but always leave at least
+// the usual match window.
+func (s *Store) slaLocked(req *models.HelpRequest) models.SLAWindows {
+	now := s.now()
+	if req.ScheduledFor == nil {
+		return models.SLAWindows{
+			MatchDeadline:      now.Add(matchWindow),
+			CompletionDeadline: now.Add(completionWindow),
+		}
+	}
+
+	matchDeadline := req.ScheduledFor.Add(-matchWindow)
+	if earliest := now.Add(matchWindow); matchDeadline.Before(earliest) {
+		matchDeadline = earliest
+	}
+	return models.SLAWindows{
+		MatchDeadline:      matchDeadline,
+		CompletionDeadline: req.ScheduledFor.Add(completionWindow),
+	}
+}
+
+// expiryLocked returns when the request expires if no helper accepts it.
+func (s *Store) expiryLocked(req *models.HelpRequest) *time.Time {
+	expires := s.now().Add(s.scheduling.UnmatchedExpiry)
+	if req.ScheduledFor != nil {
+		expires = *req.ScheduledFor
+	}
+	return &expires
+}
+
+// clearPreferenceLocked drops the request's preferred helper and invites
+// everyone else who is eligible.
+func (s *Store) clearPreferenceLocked(req *models.HelpRequest) {
+	req.PreferredHelperID = ""
+	req.PreferredUntil = nil
+	req.UpdatedAt = s.now()
+	s.dispatchLocked(req)
+}
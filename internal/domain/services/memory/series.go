@@ -0,0 +1,357 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/recurrence"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+)
+
+// seriesLookahead is how far ahead occurrences are generated. It is further
+// capped by the booking horizon.
+const seriesLookahead = 14 * 24 * time.Hour
+
+var errSeriesNotFound = errors.New("series not found")
+
+// SeriesService implementation
+
+func (s *Store) CreateSeries(_ context.Context, userID string, input models.CreateSeriesInput) (*models.RequestSeries, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := recurrence.Parse(input.Recurrence); err != nil {
+		return nil, err
+	}
+	if err := s.validateScheduleLocked("PLANNED", &input.StartsAt); err != nil {
+		return nil, err
+	}
+
+	template := models.CreateHelpRequestInput{
+		Type:         "PLANNED",
+		Category:     input.Category,
+		Location:     input.Location,
+		SavedPlaceID: input.SavedPlaceID,
+		ScheduledFor: &input.StartsAt,
+		PromoCode:    input.PromoCode,
+	}
+	if err := s.resolvePlaceLocked(userID, &template); err != nil {
+		return nil, err
+	}
+	if _, err := s.priceLocked(template); err != nil {
+		return nil, err
+	}
+
+	if input.PreferredHelperID != "" {
+		helper, ok := s.users[input.PreferredHelperID]
+		if !ok || !helper.IsHelper || helper.ID == userID {
+			return nil, fmt.Errorf("%w: preferred helper must be another registered helper", services.ErrHelperNotEligible)
+		}
+	}
+
+	id := fmt.Sprintf("series-%d", s.nextSeriesID)
+	s.nextSeriesID++
+
+	now := s.now()
+	series := &models.RequestSeries{
+		ID:                id,
+		RequesterID:       userID,
+		Status:            "ACTIVE",
+		Recurrence:        input.Recurrence,
+		StartsAt:          input.StartsAt,
+		Category:          input.Category,
+		Description:       input.Description,
+		Location:          *template.Location,
+		PromoCode:         input.PromoCode,
+		PreferredHelperID: input.PreferredHelperID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	s.series[id] = series
+
+	if created, err := s.generateOccurrencesLocked(series); err != nil {
+		if created == 0 {
+			delete(s.series, id)
+			return nil, err
+		}
+		s.notifyOccurrenceFailureLocked(series, err)
+	}
+	return s.seriesDetailLocked(series), nil
+}
+
+func (s *Store) ListSeries(_ context.Context, userID string) ([]models.RequestSeries, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]models.RequestSeries, 0)
+	for _, series := range s.series {
+		if series.RequesterID == userID {
+			results = append(results, *series)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+func (s *Store) GetSeries(_ context.Context, userID, seriesID string) (*models.RequestSeries, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series, ok := s.series[seriesID]
+	if !ok || series.RequesterID != userID {
+		return nil, errSeriesNotFound
+	}
+	return s.seriesDetailLocked(series), nil
+}
+
+// SkipOccurrence skips one occurrence of the series. An occurrence that has
+// already been generated is cancelled like any other request, so the
+// cancellation policy applies if a helper has accepted it.
+func (s *Store) SkipOccurrence(ctx context.Context, userID, seriesID string, input models.SkipOccurrenceInput) (*models.RequestSeries, error) {
+	series, plan, err := s.planSkip(userID, seriesID, input.Occurrence)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		if _, err := s.cancel(ctx, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series.SkippedOccurrences = append(series.SkippedOccurrences, input.Occurrence)
+	series.UpdatedAt = s.now()
+	return s.seriesDetailLocked(series), nil
+}
+
+// planSkip checks the occurrence can be skipped and plans the cancellation
+// of its request if it has already been generated.
+func (s *Store) planSkip(userID, seriesID string, occurrence time.Time) (*models.RequestSeries, *cancelPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[seriesID]
+	if !ok || series.RequesterID != userID {
+		return nil, nil, errSeriesNotFound
+	}
+	if series.Status != "ACTIVE" {
+		return nil, nil, fmt.Errorf("%w: series is %s", services.ErrInvalidState, series.Status)
+	}
+
+	rule, err := recurrence.Parse(series.Recurrence)
+	if err != nil {
+		return nil, nil, err
+	}
+	matches := rule.Between(series.StartsAt, occurrence.Add(-time.Nanosecond), occurrence)
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%s is not an occurrence of this series", occurrence.Format(time.RFC3339))
+	}
+	if !occurrence.After(s.now()) {
+		return nil, nil, fmt.Errorf("%w: occurrence has already started", services.ErrInvalidState)
+	}
+	if seriesSkips(series, occurrence) {
+		return nil, nil, fmt.Errorf("%w: occurrence already skipped", services.ErrInvalidState)
+	}
+
+	req := s.occurrenceRequestLocked(series.ID, occurrence)
+	if req == nil || req.Status == "CANCELLED" {
+		return series, nil, nil
+	}
+	plan, err := s.planCancelLocked(req, "SEEKER", "CHANGED_PLANS", "occurrence skipped")
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, plan, nil
+}
+
+// CancelSeries stops generating occurrences and cancels every upcoming one.
+// The series stays active if any occurrence could not be cancelled, so the
+// seeker can try again.
+func (s *Store) CancelSeries(ctx context.Context, userID, seriesID string, input models.CancelSeriesInput) (*models.RequestSeries, error) {
+	series, plans, err := s.planSeriesCancel(userID, seriesID, input)
+	if err != nil {
+		return nil, err
+	}
+	sent := make([]error, len(plans))
+	for i, plan := range plans {
+		sent[i] = s.sendRefund(ctx, plan.refund)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for i, plan := range plans {
+		s.releaseRequestLocked(plan.req.ID)
+		err := sent[i]
+		if err == nil {
+			err = s.applyCancelLocked(plan)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("request %s: %w", plan.req.ID, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	series.Status = "CANCELLED"
+	series.UpdatedAt = s.now()
+	return s.seriesDetailLocked(series), nil
+}
+
+// planSeriesCancel plans the cancellation of every upcoming occurrence of
+// the series. If any cannot be cancelled, none are.
+func (s *Store) planSeriesCancel(userID, seriesID string, input models.CancelSeriesInput) (*models.RequestSeries, []*cancelPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[seriesID]
+	if !ok || series.RequesterID != userID {
+		return nil, nil, errSeriesNotFound
+	}
+	if series.Status != "ACTIVE" {
+		return nil, nil, fmt.Errorf("%w: series is %s", services.ErrInvalidState, series.Status)
+	}
+
+	now := s.now()
+	var plans []*cancelPlan
+	for _, req := range s.requests {
+		if req.SeriesID != series.ID || req.ScheduledFor == nil || !req.ScheduledFor.After(now) {
+			continue
+		}
+		switch req.Status {
+		case "CANCELLED", "COMPLETED", "EXPIRED":
+			continue
+		}
+		plan, err := s.planCancelLocked(req, "SEEKER", input.Reason, "series cancelled")
+		if err != nil {
+			for _, plan := range plans {
+				s.releaseRequestLocked(plan.req.ID)
+			}
+			return nil, nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return series, plans, nil
+}
+
+// GenerateSeriesOccurrences creates the requests for every active series'
+// occurrences that have come within the lookahead window.
+func (s *Store) GenerateSeriesOccurrences(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created := 0
+	var errs []error
+	for _, series := range s.series {
+		if series.Status != "ACTIVE" {
+			continue
+		}
+		n, err := s.generateOccurrencesLocked(series)
+		created += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("series %s: %w", series.ID, err))
+			s.notifyOccurrenceFailureLocked(series, err)
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+// generateOccurrencesLocked creates a PLANNED request for each occurrence
+// between the last generated one and the end of the lookahead window,
+// skipping occurrences the seeker skipped or that have already passed. An
+// occurrence that cannot be created is passed over so it does not hold back
+// later ones; its error is returned with the others.
+func (s *Store) generateOccurrencesLocked(series *models.RequestSeries) (int, error) {
+	rule, err := recurrence.Parse(series.Recurrence)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	lookahead := seriesLookahead
+	if s.scheduling.BookingHorizon < lookahead {
+		lookahead = s.scheduling.BookingHorizon
+	}
+	after := series.StartsAt.Add(-time.Nanosecond)
+	if series.GeneratedThrough != nil {
+		after = *series.GeneratedThrough
+	}
+
+	created := 0
+	var errs []error
+	for _, occurrence := range rule.Between(series.StartsAt, after, now.Add(lookahead)) {
+		if occurrence.After(now) && !seriesSkips(series, occurrence) {
+			req, err := s.createLocked(series.RequesterID, models.CreateHelpRequestInput{
+				Type:         "PLANNED",
+				Category:     series.Category,
+				Description:  series.Description,
+				Location:     &series.Location,
+				ScheduledFor: &occurrence,
+				PromoCode:    series.PromoCode,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("occurrence %s: %w", occurrence.Format(time.RFC3339), err))
+			} else {
+				req.SeriesID = series.ID
+				req.SeriesOccurrence = &occurrence
+				req.PreferredHelperID = series.PreferredHelperID
+				s.submitLocked(req)
+				created++
+			}
+		}
+		series.GeneratedThrough = &occurrence
+		series.UpdatedAt = now
+	}
+	return created, errors.Join(errs...)
+}
+
+func (s *Store) notifyOccurrenceFailureLocked(series *models.RequestSeries, err error) {
+	s.notifyLocked(series.RequesterID, "SERIES_OCCURRENCE_FAILED", "Recurring request not booked",
+		fmt.Sprintf("We could not book an upcoming occurrence of your recurring request: %v", err),
+		map[string]string{"seriesId": series.ID})
+}
+
+func (s *Store) occurrenceRequestLocked(seriesID string, occurrence time.Time) *models.HelpRequest {
+	for _, req := range s.requests {
+		if req.SeriesID == seriesID && req.SeriesOccurrence != nil && req.SeriesOccurrence.Equal(occurrence) {
+			return req
+		}
+	}
+	return nil
+}
+
+// seriesDetailLocked copies the series together with its generated
+// occurrences, soonest first.
+func (s *Store) seriesDetailLocked(series *models.RequestSeries) *models.RequestSeries {
+	detail := *series
+	detail.Occurrences = nil
+	for _, req := range s.requests {
+		if req.SeriesID == series.ID {
+			detail.Occurrences = append(detail.Occurrences, *req)
+		}
+	}
+	sort.Slice(detail.Occurrences, func(i, j int) bool {
+		return detail.Occurrences[i].SeriesOccurrence.Before(*detail.Occurrences[j].SeriesOccurrence)
+	})
+	return &detail
+}
+
+func seriesSkips(series *models.RequestSeries, occurrence time.Time) bool {
+	for _, skipped := range series.SkippedOccurrences {
+		if skipped.Equal(occurrence) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,19 @@ package memory
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/MuhibNayem/community-helper-app/internal/domain/cancellation"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/ledger"
 	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/pricing"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
 )
 
 var (
@@ -18,38 +24,154 @@ var (
 	errUnauthorized    = errors.New("unauthorized")
 )
 
+const (
+	maxHelperPause = 30 * 24 * time.Hour
+
+	// matchWindow and completionWindow set a submitted request's SLA.
+	matchWindow      = 15 * time.Minute
+	completionWindow = 6 * time.Hour
+)
+
+// matchProgress ranks the statuses of an accepted match. A helper may move
+// a match forward, skipping steps, but never back; ACCEPTED is only reached
+// through Accept, which opens the payment.
+var matchProgress = map[string]int{
+	"ACCEPTED":    1,
+	"EN_ROUTE":    2,
+	"ARRIVED":     3,
+	"IN_PROGRESS": 4,
+	"COMPLETED":   5,
+}
+
 type Store struct {
 	mu sync.RWMutex
 
 	now func() time.Time
 
+	kycPolicy          KYCPolicy
+	scheduling         SchedulingPolicy
+	payoutPolicy       PayoutPolicy
+	cancellationPolicy cancellation.Policy
+	adminPhones        map[string]bool
+	objects            ObjectStorage
+	pricing            *pricing.Engine
+	quoteSigner        *pricing.QuoteSigner
+	quoteTTL           time.Duration
+	idempotencyTTL     time.Duration
+	gateway            gateway.Gateway
+	ledger             *ledger.Ledger
+
 	users          map[string]*models.User
 	helperProfiles map[string]*models.HelperProfile
 	kycDocuments   map[string]*models.KYCDocument
 	requests       map[string]*models.HelpRequest
 	matches        map[string]*models.MatchSession
+	uploads        map[string]*models.UploadTicket
+	places         map[string]*models.SavedPlace
+	series         map[string]*models.RequestSeries
+	payments       map[string]*models.Payment
+	payouts        map[string]*models.Payout
+	paymentMethods map[string]*models.PaymentMethod
+	disputes       map[string]*models.Dispute
+	idempotency    map[models.IdempotencyScope]*models.IdempotencyRecord
+	webhooks       map[string]*models.WebhookEvent
+	// webhookKeys indexes the inbox by provider and provider event ID.
+	webhookKeys map[string]string
+	// payoutDestinations holds each helper's payout account by helper ID.
+	payoutDestinations map[string]*models.PayoutDestination
+	// payoutsDueAt is when the next payout batch runs.
+	payoutsDueAt  time.Time
+	notifications []*models.Notification
+	kycReminders  map[string]time.Duration
+	// gatewayEvents records the gateway webhook IDs already applied.
+	gatewayEvents map[string]bool
+	// reserved holds the requests with a gateway call in flight; see
+	// reserveRequestLocked.
+	reserved map[string]bool
+	// busyUploads holds the upload keys whose objects are being written or
+	// deleted without s.mu.
+	busyUploads map[string]bool
+	// scheduleReminders records the tightest reminder lead time already
+	// sent for each scheduled request.
+	scheduleReminders map[string]time.Duration
 
 	otps          map[string]string
 	sessions      map[string]*models.Session
 	refreshTokens map[string]string
 
-	nextRequestID int
-	nextMatchID   int
+	nextRequestID       int
+	nextMatchID         int
+	nextPlaceID         int
+	nextSeriesID        int
+	nextPaymentID       int
+	nextPayoutID        int
+	nextPayoutBatchID   int
+	nextPaymentMethodID int
+	nextDisputeID       int
+	nextWebhookID       int
 }
 
 func NewStore() *Store {
+	engine, err := pricing.NewEngine(pricing.DefaultPriceBook())
+	if err != nil {
+		panic(fmt.Sprintf("default price book: %v", err))
+	}
+
+	quoteKey := make([]byte, 32)
+	if _, err := rand.Read(quoteKey); err != nil {
+		panic(fmt.Sprintf("generate quote key: %v", err))
+	}
+	webhookSecret := make([]byte, 32)
+	if _, err := rand.Read(webhookSecret); err != nil {
+		panic(fmt.Sprintf("generate webhook secret: %v", err))
+	}
+
 	return &Store{
-		now:            time.Now,
-		users:          make(map[string]*models.User),
-		helperProfiles: make(map[string]*models.HelperProfile),
-		kycDocuments:   make(map[string]*models.KYCDocument),
-		requests:       make(map[string]*models.HelpRequest),
-		matches:        make(map[string]*models.MatchSession),
-		otps:           make(map[string]string),
-		sessions:       make(map[string]*models.Session),
-		refreshTokens:  make(map[string]string),
-		nextRequestID:  1,
-		nextMatchID:    1,
+		now:                 time.Now,
+		cancellationPolicy:  cancellation.DefaultPolicy(),
+		scheduling:          DefaultSchedulingPolicy(),
+		payoutPolicy:        DefaultPayoutPolicy(),
+		pricing:             engine,
+		quoteSigner:         pricing.NewQuoteSigner(quoteKey),
+		quoteTTL:            defaultQuoteTTL,
+		idempotencyTTL:      defaultIdempotencyTTL,
+		gateway:             gateway.NewFake(webhookSecret),
+		ledger:              ledger.New(),
+		users:               make(map[string]*models.User),
+		helperProfiles:      make(map[string]*models.HelperProfile),
+		adminPhones:         make(map[string]bool),
+		kycDocuments:        make(map[string]*models.KYCDocument),
+		requests:            make(map[string]*models.HelpRequest),
+		matches:             make(map[string]*models.MatchSession),
+		uploads:             make(map[string]*models.UploadTicket),
+		places:              make(map[string]*models.SavedPlace),
+		series:              make(map[string]*models.RequestSeries),
+		payments:            make(map[string]*models.Payment),
+		payouts:             make(map[string]*models.Payout),
+		paymentMethods:      make(map[string]*models.PaymentMethod),
+		disputes:            make(map[string]*models.Dispute),
+		idempotency:         make(map[models.IdempotencyScope]*models.IdempotencyRecord),
+		webhooks:            make(map[string]*models.WebhookEvent),
+		webhookKeys:         make(map[string]string),
+		payoutDestinations:  make(map[string]*models.PayoutDestination),
+		kycReminders:        make(map[string]time.Duration),
+		gatewayEvents:       make(map[string]bool),
+		reserved:            make(map[string]bool),
+		busyUploads:         make(map[string]bool),
+		scheduleReminders:   make(map[string]time.Duration),
+		otps:                make(map[string]string),
+		sessions:            make(map[string]*models.Session),
+		refreshTokens:       make(map[string]string),
+		nextRequestID:       1,
+		nextMatchID:         1,
+		nextPlaceID:         1,
+		nextSeriesID:        1,
+		nextPaymentID:       1,
+		nextPayoutID:        1,
+		nextPayoutBatchID:   1,
+		nextPaymentMethodID: 1,
+		nextDisputeID:       1,
+		nextWebhookID:       1,
 	}
 }
 
@@ -58,6 +180,15 @@ func (s *Store) withNow(now func() time.Time) *Store {
 	return s
 }
 
+// WithAdminPhones grants the ADMIN role to users signing in with any of the
+// given phone numbers.
+func (s *Store) WithAdminPhones(phones []string) *Store {
+	for _, phone := range phones {
+		s.adminPhones[phone] = true
+	}
+	return s
+}
+
 // AuthService implementation
 
 func (s *Store) RequestOTP(_ context.Context, req models.OTPRequest) (models.OTPRequestResponse, error) {
@@ -177,6 +308,7 @@ func (s *Store) ToggleHelper(_ context.Context, userID string, toggle models.Hel
 	} else {
 		user.HelperStatus = "INACTIVE"
 	}
+	user.PausedUntil = nil
 	user.UpdatedAt = s.now()
 
 	profile := s.ensureHelperProfile(userID)
@@ -187,6 +319,87 @@ func (s *Store) ToggleHelper(_ context.Context, userID string, toggle models.Hel
 	return &copied, nil
 }
 
+func (s *Store) PauseHelper(_ context.Context, userID string, input models.HelperPauseInput) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, errUserNotFound
+	}
+
+	if !user.IsHelper {
+		return nil, fmt.Errorf("helper mode is not enabled")
+	}
+
+	now := s.now()
+	var until time.Time
+	switch {
+	case input.Until != nil && input.DurationMinutes > 0:
+		return nil, fmt.Errorf("provide either until or durationMinutes, not both")
+	case input.Until != nil:
+		until = *input.Until
+	case input.DurationMinutes > 0:
+		until = now.Add(time.Duration(input.DurationMinutes) * time.Minute)
+	default:
+		return nil, fmt.Errorf("until or durationMinutes required")
+	}
+
+	if !until.After(now) {
+		return nil, fmt.Errorf("pause must end in the future")
+	}
+	if until.Sub(now) > maxHelperPause {
+		return nil, fmt.Errorf("pause cannot exceed %d days", int(maxHelperPause.Hours()/24))
+	}
+
+	user.HelperStatus = "PAUSED"
+	user.PausedUntil = &until
+	user.UpdatedAt = now
+
+	copied := *user
+	return &copied, nil
+}
+
+func (s *Store) ResumeHelper(_ context.Context, userID string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, errUserNotFound
+	}
+
+	if user.HelperStatus != "PAUSED" {
+		return nil, fmt.Errorf("helper is not paused")
+	}
+
+	s.resumeHelperLocked(user)
+
+	copied := *user
+	return &copied, nil
+}
+
+// ResumeExpiredPauses reactivates helpers whose quick pause has run out.
+func (s *Store) ResumeExpiredPauses(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	resumed := 0
+	for _, user := range s.users {
+		if user.HelperStatus != "PAUSED" || user.PausedUntil == nil {
+			continue
+		}
+		if user.PausedUntil.After(now) {
+			continue
+		}
+		s.resumeHelperLocked(user)
+		resumed++
+	}
+
+	return resumed, nil
+}
+
 func (s *Store) UpdateSkills(_ context.Context, userID string, update models.SkillsUpdate) (*models.HelperProfile, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -250,21 +463,33 @@ func (s *Store) UploadKYC(_ context.Context, userID string, upload models.KYCDoc
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if upload.DocumentType == "" || upload.FileURL == "" {
-		return nil, fmt.Errorf("document type and fileUrl required")
+	if upload.DocumentType == "" || upload.FileKey == "" {
+		return nil, fmt.Errorf("document type and fileKey required")
+	}
+
+	if upload.ExpiresAt != nil && !upload.ExpiresAt.After(s.now()) {
+		return nil, fmt.Errorf("document has already expired")
 	}
 
 	id := fmt.Sprintf("kyc-%d", len(s.kycDocuments)+1)
+	ticket, err := s.claimUploadLocked(userID, "KYC", upload.FileKey, id)
+	if err != nil {
+		return nil, err
+	}
+
 	doc := &models.KYCDocument{
 		ID:           id,
 		UserID:       userID,
 		DocumentType: upload.DocumentType,
-		FileURL:      upload.FileURL,
+		FileKey:      ticket.Key,
+		ContentType:  ticket.ContentType,
 		Status:       "PENDING",
+		ExpiresAt:    upload.ExpiresAt,
 		SubmittedAt:  s.now(),
 	}
 
 	s.kycDocuments[id] = doc
+	s.refreshKYCStatusLocked(userID)
 
 	copyDoc := *doc
 	return &copyDoc, nil
@@ -276,76 +501,105 @@ func (s *Store) Create(_ context.Context, userID string, input models.CreateHelp
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	request, err := s.createLocked(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Draft {
+		s.submitLocked(request)
+	}
+
+	copyRequest := *request
+	return &copyRequest, nil
+}
+
+// createLocked validates, prices and stores a new draft request. Callers
+// must hold s.mu for writing.
+func (s *Store) createLocked(userID string, input models.CreateHelpRequestInput) (*models.HelpRequest, error) {
+	if len(input.Attachments) > maxRequestAttachments {
+		return nil, fmt.Errorf("at most %d attachments allowed", maxRequestAttachments)
+	}
+	if err := s.checkClaimableLocked(userID, "ATTACHMENT", input.Attachments); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateScheduleLocked(input.Type, input.ScheduledFor); err != nil {
+		return nil, err
+	}
+	if err := s.resolvePlaceLocked(userID, &input); err != nil {
+		return nil, err
+	}
+	quote, err := s.pricingForRequestLocked(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	paymentMethodID, err := s.resolvePaymentMethodLocked(userID, input.PaymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
 	id := fmt.Sprintf("req-%d", s.nextRequestID)
 	s.nextRequestID++
+	for _, key := range input.Attachments {
+		if _, err := s.claimUploadLocked(userID, "ATTACHMENT", key, id); err != nil {
+			return nil, err
+		}
+	}
 
 	now := s.now()
 	request := &models.HelpRequest{
-		ID:          id,
-		RequesterID: userID,
-		Type:        input.Type,
-		Status:      "SUBMITTED",
-		Category:    input.Category,
-		Description: input.Description,
-		Attachments: append([]string{}, input.Attachments...),
-		Location: models.RequestLocation{
-			Latitude:  input.Location.Latitude,
-			Longitude: input.Location.Longitude,
-			Address:   input.Location.Address,
-			PlaceID:   input.Location.PlaceID,
-			Accuracy:  input.Location.Accuracy,
-		},
-		ScheduledFor: input.ScheduledFor,
-		CreatedAt:    now,
-		UpdatedAt:    now,
-		SLA: models.SLAWindows{
-			MatchDeadline:      now.Add(15 * time.Minute),
-			CompletionDeadline: now.Add(6 * time.Hour),
-		},
-		Pricing: models.Pricing{
-			EstimatedAmount: 500,
-			Currency:        "BDT",
-			PlatformFee:     50,
-		},
+		ID:              id,
+		RequesterID:     userID,
+		Type:            input.Type,
+		Status:          "DRAFT",
+		Category:        input.Category,
+		Description:     input.Description,
+		Attachments:     append([]string{}, input.Attachments...),
+		Location:        *input.Location,
+		ScheduledFor:    input.ScheduledFor,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Pricing:         quote,
+		PromoCode:       input.PromoCode,
+		PaymentMethodID: paymentMethodID,
 	}
 
 	s.requests[id] = request
-
-	copyRequest := *request
-	return &copyRequest, nil
+	return request, nil
 }
 
-func (s *Store) List(_ context.Context, userID string, filter models.RequestListFilter) ([]models.HelpRequest, error) {
+func (s *Store) List(_ context.Context, userID string, filter models.RequestListFilter) (*models.RequestPage, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	statuses := statusSet(filter.Status)
 	var results []models.HelpRequest
 	for _, req := range s.requests {
 		if req.RequesterID != userID {
 			continue
 		}
-		if filter.Status != "" && req.Status != filter.Status {
+		if filter.Type != "" && req.Type != filter.Type {
+			continue
+		}
+		if filter.Category != "" && req.Category != filter.Category {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[req.Status] {
+			continue
+		}
+		if !withinRange(req.CreatedAt, filter.From, filter.To) {
 			continue
 		}
 		results = append(results, *req)
 	}
 
-	limit := filter.Limit
-	if limit <= 0 {
-		return results, nil
-	}
-
-	offset := filter.Offset
-	if offset >= len(results) {
-		return []models.HelpRequest{}, nil
-	}
-
-	end := offset + limit
-	if end > len(results) {
-		end = len(results)
+	page, next, err := paginate(results, func(req models.HelpRequest) cursorKey {
+		return cursorKey{at: req.CreatedAt, id: req.ID}
+	}, filter.Order, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, err
 	}
-
-	return results[offset:end], nil
+	return &models.RequestPage{Data: page, NextCursor: next}, nil
 }
 
 func (s *Store) Get(_ context.Context, userID, requestID string) (*models.HelpRequest, error) {
@@ -361,7 +615,15 @@ func (s *Store) Get(_ context.Context, userID, requestID string) (*models.HelpRe
 	return &copyReq, nil
 }
 
-func (s *Store) Cancel(_ context.Context, userID, requestID string, input models.CancelRequestInput) (*models.HelpRequest, error) {
+func (s *Store) Cancel(ctx context.Context, userID, requestID string, input models.CancelRequestInput) (*models.HelpRequest, error) {
+	plan, err := s.planSeekerCancel(userID, requestID, input)
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, plan)
+}
+
+func (s *Store) planSeekerCancel(userID, requestID string, input models.CancelRequestInput) (*cancelPlan, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -369,23 +631,7 @@ func (s *Store) Cancel(_ context.Context, userID, requestID string, input models
 	if !ok || req.RequesterID != userID {
 		return nil, errRequestNotFound
 	}
-
-	if req.Status == "COMPLETED" {
-		return nil, fmt.Errorf("cannot cancel completed request")
-	}
-
-	now := s.now()
-	req.Status = "CANCELLED"
-	req.Cancellation = &models.Cancellation{
-		Reason:         input.Reason,
-		Initiator:      "SEEKER",
-		Timestamp:      now,
-		PenaltyApplied: false,
-	}
-	req.UpdatedAt = now
-
-	copyReq := *req
-	return &copyReq, nil
+	return s.planCancelLocked(req, "SEEKER", input.Reason, input.Details)
 }
 
 func (s *Store) RateHelper(_ context.Context, userID, requestID string, rating models.RateRequest) error {
@@ -407,30 +653,51 @@ func (s *Store) RateHelper(_ context.Context, userID, requestID string, rating m
 
 // MatchService implementation
 
-func (s *Store) ListInvitations(_ context.Context, helperID string, status string) ([]models.MatchSession, error) {
+func (s *Store) ListInvitations(_ context.Context, helperID string, filter models.InvitationListFilter) (*models.InvitationPage, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	statuses := statusSet(filter.Status)
 	var matches []models.MatchSession
 	for _, match := range s.matches {
 		if match.HelperID != helperID {
 			continue
 		}
-		if status != "" && match.Status != status {
+		if len(statuses) > 0 && !statuses[match.Status] {
+			continue
+		}
+		if !withinRange(match.InvitedAt, filter.From, filter.To) {
 			continue
 		}
 		matches = append(matches, *match)
 	}
-	return matches, nil
+
+	page, next, err := paginate(matches, func(match models.MatchSession) cursorKey {
+		return cursorKey{at: match.InvitedAt, id: match.ID}
+	}, filter.Order, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InvitationPage{Data: page, NextCursor: next}, nil
 }
 
-func (s *Store) Accept(_ context.Context, helperID, matchID string) (*models.MatchSession, error) {
+func (s *Store) Accept(ctx context.Context, helperID, matchID string) (*models.MatchSession, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	match, payment, intent, err := s.openAcceptLocked(helperID, matchID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
 
-	match, ok := s.matches[matchID]
-	if !ok || match.HelperID != helperID {
-		return nil, errMatchNotFound
+	if intent != nil {
+		err = s.chargePayment(ctx, payment, *intent)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseRequestLocked(match.RequestID)
+	if err != nil {
+		return nil, err
 	}
 
 	now := s.now()
@@ -440,6 +707,8 @@ func (s *Store) Accept(_ context.Context, helperID, matchID string) (*models.Mat
 
 	if req, ok := s.requests[match.RequestID]; ok {
 		req.Status = "ACCEPTED"
+		req.PreferredHelperID = ""
+		req.PreferredUntil = nil
 		req.UpdatedAt = now
 	}
 
@@ -447,6 +716,40 @@ func (s *Store) Accept(_ context.Context, helperID, matchID string) (*models.Mat
 	return &copyMatch, nil
 }
 
+// openAcceptLocked checks the helper may accept the match, reserves its
+// request and opens the payment that Accept charges.
+func (s *Store) openAcceptLocked(helperID, matchID string) (*models.MatchSession, *models.Payment, *gateway.IntentRequest, error) {
+	match, ok := s.matches[matchID]
+	if !ok || match.HelperID != helperID {
+		return nil, nil, nil, errMatchNotFound
+	}
+	if match.Status != "INVITED" {
+		return nil, nil, nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+	if active := s.activeMatchLocked(match.RequestID); active != nil {
+		return nil, nil, nil, fmt.Errorf("%w: request already matched", services.ErrInvalidState)
+	}
+
+	req, ok := s.requests[match.RequestID]
+	if !ok {
+		return nil, nil, nil, errRequestNotFound
+	}
+	if helper, ok := s.users[helperID]; ok {
+		if err := s.checkKYCPolicyLocked(helper, req); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if err := s.reserveRequestLocked(req.ID); err != nil {
+		return nil, nil, nil, err
+	}
+	payment, intent, err := s.openPaymentLocked(req, match)
+	if err != nil {
+		s.releaseRequestLocked(req.ID)
+		return nil, nil, nil, err
+	}
+	return match, payment, intent, nil
+}
+
 func (s *Store) Decline(_ context.Context, helperID, matchID string, input models.DeclineMatchInput) (*models.MatchSession, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -455,12 +758,22 @@ func (s *Store) Decline(_ context.Context, helperID, matchID string, input model
 	if !ok || match.HelperID != helperID {
 		return nil, errMatchNotFound
 	}
+	if match.Status != "INVITED" {
+		return nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+	if err := s.checkUnreservedLocked(match.RequestID); err != nil {
+		return nil, err
+	}
 
 	now := s.now()
 	match.Status = "DECLINED"
 	match.DeclineReason = input.Reason
 	match.RespondedAt = &now
 
+	if req, ok := s.requests[match.RequestID]; ok && req.PreferredHelperID == helperID {
+		s.clearPreferenceLocked(req)
+	}
+
 	copyMatch := *match
 	return &copyMatch, nil
 }
@@ -473,13 +786,17 @@ func (s *Store) UpdateStatus(_ context.Context, helperID, matchID string, input
 	if !ok || match.HelperID != helperID {
 		return nil, errMatchNotFound
 	}
+	if from, to := matchProgress[match.Status], matchProgress[input.Status]; from == 0 || to <= from {
+		return nil, fmt.Errorf("%w: match is %s", services.ErrInvalidState, match.Status)
+	}
+	if err := s.checkUnreservedLocked(match.RequestID); err != nil {
+		return nil, err
+	}
 
 	now := s.now()
 	match.Status = input.Status
 
 	switch input.Status {
-	case "EN_ROUTE":
-		match.ArrivedAt = nil
 	case "ARRIVED":
 		match.ArrivedAt = &now
 	case "COMPLETED":
@@ -523,6 +840,9 @@ func (s *Store) ensureUser(phone string) *models.User {
 		HelperStatus: "ACTIVE",
 		KYCStatus:    "PENDING",
 	}
+	if s.adminPhones[phone] {
+		user.Roles = []string{"ADMIN"}
+	}
 
 	s.users[id] = user
 	s.ensureHelperProfile(id)
@@ -538,12 +858,13 @@ func (s *Store) ensureHelperProfile(userID string) *models.HelperProfile {
 
 	now := s.now()
 	profile = &models.HelperProfile{
-		UserID:    userID,
-		Skills:    []string{"GENERAL_HELP"},
-		OptedIn:   true,
-		Rating:    5,
-		Badges:    []string{},
-		UpdatedAt: now,
+		UserID:           userID,
+		Skills:           []string{"GENERAL_HELP"},
+		OptedIn:          true,
+		Rating:           5,
+		ReliabilityScore: 100,
+		Badges:           []string{},
+		UpdatedAt:        now,
 		Availability: models.Availability{
 			Weekly: []models.AvailabilitySlot{
 				{Day: "MONDAY", Start: "09:00", End: "17:00"},
@@ -555,6 +876,12 @@ func (s *Store) ensureHelperProfile(userID string) *models.HelperProfile {
 	return profile
 }
 
+func (s *Store) resumeHelperLocked(user *models.User) {
+	user.HelperStatus = "ACTIVE"
+	user.PausedUntil = nil
+	user.UpdatedAt = s.now()
+}
+
 func (s *Store) createSession(userID string) *models.Session {
 	token := fmt.Sprintf("token-%s-%d", userID, time.Now().UnixNano())
 	refresh := fmt.Sprintf("refresh-%s-%d", userID, time.Now().UnixNano())
@@ -574,22 +901,25 @@ func (s *Store) createSession(userID string) *models.Session {
 	return session
 }
 
-func (s *Store) SeedMatch(helperID, requestID string) *models.MatchSession {
+// GrantRole adds role to the user, e.g. to seed operations staff.
+func (s *Store) GrantRole(userID, role string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	id := fmt.Sprintf("match-%d", s.nextMatchID)
-	s.nextMatchID++
-
-	now := s.now()
-	match := &models.MatchSession{
-		ID:        id,
-		RequestID: requestID,
-		HelperID:  helperID,
-		Status:    "INVITED",
-		InvitedAt: now,
+	user, ok := s.users[userID]
+	if !ok {
+		return errUserNotFound
 	}
+	if !user.HasRole(role) {
+		user.Roles = append(user.Roles, role)
+		user.UpdatedAt = s.now()
+	}
+	return nil
+}
+
+func (s *Store) SeedMatch(helperID, requestID string) *models.MatchSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	s.matches[id] = match
-	return match
+	return s.inviteLocked(helperID, requestID)
 }
@@ -0,0 +1,1940 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/ledger"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/money"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/pricing"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/reports"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/storage"
+)
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+func newTestStore(t *testing.T) (*Store, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{current: time.Date(2025, 2, 17, 9, 0, 0, 0, time.UTC)}
+
+	local, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("local storage: %v", err)
+	}
+
+	store := NewStore().withNow(clock.now).WithObjectStorage(ObjectStorage{
+		KYC:    local,
+		Signer: storage.NewSigner([]byte("test-signing-key")),
+	})
+	return store, clock
+}
+
+func uploadKYCFile(t *testing.T, store *Store, userID string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	ticket, err := store.IssueKYCUpload(ctx, userID)
+	if err != nil {
+		t.Fatalf("issue upload: %v", err)
+	}
+
+	sig := models.UploadSignature{
+		Expires:   ticket.ExpiresAt.Unix(),
+		Signature: store.objects.Signer.Sign(ticket.Method, ticket.Key, ticket.ExpiresAt),
+	}
+	if _, err := store.ReceiveUpload(ctx, ticket.Key, sig, []byte("%PDF-1.4\n%%EOF\n")); err != nil {
+		t.Fatalf("receive upload: %v", err)
+	}
+	return ticket.Key
+}
+
+func seedUser(t *testing.T, store *Store, phone string) *models.User {
+	t.Helper()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	user := store.ensureUser(phone)
+	copied := *user
+	return &copied
+}
+
+// invitations lists every match of helperID with the given status.
+func invitations(store *Store, helperID, status string) ([]models.MatchSession, error) {
+	page, err := store.ListInvitations(context.Background(), helperID, models.InvitationListFilter{Status: status, Limit: maxPageSize})
+	if err != nil {
+		return nil, err
+	}
+	return page.Data, nil
+}
+
+// acceptRequest has helperID accept their invitation to requestID.
+func acceptRequest(t *testing.T, store *Store, helperID, requestID string) *models.MatchSession {
+	t.Helper()
+	invites, _ := invitations(store, helperID, "INVITED")
+	for _, invite := range invites {
+		if invite.RequestID == requestID {
+			match, err := store.Accept(context.Background(), helperID, invite.ID)
+			if err != nil {
+				t.Fatalf("accept %s: %v", requestID, err)
+			}
+			return match
+		}
+	}
+	t.Fatalf("helper %s has no invitation to %s", helperID, requestID)
+	return nil
+}
+
+func testRequestInput(category string) models.CreateHelpRequestInput {
+	return models.CreateHelpRequestInput{
+		Type:        "URGENT",
+		Category:    category,
+		Description: "Need a hand",
+		Location:    &models.RequestLocation{Latitude: 23.78, Longitude: 90.36, Address: "Dhaka"},
+	}
+}
+
+func TestPausedHelperSkipsMatchingAndAutoResumes(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+
+	seeker := seedUser(t, store, "+8801000000100")
+	helper := seedUser(t, store, "+8801000000101")
+
+	paused, err := store.PauseHelper(ctx, helper.ID, models.HelperPauseInput{DurationMinutes: 120})
+	if err != nil {
+		t.Fatalf("pause helper: %v", err)
+	}
+	if paused.HelperStatus != "PAUSED" || paused.PausedUntil == nil {
+		t.Fatalf("expected paused helper, got %+v", paused)
+	}
+
+	input := testRequestInput("GENERAL_HELP")
+	if _, err := store.Create(ctx, seeker.ID, input); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	invites, _ := invitations(store, helper.ID, "")
+	if len(invites) != 0 {
+		t.Fatalf("paused helper should not be invited, got %d invitations", len(invites))
+	}
+
+	clock.advance(time.Hour)
+	if n, _ := store.ResumeExpiredPauses(ctx); n != 0 {
+		t.Fatalf("expected no resumes before expiry, got %d", n)
+	}
+
+	clock.advance(time.Hour)
+	if n, _ := store.ResumeExpiredPauses(ctx); n != 1 {
+		t.Fatalf("expected one resume after expiry, got %d", n)
+	}
+
+	current, _ := store.GetCurrentUser(ctx, helper.ID)
+	if current.HelperStatus != "ACTIVE" || current.PausedUntil != nil {
+		t.Fatalf("expected helper to be active again, got %+v", current)
+	}
+
+	if _, err := store.Create(ctx, seeker.ID, input); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	invites, _ = invitations(store, helper.ID, "")
+	if len(invites) != 1 {
+		t.Fatalf("expected resumed helper to be invited, got %d invitations", len(invites))
+	}
+}
+
+func TestKYCPolicyGatesUnverifiedHelpers(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	store.WithKYCPolicy(KYCPolicy{RestrictPaid: true})
+
+	seeker := seedUser(t, store, "+8801000000110")
+	helper := seedUser(t, store, "+8801000000111")
+	reviewer := seedUser(t, store, "+8801000000112")
+
+	req, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	if invites, _ := invitations(store, helper.ID, ""); len(invites) != 0 {
+		t.Fatalf("unverified helper should not be invited to paid request, got %d", len(invites))
+	}
+
+	match := store.SeedMatch(helper.ID, req.ID)
+	if _, err := store.Accept(ctx, helper.ID, match.ID); !errors.Is(err, services.ErrHelperNotEligible) {
+		t.Fatalf("expected ErrHelperNotEligible, got %v", err)
+	}
+	for _, status := range []string{"ACCEPTED", "COMPLETED"} {
+		if _, err := store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: status}); !errors.Is(err, services.ErrInvalidState) {
+			t.Fatalf("expected ErrInvalidState moving an invitation to %s, got %v", status, err)
+		}
+	}
+
+	fileKey := uploadKYCFile(t, store, helper.ID)
+	doc, err := store.UploadKYC(ctx, helper.ID, models.KYCDocumentUpload{DocumentType: "NID", FileKey: fileKey})
+	if err != nil {
+		t.Fatalf("upload kyc: %v", err)
+	}
+	if _, err := store.ReviewKYC(ctx, reviewer.ID, doc.ID, models.KYCReviewInput{Decision: "APPROVED"}); err != nil {
+		t.Fatalf("review kyc: %v", err)
+	}
+
+	if _, err := store.Accept(ctx, helper.ID, match.ID); err != nil {
+		t.Fatalf("verified helper accept: %v", err)
+	}
+}
+
+func TestKYCExpiryRemindersAndReverification(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	store.WithKYCPolicy(KYCPolicy{ExpiryReminders: []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}})
+
+	helper := seedUser(t, store, "+8801000000120")
+	reviewer := seedUser(t, store, "+8801000000121")
+
+	expiresAt := clock.now().Add(10 * 24 * time.Hour)
+	doc, err := store.UploadKYC(ctx, helper.ID, models.KYCDocumentUpload{
+		DocumentType: "DRIVING_LICENCE",
+		FileKey:      uploadKYCFile(t, store, helper.ID),
+		ExpiresAt:    &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("upload kyc: %v", err)
+	}
+	past := clock.now().Add(-time.Hour)
+	if _, err := store.ReviewKYC(ctx, reviewer.ID, doc.ID, models.KYCReviewInput{Decision: "APPROVED", ExpiresAt: &past}); err == nil {
+		t.Fatalf("expected a past expiry to be refused")
+	}
+	if got := store.kycDocuments[doc.ID].ExpiresAt; !got.Equal(expiresAt) {
+		t.Fatalf("expected a refused review to keep the uploaded expiry, got %v", got)
+	}
+	if _, err := store.ReviewKYC(ctx, reviewer.ID, doc.ID, models.KYCReviewInput{Decision: "APPROVED"}); err != nil {
+		t.Fatalf("review kyc: %v", err)
+	}
+
+	sweep := func(want int) {
+		t.Helper()
+		got, err := store.SweepKYCExpiry(ctx)
+		if err != nil {
+			t.Fatalf("sweep: %v", err)
+		}
+		if got != want {
+			t.Fatalf("sweep changed %d documents, want %d", got, want)
+		}
+	}
+
+	sweep(0)
+	clock.advance(4 * 24 * time.Hour)
+	sweep(1)
+	sweep(0)
+	clock.advance(5 * 24 * time.Hour)
+	sweep(1)
+
+	current, _ := store.GetCurrentUser(ctx, helper.ID)
+	if current.KYCStatus != "VERIFIED" {
+		t.Fatalf("expected helper verified before expiry, got %s", current.KYCStatus)
+	}
+
+	clock.advance(2 * 24 * time.Hour)
+	sweep(1)
+
+	current, _ = store.GetCurrentUser(ctx, helper.ID)
+	if current.KYCStatus != "REVERIFICATION_REQUIRED" {
+		t.Fatalf("expected re-verification after expiry, got %s", current.KYCStatus)
+	}
+
+	notifications, _ := store.ListNotifications(ctx, helper.ID)
+	if len(notifications) != 3 || notifications[0].Type != "KYC_EXPIRED" {
+		t.Fatalf("unexpected notifications: %+v", notifications)
+	}
+}
+
+func TestCleanupOrphanedUploads(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	helper := seedUser(t, store, "+8801000000130")
+
+	orphan := uploadKYCFile(t, store, helper.ID)
+	attached := uploadKYCFile(t, store, helper.ID)
+	if _, err := store.UploadKYC(ctx, helper.ID, models.KYCDocumentUpload{DocumentType: "NID", FileKey: attached}); err != nil {
+		t.Fatalf("upload kyc: %v", err)
+	}
+
+	if n, err := store.CleanupOrphanedUploads(ctx); err != nil || n != 0 {
+		t.Fatalf("early cleanup removed %d err=%v", n, err)
+	}
+
+	clock.advance(orphanedUploadTTL + time.Minute)
+	if n, err := store.CleanupOrphanedUploads(ctx); err != nil || n != 1 {
+		t.Fatalf("cleanup removed %d err=%v", n, err)
+	}
+
+	if _, err := store.objects.KYC.Get(ctx, orphan); !errors.Is(err, storage.ErrObjectNotFound) {
+		t.Fatalf("expected orphan object deleted, got %v", err)
+	}
+	if _, err := store.objects.KYC.Get(ctx, attached); err != nil {
+		t.Fatalf("attached object should remain: %v", err)
+	}
+}
+
+func TestPlacesListInCreationOrder(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000304")
+
+	// Every place shares the clock's time, so IDs break the tie.
+	var want []string
+	for i := 0; i < 12; i++ {
+		place, err := store.CreatePlace(ctx, seeker.ID, models.SavedPlaceInput{
+			Label:    "Home",
+			Location: models.RequestLocation{Latitude: 23.78, Longitude: 90.36, Address: "Dhaka"},
+		})
+		if err != nil {
+			t.Fatalf("create place: %v", err)
+		}
+		want = append(want, place.ID)
+	}
+
+	places, _ := store.ListPlaces(ctx, seeker.ID)
+	for i, place := range places {
+		if place.ID != want[i] {
+			t.Fatalf("place %d is %s, want %s", i, place.ID, want[i])
+		}
+	}
+}
+
+func TestQuoteIsHonouredAtCreation(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000140")
+	other := seedUser(t, store, "+8801000000141")
+
+	input := testRequestInput("GENERAL_HELP")
+	quote, err := store.CreateQuote(ctx, seeker.ID, input)
+	if err != nil {
+		t.Fatalf("create quote: %v", err)
+	}
+
+	revised := pricing.DefaultPriceBook()
+	revised.Version = "2025-02"
+	revised.EffectiveFrom = clock.now().Add(time.Minute)
+	revised.DefaultBaseFee = money.New(90000, "BDT")
+	if _, err := store.PublishPriceBook(ctx, revised); err != nil {
+		t.Fatalf("publish price book: %v", err)
+	}
+	clock.advance(5 * time.Minute)
+
+	input.QuoteID = quote.ID
+	req, err := store.Create(ctx, seeker.ID, input)
+	if err != nil {
+		t.Fatalf("create with quote: %v", err)
+	}
+	if req.Pricing.EstimatedAmount != quote.Pricing.EstimatedAmount || req.Pricing.PriceBookVersion != "2025-01" || req.Pricing.QuoteID != quote.ID {
+		t.Fatalf("request not priced as quoted: %+v", req.Pricing)
+	}
+
+	if _, err := store.Create(ctx, other.ID, input); !errors.Is(err, services.ErrQuoteInvalid) {
+		t.Fatalf("expected ErrQuoteInvalid for another user, got %v", err)
+	}
+
+	changed := input
+	changed.Category = "MEDICAL_FIRST_AID"
+	if _, err := store.Create(ctx, seeker.ID, changed); !errors.Is(err, services.ErrQuoteInvalid) {
+		t.Fatalf("expected ErrQuoteInvalid for changed request, got %v", err)
+	}
+
+	tampered := input
+	tampered.QuoteID = "x" + quote.ID
+	if _, err := store.Create(ctx, seeker.ID, tampered); !errors.Is(err, services.ErrQuoteInvalid) {
+		t.Fatalf("expected ErrQuoteInvalid for tampered quote, got %v", err)
+	}
+
+	quotedDraft := input
+	quotedDraft.Draft = true
+	draft, err := store.Create(ctx, seeker.ID, quotedDraft)
+	if err != nil {
+		t.Fatalf("create quoted draft: %v", err)
+	}
+
+	clock.advance(defaultQuoteTTL)
+	if _, err := store.Create(ctx, seeker.ID, input); !errors.Is(err, services.ErrQuoteExpired) {
+		t.Fatalf("expected ErrQuoteExpired, got %v", err)
+	}
+
+	// A draft whose quote has expired is priced afresh when submitted.
+	submitted, err := store.Submit(ctx, seeker.ID, draft.ID)
+	if err != nil {
+		t.Fatalf("submit draft with expired quote: %v", err)
+	}
+	if submitted.Pricing.QuoteID != "" || submitted.Pricing.PriceBookVersion != revised.Version {
+		t.Fatalf("expected the draft to be re-priced, got %+v", submitted.Pricing)
+	}
+}
+
+func TestCancellationPenalties(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000150")
+	helper := seedUser(t, store, "+8801000000151")
+
+	req, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	invites, _ := invitations(store, helper.ID, "INVITED")
+	if len(invites) != 1 {
+		t.Fatalf("expected one invitation, got %d", len(invites))
+	}
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if _, err := store.UpdateStatus(ctx, helper.ID, invites[0].ID, models.MatchStatusUpdate{Status: "EN_ROUTE"}); err != nil {
+		t.Fatalf("en route: %v", err)
+	}
+	clock.advance(10 * time.Minute)
+
+	cancelled, err := store.Cancel(ctx, seeker.ID, req.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	c := cancelled.Cancellation
+	if c.Initiator != "SEEKER" || c.Stage != "EN_ROUTE" || c.PenaltyRule != "HELPER_EN_ROUTE" || !c.PenaltyApplied || c.PenaltyAmount != money.New(15000, "BDT") || c.Currency != "BDT" {
+		t.Fatalf("unexpected cancellation: %+v", c)
+	}
+	if matches, _ := invitations(store, helper.ID, "CANCELLED"); len(matches) != 1 {
+		t.Fatalf("expected helper's match to be cancelled, got %d", len(matches))
+	}
+	if notes, _ := store.ListNotifications(ctx, helper.ID); len(notes) == 0 || notes[0].Type != "REQUEST_CANCELLED" {
+		t.Fatalf("expected helper to be notified, got %+v", notes)
+	}
+
+	if _, err := store.Cancel(ctx, seeker.ID, req.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState re-cancelling, got %v", err)
+	}
+
+	// A helper who arrives to find nobody there cancels through the match.
+	req, _ = store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ = invitations(store, helper.ID, "INVITED")
+	if len(invites) != 1 {
+		t.Fatalf("expected one new invitation, got %d", len(invites))
+	}
+	matchID := invites[0].ID
+	if _, err := store.CancelMatch(ctx, helper.ID, matchID, models.HelperCancelInput{Reason: "SEEKER_NO_SHOW"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState for unaccepted match, got %v", err)
+	}
+	store.Accept(ctx, helper.ID, matchID)
+	store.UpdateStatus(ctx, helper.ID, matchID, models.MatchStatusUpdate{Status: "ARRIVED"})
+
+	cancelled, err = store.CancelMatch(ctx, helper.ID, matchID, models.HelperCancelInput{Reason: "SEEKER_NO_SHOW"})
+	if err != nil {
+		t.Fatalf("helper cancel: %v", err)
+	}
+	c = cancelled.Cancellation
+	if cancelled.ID != req.ID || c.Initiator != "HELPER" || c.PenaltyRule != "SEEKER_NO_SHOW" || c.PenaltyAmount != money.New(30000, "BDT") {
+		t.Fatalf("unexpected helper cancellation: %+v", c)
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "REQUEST_CANCELLED" {
+		t.Fatalf("expected seeker to be notified, got %+v", notes)
+	}
+}
+
+func TestWithdrawReturnsRequestToMatching(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	fake := gateway.NewFake([]byte("whsec"))
+	store.WithGateway(fake)
+	seeker := seedUser(t, store, "+8801000000160")
+	first := seedUser(t, store, "+8801000000161")
+	second := seedUser(t, store, "+8801000000162")
+
+	req, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	invites, _ := invitations(store, first.ID, "INVITED")
+	if len(invites) != 1 {
+		t.Fatalf("expected one invitation, got %d", len(invites))
+	}
+	matchID := invites[0].ID
+
+	if _, err := store.Withdraw(ctx, first.ID, matchID, models.WithdrawMatchInput{Reason: "VEHICLE_ISSUE"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState withdrawing an invitation, got %v", err)
+	}
+	if _, err := store.Accept(ctx, first.ID, matchID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if _, err := store.Decline(ctx, first.ID, matchID, models.DeclineMatchInput{Reason: "busy"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState declining an accepted match, got %v", err)
+	}
+	others, _ := invitations(store, second.ID, "INVITED")
+	if _, err := store.Accept(ctx, second.ID, others[0].ID); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState accepting a matched request, got %v", err)
+	}
+
+	store.UpdateStatus(ctx, first.ID, matchID, models.MatchStatusUpdate{Status: "EN_ROUTE"})
+	fake.Script(gateway.OpRefund, gateway.Decline)
+	if _, err := store.Withdraw(ctx, first.ID, matchID, models.WithdrawMatchInput{Reason: "VEHICLE_ISSUE"}); err == nil {
+		t.Fatalf("expected a declined refund to fail the withdrawal")
+	}
+	if match := store.matches[matchID]; match.Status != "EN_ROUTE" {
+		t.Fatalf("expected match unchanged after a failed refund, got %s", match.Status)
+	}
+	if payment := store.paymentForMatchLocked(matchID); payment.Status != "HELD" {
+		t.Fatalf("expected payment still held, got %s", payment.Status)
+	}
+	withdrawn, err := store.Withdraw(ctx, first.ID, matchID, models.WithdrawMatchInput{Reason: "VEHICLE_ISSUE"})
+	if err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if withdrawn.Status != "WITHDRAWN" || withdrawn.WithdrawnAt == nil || withdrawn.DeclineReason != "VEHICLE_ISSUE" {
+		t.Fatalf("unexpected withdrawn match: %+v", withdrawn)
+	}
+
+	profile := store.helperProfiles[first.ID]
+	if profile.ReliabilityScore != 85 || profile.Withdrawals != 1 {
+		t.Fatalf("expected late withdrawal penalty, got score %d withdrawals %d", profile.ReliabilityScore, profile.Withdrawals)
+	}
+
+	current, _ := store.Get(ctx, seeker.ID, req.ID)
+	if current.Status != "SUBMITTED" {
+		t.Fatalf("expected request back in matching, got %s", current.Status)
+	}
+	if invites, _ := invitations(store, first.ID, "INVITED"); len(invites) != 0 {
+		t.Fatalf("withdrawn helper should not be re-invited, got %d", len(invites))
+	}
+	if _, err := store.Accept(ctx, second.ID, others[0].ID); err != nil {
+		t.Fatalf("second helper accept: %v", err)
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "HELPER_WITHDREW" {
+		t.Fatalf("expected seeker to be notified, got %+v", notes)
+	}
+}
+
+func TestDraftEditAndSubmit(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	store.WithKYCPolicy(KYCPolicy{RestrictedCategories: []string{"MEDICAL_FIRST_AID"}})
+	seeker := seedUser(t, store, "+8801000000170")
+	helper := seedUser(t, store, "+8801000000171")
+
+	input := testRequestInput("GENERAL_HELP")
+	input.Draft = true
+	draft, err := store.Create(ctx, seeker.ID, input)
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if draft.Status != "DRAFT" || draft.SubmittedAt != nil {
+		t.Fatalf("expected unsubmitted draft, got %+v", draft)
+	}
+	if invites, _ := invitations(store, helper.ID, ""); len(invites) != 0 {
+		t.Fatalf("drafts should not be matched, got %d invitations", len(invites))
+	}
+
+	description := "Carry groceries upstairs"
+	edited, err := store.Update(ctx, seeker.ID, draft.ID, models.UpdateHelpRequestInput{Description: &description})
+	if err != nil {
+		t.Fatalf("edit draft: %v", err)
+	}
+	if edited.Description != description || len(edited.History) != 1 || edited.History[0].Changes[0].Field != "description" {
+		t.Fatalf("expected recorded description edit, got %+v", edited.History)
+	}
+
+	submitted, err := store.Submit(ctx, seeker.ID, draft.ID)
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if submitted.Status != "SUBMITTED" || submitted.SubmittedAt == nil {
+		t.Fatalf("expected submitted request, got %+v", submitted)
+	}
+	if _, err := store.Submit(ctx, seeker.ID, draft.ID); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState resubmitting, got %v", err)
+	}
+	if invites, _ := invitations(store, helper.ID, "INVITED"); len(invites) != 1 {
+		t.Fatalf("expected helper to be invited on submit, got %d", len(invites))
+	}
+
+	// Moving the request into a category the unverified helper cannot take
+	// withdraws their invitation.
+	category := "MEDICAL_FIRST_AID"
+	edited, err = store.Update(ctx, seeker.ID, draft.ID, models.UpdateHelpRequestInput{Category: &category})
+	if err != nil {
+		t.Fatalf("edit category: %v", err)
+	}
+	if len(edited.History) != 2 {
+		t.Fatalf("expected two history entries, got %d", len(edited.History))
+	}
+	if invites, _ := invitations(store, helper.ID, "INVITED"); len(invites) != 0 {
+		t.Fatalf("expected invitation to be withdrawn, got %d", len(invites))
+	}
+
+	category = "GENERAL_HELP"
+	store.Update(ctx, seeker.ID, draft.ID, models.UpdateHelpRequestInput{Category: &category})
+	if invites, _ := invitations(store, helper.ID, "INVITED"); len(invites) != 1 {
+		t.Fatalf("expected helper to be invited again, got %d", len(invites))
+	}
+	other := seedUser(t, store, "+8801000000172")
+	store.SeedMatch(other.ID, draft.ID)
+	invites, _ := invitations(store, other.ID, "INVITED")
+	if _, err := store.Accept(ctx, other.ID, invites[0].ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if _, err := store.Update(ctx, seeker.ID, draft.ID, models.UpdateHelpRequestInput{Description: &description}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState editing a matched request, got %v", err)
+	}
+}
+
+func TestRepeatPrefersSameHelper(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000180")
+	favourite := seedUser(t, store, "+8801000000181")
+	other := seedUser(t, store, "+8801000000182")
+
+	original, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ := invitations(store, favourite.ID, "INVITED")
+	store.Accept(ctx, favourite.ID, invites[0].ID)
+	store.UpdateStatus(ctx, favourite.ID, invites[0].ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+
+	repeated, err := store.Repeat(ctx, seeker.ID, original.ID, models.RepeatRequestInput{PreferSameHelper: true})
+	if err != nil {
+		t.Fatalf("repeat: %v", err)
+	}
+	if repeated.PreferredHelperID != favourite.ID || repeated.PreferredUntil == nil {
+		t.Fatalf("expected preferred helper, got %+v", repeated)
+	}
+
+	countFor := func(helperID string) int {
+		count := 0
+		invites, _ := invitations(store, helperID, "INVITED")
+		for _, invite := range invites {
+			if invite.RequestID == repeated.ID {
+				count++
+			}
+		}
+		return count
+	}
+	if countFor(favourite.ID) != 1 || countFor(other.ID) != 0 {
+		t.Fatalf("expected only the preferred helper to be invited")
+	}
+
+	if n, _ := store.DispatchDue(ctx); n != 0 {
+		t.Fatalf("expected no widening inside the window, got %d", n)
+	}
+	clock.advance(preferredHelperWindow)
+	if n, _ := store.DispatchDue(ctx); n != 1 {
+		t.Fatalf("expected the request to be widened, got %d", n)
+	}
+	if countFor(other.ID) != 1 {
+		t.Fatalf("expected other helpers to be invited after the window")
+	}
+
+	// Declining as the preferred helper widens matching immediately.
+	again, _ := store.Repeat(ctx, seeker.ID, original.ID, models.RepeatRequestInput{PreferSameHelper: true})
+	var preferred string
+	invites, _ = invitations(store, favourite.ID, "INVITED")
+	for _, invite := range invites {
+		if invite.RequestID == again.ID {
+			preferred = invite.ID
+		}
+	}
+	if _, err := store.Decline(ctx, favourite.ID, preferred, models.DeclineMatchInput{Reason: "BUSY"}); err != nil {
+		t.Fatalf("decline: %v", err)
+	}
+	current, _ := store.Get(ctx, seeker.ID, again.ID)
+	if current.PreferredHelperID != "" {
+		t.Fatalf("expected preference to be cleared after decline")
+	}
+}
+
+func TestPlannedRequestScheduling(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000190")
+	helper := seedUser(t, store, "+8801000000191")
+
+	planned := func(at time.Time) models.CreateHelpRequestInput {
+		input := testRequestInput("GENERAL_HELP")
+		input.Type = "PLANNED"
+		input.ScheduledFor = &at
+		return input
+	}
+
+	urgent := testRequestInput("GENERAL_HELP")
+	soon := clock.now().Add(time.Hour)
+	urgent.ScheduledFor = &soon
+	if _, err := store.Create(ctx, seeker.ID, urgent); err == nil {
+		t.Fatalf("expected URGENT request with scheduledFor to be rejected")
+	}
+	if _, err := store.Create(ctx, seeker.ID, planned(clock.now().Add(-time.Minute))); err == nil {
+		t.Fatalf("expected past scheduledFor to be rejected")
+	}
+	if _, err := store.Create(ctx, seeker.ID, planned(clock.now().Add(31*24*time.Hour))); err == nil {
+		t.Fatalf("expected scheduledFor beyond the horizon to be rejected")
+	}
+
+	slot := clock.now().Add(72 * time.Hour)
+	req, err := store.Create(ctx, seeker.ID, planned(slot))
+	if err != nil {
+		t.Fatalf("create planned: %v", err)
+	}
+	if req.Status != "SCHEDULED" || req.MatchingStartsAt == nil || !req.MatchingStartsAt.Equal(slot.Add(-24*time.Hour)) {
+		t.Fatalf("expected request parked until the matching lead, got %+v", req)
+	}
+	if !req.SLA.CompletionDeadline.Equal(slot.Add(completionWindow)) {
+		t.Fatalf("expected SLA relative to the slot, got %+v", req.SLA)
+	}
+	if invites, _ := invitations(store, helper.ID, ""); len(invites) != 0 {
+		t.Fatalf("expected no invitations before the lead time, got %d", len(invites))
+	}
+
+	clock.advance(48 * time.Hour)
+	if n, _ := store.DispatchDue(ctx); n != 1 {
+		t.Fatalf("expected matching to start, got %d", n)
+	}
+	invites, _ := invitations(store, helper.ID, "INVITED")
+	if len(invites) != 1 {
+		t.Fatalf("expected helper to be invited at the lead time, got %d", len(invites))
+	}
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	if n, _ := store.SweepScheduleReminders(ctx); n != 1 {
+		t.Fatalf("expected day-before reminder, got %d", n)
+	}
+	if n, _ := store.SweepScheduleReminders(ctx); n != 0 {
+		t.Fatalf("expected reminder to be sent once, got %d", n)
+	}
+	clock.advance(23 * time.Hour)
+	if n, _ := store.SweepScheduleReminders(ctx); n != 1 {
+		t.Fatalf("expected hour-before reminder, got %d", n)
+	}
+
+	for _, userID := range []string{seeker.ID, helper.ID} {
+		notes, _ := store.ListNotifications(ctx, userID)
+		reminders := 0
+		for _, note := range notes {
+			if note.Type == "SCHEDULE_REMINDER" {
+				reminders++
+			}
+		}
+		if reminders != 2 {
+			t.Fatalf("expected two reminders for %s, got %d", userID, reminders)
+		}
+	}
+
+	// Rescheduling a request starts its reminders over.
+	moved, err := store.Create(ctx, seeker.ID, planned(clock.now().Add(30*time.Minute)))
+	if err != nil {
+		t.Fatalf("create planned: %v", err)
+	}
+	if n, _ := store.SweepScheduleReminders(ctx); n != 1 {
+		t.Fatalf("expected hour-before reminder for the new request, got %d", n)
+	}
+	later := clock.now().Add(50 * time.Minute)
+	if _, err := store.Update(ctx, seeker.ID, moved.ID, models.UpdateHelpRequestInput{ScheduledFor: &later}); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if n, _ := store.SweepScheduleReminders(ctx); n != 1 {
+		t.Fatalf("expected a reminder for the new time, got %d", n)
+	}
+}
+
+func TestRecurringSeries(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000200")
+	favourite := seedUser(t, store, "+8801000000201")
+	other := seedUser(t, store, "+8801000000202")
+
+	start := clock.now().Add(24 * time.Hour)
+	input := testRequestInput("TUTORING")
+	series, err := store.CreateSeries(ctx, seeker.ID, models.CreateSeriesInput{
+		Category:          input.Category,
+		Description:       "Weekly maths tutoring",
+		Location:          input.Location,
+		StartsAt:          start,
+		Recurrence:        "FREQ=WEEKLY;COUNT=4",
+		PreferredHelperID: favourite.ID,
+	})
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	if len(series.Occurrences) != 2 {
+		t.Fatalf("expected two occurrences within the lookahead, got %d", len(series.Occurrences))
+	}
+	first, second := series.Occurrences[0], series.Occurrences[1]
+	if first.Type != "PLANNED" || !first.ScheduledFor.Equal(start) || first.SeriesID != series.ID {
+		t.Fatalf("unexpected first occurrence: %+v", first)
+	}
+	if first.PreferredHelperID != favourite.ID || first.PreferredUntil == nil {
+		t.Fatalf("expected preferred helper window on the first occurrence, got %+v", first)
+	}
+	if invites, _ := invitations(store, other.ID, ""); len(invites) != 0 {
+		t.Fatalf("expected only the preferred helper to be invited, got %d", len(invites))
+	}
+	if second.Status != "SCHEDULED" {
+		t.Fatalf("expected later occurrence to wait for its lead time, got %s", second.Status)
+	}
+
+	if _, err := store.SkipOccurrence(ctx, seeker.ID, series.ID, models.SkipOccurrenceInput{Occurrence: start.Add(time.Hour)}); err == nil {
+		t.Fatalf("expected error skipping a time that is not an occurrence")
+	}
+	skipped, err := store.SkipOccurrence(ctx, seeker.ID, series.ID, models.SkipOccurrenceInput{Occurrence: *second.ScheduledFor})
+	if err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	if skipped.Occurrences[1].Status != "CANCELLED" || len(skipped.SkippedOccurrences) != 1 {
+		t.Fatalf("expected skipped occurrence to be cancelled, got %+v", skipped.Occurrences[1])
+	}
+
+	clock.advance(7 * 24 * time.Hour)
+	if n, err := store.GenerateSeriesOccurrences(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one new occurrence, got %d (%v)", n, err)
+	}
+	// Skipping an occurrence before it is generated keeps it from being
+	// generated at all.
+	fourth := start.AddDate(0, 0, 21)
+	if _, err := store.SkipOccurrence(ctx, seeker.ID, series.ID, models.SkipOccurrenceInput{Occurrence: fourth}); err != nil {
+		t.Fatalf("skip ungenerated: %v", err)
+	}
+	clock.advance(7 * 24 * time.Hour)
+	if n, _ := store.GenerateSeriesOccurrences(ctx); n != 0 {
+		t.Fatalf("expected skipped occurrence not to be generated, got %d", n)
+	}
+
+	cancelled, err := store.CancelSeries(ctx, seeker.ID, series.ID, models.CancelSeriesInput{Reason: "CHANGED_PLANS"})
+	if err != nil {
+		t.Fatalf("cancel series: %v", err)
+	}
+	if cancelled.Status != "CANCELLED" {
+		t.Fatalf("expected cancelled series, got %s", cancelled.Status)
+	}
+	for _, occurrence := range cancelled.Occurrences {
+		if occurrence.ScheduledFor.After(clock.now()) && occurrence.Status != "CANCELLED" {
+			t.Fatalf("expected upcoming occurrence %s to be cancelled, got %s", occurrence.ID, occurrence.Status)
+		}
+	}
+	if _, err := store.CancelSeries(ctx, seeker.ID, series.ID, models.CancelSeriesInput{Reason: "CHANGED_PLANS"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState cancelling twice, got %v", err)
+	}
+}
+
+func TestSeriesOccurrenceFailures(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000293")
+
+	input := models.CreateSeriesInput{
+		Category:   "TUTORING",
+		Location:   testRequestInput("TUTORING").Location,
+		StartsAt:   clock.now().Add(24 * time.Hour),
+		Recurrence: "FREQ=WEEKLY;COUNT=4",
+		PromoCode:  "NOPE",
+	}
+	if _, err := store.CreateSeries(ctx, seeker.ID, input); !errors.Is(err, pricing.ErrUnknownPromoCode) {
+		t.Fatalf("expected ErrUnknownPromoCode, got %v", err)
+	}
+	if list, _ := store.ListSeries(ctx, seeker.ID); len(list) != 0 {
+		t.Fatalf("expected a rejected series not to be kept, got %+v", list)
+	}
+
+	input.PromoCode = ""
+	series, err := store.CreateSeries(ctx, seeker.ID, input)
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+
+	// An occurrence that can no longer be priced is passed over once and
+	// does not hold back the ones after it.
+	store.series[series.ID].PromoCode = "NOPE"
+	clock.advance(7 * 24 * time.Hour)
+	if n, err := store.GenerateSeriesOccurrences(ctx); n != 0 || !errors.Is(err, pricing.ErrUnknownPromoCode) {
+		t.Fatalf("expected the third occurrence to fail, got %d, %v", n, err)
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "SERIES_OCCURRENCE_FAILED" {
+		t.Fatalf("expected seeker to be told, got %+v", notes)
+	}
+	store.series[series.ID].PromoCode = ""
+	clock.advance(7 * 24 * time.Hour)
+	if n, err := store.GenerateSeriesOccurrences(ctx); n != 1 || err != nil {
+		t.Fatalf("expected the fourth occurrence to be created, got %d, %v", n, err)
+	}
+}
+
+func TestRequestDeadlineSweep(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	store.WithAdminPhones([]string{"+8801000000199"})
+	admin := seedUser(t, store, "+8801000000199")
+	seeker := seedUser(t, store, "+8801000000190")
+	helper := seedUser(t, store, "+8801000000191")
+
+	stale, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create stale request: %v", err)
+	}
+	if stale.ExpiresAt == nil || !stale.ExpiresAt.Equal(clock.now().Add(time.Hour)) {
+		t.Fatalf("expected expiry an hour out, got %v", stale.ExpiresAt)
+	}
+	active, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create active request: %v", err)
+	}
+	invites, _ := invitations(store, helper.ID, "INVITED")
+	var matchID string
+	for _, invite := range invites {
+		if invite.RequestID == active.ID {
+			matchID = invite.ID
+		}
+	}
+	if _, err := store.Accept(ctx, helper.ID, matchID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	clock.advance(matchWindow)
+	if n, _ := store.SweepRequestDeadlines(ctx); n != 1 {
+		t.Fatalf("expected one match deadline breach, got %d", n)
+	}
+	clock.advance(time.Hour)
+	if n, _ := store.SweepRequestDeadlines(ctx); n != 1 {
+		t.Fatalf("expected one expiry, got %d", n)
+	}
+	expired, _ := store.Get(ctx, seeker.ID, stale.ID)
+	if expired.Status != "EXPIRED" {
+		t.Fatalf("expected EXPIRED, got %s", expired.Status)
+	}
+	if invites, _ := invitations(store, helper.ID, "INVITED"); len(invites) != 0 {
+		t.Fatalf("expected invitations to expire, got %d", len(invites))
+	}
+	if _, err := store.Cancel(ctx, seeker.ID, stale.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState cancelling an expired request, got %v", err)
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "REQUEST_EXPIRED" {
+		t.Fatalf("expected seeker to be notified, got %+v", notes)
+	}
+
+	clock.advance(completionWindow)
+	if n, _ := store.SweepRequestDeadlines(ctx); n != 1 {
+		t.Fatalf("expected one completion breach, got %d", n)
+	}
+	if n, _ := store.SweepRequestDeadlines(ctx); n != 0 {
+		t.Fatalf("breaches should only be recorded once, got %d", n)
+	}
+	if notes, _ := store.ListNotifications(ctx, admin.ID); len(notes) == 0 || notes[0].Type != "SLA_BREACH" {
+		t.Fatalf("expected ops to be notified, got %+v", notes)
+	}
+
+	metrics, _ := store.SLAMetrics(ctx)
+	if metrics.ExpiredRequests != 1 || metrics.MatchDeadlineBreaches != 1 || metrics.CompletionDeadlineBreaches != 1 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if len(metrics.Escalations) != 1 || metrics.Escalations[0].MatchID != matchID {
+		t.Fatalf("expected the accepted match to be escalated, got %+v", metrics.Escalations)
+	}
+}
+
+func TestListRequestsPagination(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000200")
+
+	var created []string
+	for i := 0; i < 12; i++ {
+		category := "GENERAL_HELP"
+		if i%3 == 0 {
+			category = "MEDICAL_FIRST_AID"
+		}
+		req, err := store.Create(ctx, seeker.ID, testRequestInput(category))
+		if err != nil {
+			t.Fatalf("create request %d: %v", i, err)
+		}
+		created = append(created, req.ID)
+		if i%2 == 0 {
+			clock.advance(time.Minute)
+		}
+	}
+
+	var seen []string
+	cursor := ""
+	for {
+		page, err := store.List(ctx, seeker.ID, models.RequestListFilter{Limit: 5, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, req := range page.Data {
+			seen = append(seen, req.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != len(created) {
+		t.Fatalf("expected %d requests across pages, got %v", len(created), seen)
+	}
+	for i, id := range seen {
+		if want := created[len(created)-1-i]; id != want {
+			t.Fatalf("expected newest first, position %d got %s want %s", i, id, want)
+		}
+	}
+
+	asc, _ := store.List(ctx, seeker.ID, models.RequestListFilter{Order: "asc", Limit: 2})
+	if asc.Data[0].ID != created[0] || asc.Data[1].ID != created[1] {
+		t.Fatalf("unexpected ascending order: %s, %s", asc.Data[0].ID, asc.Data[1].ID)
+	}
+	if _, err := store.List(ctx, seeker.ID, models.RequestListFilter{Cursor: asc.NextCursor}); !errors.Is(err, services.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor reusing an ascending cursor, got %v", err)
+	}
+	if _, err := store.List(ctx, seeker.ID, models.RequestListFilter{Cursor: "not-a-cursor"}); !errors.Is(err, services.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	store.Cancel(ctx, seeker.ID, created[0], models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	store.Cancel(ctx, seeker.ID, created[1], models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	medical, _ := store.List(ctx, seeker.ID, models.RequestListFilter{Category: "MEDICAL_FIRST_AID", Status: "SUBMITTED, cancelled"})
+	if len(medical.Data) != 4 {
+		t.Fatalf("expected 4 medical requests, got %d", len(medical.Data))
+	}
+	cancelled, _ := store.List(ctx, seeker.ID, models.RequestListFilter{Status: "CANCELLED"})
+	if len(cancelled.Data) != 2 {
+		t.Fatalf("expected 2 cancelled requests, got %d", len(cancelled.Data))
+	}
+
+	from := time.Date(2025, 2, 17, 9, 2, 0, 0, time.UTC)
+	to := from.Add(2 * time.Minute)
+	ranged, _ := store.List(ctx, seeker.ID, models.RequestListFilter{From: &from, To: &to})
+	if len(ranged.Data) != 4 || ranged.NextCursor != "" {
+		t.Fatalf("expected 4 requests in range, got %d", len(ranged.Data))
+	}
+	if empty, _ := store.List(ctx, seeker.ID, models.RequestListFilter{Type: "PLANNED"}); empty.Data == nil || len(empty.Data) != 0 {
+		t.Fatalf("expected an empty, non-nil page, got %+v", empty)
+	}
+}
+
+func TestEscrowLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000210")
+	helper := seedUser(t, store, "+8801000000211")
+	other := seedUser(t, store, "+8801000000212")
+
+	confirmed, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	match := acceptRequest(t, store, helper.ID, confirmed.ID)
+	payment, err := store.GetRequestPayment(ctx, seeker.ID, confirmed.ID)
+	if err != nil {
+		t.Fatalf("get payment: %v", err)
+	}
+	if payment.Status != "HELD" || len(payment.Timeline) != 3 || payment.Amount != confirmed.Pricing.EstimatedAmount {
+		t.Fatalf("expected funds held in escrow, got %+v", payment)
+	}
+	if want, _ := money.Sum("BDT", confirmed.Pricing.EstimatedAmount, confirmed.Pricing.Subsidy, confirmed.Pricing.PlatformFee.Neg()); payment.HelperEarnings != want {
+		t.Fatalf("expected helper earnings %v, got %v", want, payment.HelperEarnings)
+	}
+	if _, err := store.GetRequestPayment(ctx, other.ID, confirmed.ID); err == nil {
+		t.Fatalf("expected other users not to see the payment")
+	}
+	if _, err := store.ConfirmCompletion(ctx, seeker.ID, match.ID, models.CompleteSessionInput{Confirmation: "SUCCESS"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState confirming an unfinished job, got %v", err)
+	}
+	store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+	released, err := store.ConfirmCompletion(ctx, seeker.ID, match.ID, models.CompleteSessionInput{Confirmation: "SUCCESS"})
+	if err != nil || released.Status != "RELEASED" {
+		t.Fatalf("expected release on confirmation, got %+v, %v", released, err)
+	}
+	if _, err := store.ConfirmCompletion(ctx, seeker.ID, match.ID, models.CompleteSessionInput{Confirmation: "FAILED", Reason: "late"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState disputing a released payment, got %v", err)
+	}
+	if notes, _ := store.ListNotifications(ctx, helper.ID); len(notes) == 0 || notes[0].Type != "PAYMENT_RELEASED" {
+		t.Fatalf("expected helper to be notified, got %+v", notes)
+	}
+
+	// Cancelling after the grace period keeps the fee and refunds the rest.
+	cancelled, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	acceptRequest(t, store, helper.ID, cancelled.ID)
+	clock.advance(10 * time.Minute)
+	result, _ := store.Cancel(ctx, seeker.ID, cancelled.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	refunded, _ := store.GetRequestPayment(ctx, seeker.ID, cancelled.ID)
+	fee := result.Cancellation.PenaltyAmount
+	if kept, _ := refunded.Amount.Sub(fee); refunded.Status != "REFUNDED" || fee.IsZero() || refunded.PenaltyAmount != fee || refunded.RefundedAmount != kept {
+		t.Fatalf("expected partial refund keeping %v, got %+v", fee, refunded)
+	}
+
+	// A withdrawal refunds that helper's payment; the next acceptance opens a new one.
+	rematched, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	first := acceptRequest(t, store, helper.ID, rematched.ID)
+	store.Withdraw(ctx, helper.ID, first.ID, models.WithdrawMatchInput{Reason: "EMERGENCY"})
+	second := acceptRequest(t, store, other.ID, rematched.ID)
+	latest, _ := store.GetRequestPayment(ctx, other.ID, rematched.ID)
+	if latest.HelperID != other.ID || latest.Status != "HELD" {
+		t.Fatalf("expected a new held payment for the second helper, got %+v", latest)
+	}
+	if withdrawn := store.paymentForMatchLocked(first.ID); withdrawn.Status != "REFUNDED" || withdrawn.RefundedAmount != withdrawn.Amount {
+		t.Fatalf("expected withdrawn helper's payment refunded, got %+v", withdrawn)
+	}
+
+	// Unfinished jobs are refunded once escrow expires; completed ones wait
+	// for the seeker until the confirmation window closes.
+	unconfirmed, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	done := acceptRequest(t, store, helper.ID, unconfirmed.ID)
+	store.UpdateStatus(ctx, helper.ID, done.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+	if n, _ := store.SweepEscrow(ctx); n != 0 {
+		t.Fatalf("expected nothing to settle before expiry, got %d", n)
+	}
+	clock.advance(completionWindow + escrowGrace)
+	if n, err := store.SweepEscrow(ctx); n != 1 || err != nil {
+		t.Fatalf("expected one settlement, got %d, %v", n, err)
+	}
+	if p, _ := store.GetRequestPayment(ctx, seeker.ID, rematched.ID); p.Status != "REFUNDED" || p.RefundedAmount != p.Amount {
+		t.Fatalf("expected unfinished job refunded, got %+v", p)
+	}
+	if got, _ := store.Get(ctx, seeker.ID, rematched.ID); got.Status != "EXPIRED" {
+		t.Fatalf("expected the unpaid request closed, got %s", got.Status)
+	}
+	if _, err := store.UpdateStatus(ctx, other.ID, second.ID, models.MatchStatusUpdate{Status: "COMPLETED"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected the unpaid match closed, got %v", err)
+	}
+	if notes, _ := store.ListNotifications(ctx, other.ID); len(notes) == 0 || notes[0].Type != "ESCROW_EXPIRED" {
+		t.Fatalf("expected the helper to be told the job closed, got %+v", notes)
+	}
+	if p, _ := store.GetRequestPayment(ctx, seeker.ID, unconfirmed.ID); p.Status != "HELD" {
+		t.Fatalf("expected completed job held for the seeker, got %s", p.Status)
+	}
+	clock.advance(confirmationWindow)
+	if n, err := store.SweepEscrow(ctx); n != 1 || err != nil {
+		t.Fatalf("expected one settlement, got %d, %v", n, err)
+	}
+	if p, _ := store.GetRequestPayment(ctx, seeker.ID, unconfirmed.ID); p.Status != "RELEASED" {
+		t.Fatalf("expected unconfirmed job released, got %s", p.Status)
+	}
+}
+
+func TestMatchStatusTransitions(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000290")
+	first := seedUser(t, store, "+8801000000291")
+	second := seedUser(t, store, "+8801000000292")
+
+	req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	withdrawn := acceptRequest(t, store, first.ID, req.ID)
+	if _, err := store.UpdateStatus(ctx, first.ID, withdrawn.ID, models.MatchStatusUpdate{Status: "ARRIVED"}); err != nil {
+		t.Fatalf("expected a helper to skip EN_ROUTE, got %v", err)
+	}
+	for _, status := range []string{"ACCEPTED", "EN_ROUTE", "ARRIVED", "BLOCKED"} {
+		if _, err := store.UpdateStatus(ctx, first.ID, withdrawn.ID, models.MatchStatusUpdate{Status: status}); !errors.Is(err, services.ErrInvalidState) {
+			t.Fatalf("expected ErrInvalidState moving an arrived match to %s, got %v", status, err)
+		}
+	}
+	if _, err := store.Withdraw(ctx, first.ID, withdrawn.ID, models.WithdrawMatchInput{Reason: "EMERGENCY"}); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+
+	current := acceptRequest(t, store, second.ID, req.ID)
+	for _, status := range []string{"EN_ROUTE", "ARRIVED", "IN_PROGRESS", "COMPLETED"} {
+		if _, err := store.UpdateStatus(ctx, first.ID, withdrawn.ID, models.MatchStatusUpdate{Status: status}); !errors.Is(err, services.ErrInvalidState) {
+			t.Fatalf("expected ErrInvalidState moving a withdrawn match to %s, got %v", status, err)
+		}
+	}
+	if got, _ := store.Get(ctx, seeker.ID, req.ID); got.Status != "ACCEPTED" {
+		t.Fatalf("expected request to stay with the new helper, got %s", got.Status)
+	}
+
+	if _, err := store.UpdateStatus(ctx, second.ID, current.ID, models.MatchStatusUpdate{Status: "COMPLETED"}); err != nil {
+		t.Fatalf("expected an accepted match to go straight to COMPLETED, got %v", err)
+	}
+	if _, err := store.UpdateStatus(ctx, second.ID, current.ID, models.MatchStatusUpdate{Status: "EN_ROUTE"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState reopening a completed match, got %v", err)
+	}
+	if got, _ := store.Get(ctx, seeker.ID, req.ID); got.Status != "COMPLETED" {
+		t.Fatalf("expected request completed, got %s", got.Status)
+	}
+}
+
+func TestGatewayOutcomes(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	fake := gateway.NewFake([]byte("whsec"))
+	store.WithGateway(fake)
+	seeker := seedUser(t, store, "+8801000000220")
+	helper := seedUser(t, store, "+8801000000221")
+
+	deliver := func() {
+		t.Helper()
+		for _, webhook := range fake.Webhooks() {
+			event, err := fake.VerifyWebhook(webhook.Payload, webhook.Signature)
+			if err != nil {
+				t.Fatalf("verify webhook: %v", err)
+			}
+			if err := store.HandleGatewayEvent(ctx, event); err != nil {
+				t.Fatalf("handle %s: %v", event.Type, err)
+			}
+		}
+	}
+
+	declined, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ := invitations(store, helper.ID, "INVITED")
+	fake.Script(gateway.OpCreateIntent, gateway.Decline)
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); !errors.Is(err, services.ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+	}
+	if still, _ := invitations(store, helper.ID, "INVITED"); len(still) != 1 {
+		t.Fatalf("expected the invitation to stay open after a decline")
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "PAYMENT_FAILED" {
+		t.Fatalf("expected seeker to be told the payment failed, got %+v", notes)
+	}
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); err != nil {
+		t.Fatalf("accept after retry: %v", err)
+	}
+	held, _ := store.GetRequestPayment(ctx, seeker.ID, declined.ID)
+	if intent, _ := fake.Intent(held.GatewayRef); held.Status != "HELD" || intent.Status != gateway.IntentSucceeded {
+		t.Fatalf("expected captured escrow, got payment %s intent %s", held.Status, intent.Status)
+	}
+	deliver()
+
+	// Delayed authorization holds escrow once the webhook arrives, and
+	// duplicate webhooks are applied once.
+	delayed, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	fake.Script(gateway.OpCreateIntent, gateway.Delay)
+	fake.Script(gateway.OpCapture, gateway.DuplicateWebhook)
+	acceptRequest(t, store, helper.ID, delayed.ID)
+	if pending, _ := store.GetRequestPayment(ctx, seeker.ID, delayed.ID); pending.Status != "PENDING" {
+		t.Fatalf("expected payment pending until the webhook, got %s", pending.Status)
+	}
+	deliver()
+	deliver()
+	payment, _ := store.GetRequestPayment(ctx, seeker.ID, delayed.ID)
+	if payment.Status != "HELD" || len(payment.Timeline) != 3 {
+		t.Fatalf("expected held payment with one capture, got %+v", payment)
+	}
+
+	// A declined capture refunds the authorization and aborts the accept.
+	uncaptured, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ = invitations(store, helper.ID, "INVITED")
+	fake.Script(gateway.OpCapture, gateway.Decline)
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); !errors.Is(err, services.ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined for a declined capture, got %v", err)
+	}
+	if got, _ := store.Get(ctx, seeker.ID, uncaptured.ID); got.Status != "SUBMITTED" {
+		t.Fatalf("expected request to stay unmatched, got %s", got.Status)
+	}
+	if refunded, _ := store.GetRequestPayment(ctx, seeker.ID, uncaptured.ID); refunded.Status != "REFUNDED" {
+		t.Fatalf("expected authorization refunded, got %s", refunded.Status)
+	}
+	deliver()
+	acceptRequest(t, store, helper.ID, uncaptured.ID)
+
+	// A capture that fails for any other reason voids the authorization
+	// rather than leaving it behind on the still-open invitation.
+	unreachable, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ = invitations(store, helper.ID, "INVITED")
+	fake.Script(gateway.OpCapture, gateway.Unavailable)
+	if _, err := store.Accept(ctx, helper.ID, invites[0].ID); !errors.Is(err, gateway.ErrUnavailable) {
+		t.Fatalf("expected the capture error, got %v", err)
+	}
+	voided, _ := store.GetRequestPayment(ctx, seeker.ID, unreachable.ID)
+	if intent, _ := fake.Intent(voided.GatewayRef); voided.Status != "REFUNDED" || intent.Status != gateway.IntentRefunded {
+		t.Fatalf("expected the authorization voided, got payment %s intent %s", voided.Status, intent.Status)
+	}
+	deliver()
+	acceptRequest(t, store, helper.ID, unreachable.ID)
+
+	// A delayed decline fails the payment and reopens the invitation.
+	failing, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	fake.Script(gateway.OpCreateIntent, gateway.DelayedDecline)
+	failingMatch := acceptRequest(t, store, helper.ID, failing.ID)
+	deliver()
+	if failed, _ := store.GetRequestPayment(ctx, seeker.ID, failing.ID); failed.Status != "FAILED" {
+		t.Fatalf("expected failed payment, got %s", failed.Status)
+	}
+	if got, _ := store.Get(ctx, seeker.ID, failing.ID); got.Status != "SUBMITTED" {
+		t.Fatalf("expected request back in matching, got %s", got.Status)
+	}
+	if _, err := store.UpdateStatus(ctx, helper.ID, failingMatch.ID, models.MatchStatusUpdate{Status: "EN_ROUTE"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected unpaid match to be unaccepted, got %v", err)
+	}
+	acceptRequest(t, store, helper.ID, failing.ID)
+	if retried, _ := store.GetRequestPayment(ctx, seeker.ID, failing.ID); retried.Status != "HELD" {
+		t.Fatalf("expected a new payment held after accepting again, got %s", retried.Status)
+	}
+
+	// Refunds go back through the gateway.
+	store.Cancel(ctx, seeker.ID, declined.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	if refunded := fake.Refunded(held.GatewayRef); refunded != held.Amount.Amount() {
+		t.Fatalf("expected a full refund at the gateway, got %d", refunded)
+	}
+}
+
+func TestDeclinedAcceptRetry(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	fake := gateway.NewFake([]byte("whsec"))
+	store.WithGateway(fake)
+	seeker := seedUser(t, store, "+8801000000300")
+	helper := seedUser(t, store, "+8801000000301")
+
+	// retry declines the first accept and accepts again, leaving a FAILED
+	// payment on the match next to the one that went through.
+	retry := func() (*models.HelpRequest, *models.MatchSession) {
+		t.Helper()
+		req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+		invites, _ := invitations(store, helper.ID, "INVITED")
+		fake.Script(gateway.OpCreateIntent, gateway.Decline)
+		if _, err := store.Accept(ctx, helper.ID, invites[0].ID); !errors.Is(err, services.ErrPaymentDeclined) {
+			t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+		}
+		match, err := store.Accept(ctx, helper.ID, invites[0].ID)
+		if err != nil {
+			t.Fatalf("accept after retry: %v", err)
+		}
+		return req, match
+	}
+
+	_, confirmed := retry()
+	store.UpdateStatus(ctx, helper.ID, confirmed.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+	released, err := store.ConfirmCompletion(ctx, seeker.ID, confirmed.ID, models.CompleteSessionInput{Confirmation: "SUCCESS"})
+	if err != nil || released.Status != "RELEASED" {
+		t.Fatalf("expected the retried payment released, got %+v, %v", released, err)
+	}
+
+	withdrawnReq, withdrawn := retry()
+	held, _ := store.GetRequestPayment(ctx, seeker.ID, withdrawnReq.ID)
+	if _, err := store.Withdraw(ctx, helper.ID, withdrawn.ID, models.WithdrawMatchInput{Reason: "EMERGENCY"}); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if refunded, _ := store.GetRequestPayment(ctx, seeker.ID, withdrawnReq.ID); refunded.ID != held.ID || refunded.Status != "REFUNDED" {
+		t.Fatalf("expected the retried payment refunded, got %+v", refunded)
+	}
+	if got := fake.Refunded(held.GatewayRef); got != held.Amount.Amount() {
+		t.Fatalf("expected a full refund at the gateway, got %d", got)
+	}
+}
+
+// blockingGateway holds CreateIntent calls until release is closed.
+type blockingGateway struct {
+	gateway.Gateway
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (g *blockingGateway) CreateIntent(ctx context.Context, req gateway.IntentRequest) (*gateway.Intent, error) {
+	g.entered <- struct{}{}
+	<-g.release
+	return g.Gateway.CreateIntent(ctx, req)
+}
+
+func TestGatewayCallsRunWithoutStoreLock(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	blocking := &blockingGateway{
+		Gateway: gateway.NewFake([]byte("whsec")),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	store.WithGateway(blocking)
+	seeker := seedUser(t, store, "+8801000000302")
+	helper := seedUser(t, store, "+8801000000303")
+
+	req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	invites, _ := invitations(store, helper.ID, "INVITED")
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := store.Accept(ctx, helper.ID, invites[0].ID)
+		accepted <- err
+	}()
+	<-blocking.entered
+
+	// The store keeps serving while the charge is with the gateway, but the
+	// request cannot change under it.
+	served := make(chan error, 1)
+	go func() {
+		if _, err := store.Get(ctx, seeker.ID, req.ID); err != nil {
+			served <- err
+			return
+		}
+		_, err := store.Cancel(ctx, seeker.ID, req.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+		served <- err
+	}()
+	select {
+	case err := <-served:
+		if !errors.Is(err, services.ErrInvalidState) {
+			t.Fatalf("expected cancel during the charge to fail with ErrInvalidState, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("store blocked while the gateway call was in flight")
+	}
+
+	close(blocking.release)
+	if err := <-accepted; err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if got, _ := store.Get(ctx, seeker.ID, req.ID); got.Status != "ACCEPTED" {
+		t.Fatalf("expected request ACCEPTED, got %s", got.Status)
+	}
+	if _, err := store.Cancel(ctx, seeker.ID, req.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"}); err != nil {
+		t.Fatalf("cancel after the charge: %v", err)
+	}
+}
+
+func TestLedgerPostingsReconcile(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000230")
+	helper := seedUser(t, store, "+8801000000231")
+
+	released, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	match := acceptRequest(t, store, helper.ID, released.ID)
+	store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+	store.ConfirmCompletion(ctx, seeker.ID, match.ID, models.CompleteSessionInput{Confirmation: "SUCCESS"})
+	payment, _ := store.GetRequestPayment(ctx, seeker.ID, released.ID)
+
+	txs, _ := store.LedgerTransactions(ctx, payment.ID)
+	var kinds []string
+	for _, tx := range txs {
+		kinds = append(kinds, tx.Kind)
+	}
+	if len(kinds) != 3 || kinds[0] != "PAYMENT_AUTHORIZED" || kinds[1] != "PAYMENT_HELD" || kinds[2] != "PAYMENT_RELEASED" {
+		t.Fatalf("unexpected postings: %v", kinds)
+	}
+
+	cancelled, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	acceptRequest(t, store, helper.ID, cancelled.ID)
+	clock.advance(10 * time.Minute)
+	result, _ := store.Cancel(ctx, seeker.ID, cancelled.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"})
+	fee := result.Cancellation.PenaltyAmount.Amount()
+
+	disputed, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	disputedMatch := acceptRequest(t, store, helper.ID, disputed.ID)
+	store.UpdateStatus(ctx, helper.ID, disputedMatch.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+	store.ConfirmCompletion(ctx, seeker.ID, disputedMatch.ID, models.CompleteSessionInput{Confirmation: "FAILED", Reason: "not done"})
+
+	summary, _ := store.LedgerSummary(ctx)
+	if !summary.Balanced || summary.Totals["BDT"] != 0 {
+		t.Fatalf("ledger does not reconcile: %+v", summary)
+	}
+	balance := func(account string) int64 {
+		for _, b := range summary.Balances {
+			if b.Account == account {
+				return b.Balance
+			}
+		}
+		return 0
+	}
+	amount := payment.Amount.Amount()
+	if got, want := balance(ledger.HelperBalance(helper.ID)), payment.HelperEarnings.Amount()+fee; got != want {
+		t.Fatalf("helper balance %d, want %d", got, want)
+	}
+	if got := balance(ledger.AccountPlatformFees); got != payment.PlatformFee.Amount() {
+		t.Fatalf("platform fees %d, want %d", got, payment.PlatformFee.Amount())
+	}
+	if got := balance(ledger.AccountDisputed); got != amount {
+		t.Fatalf("disputed escrow %d, want %d", got, amount)
+	}
+	if got := balance(ledger.AccountEscrow) + balance(ledger.AccountAuthorized); got != 0 {
+		t.Fatalf("expected no funds left in escrow, got %d", got)
+	}
+	if got, want := balance(ledger.SeekerFunds(seeker.ID)), -(2*amount + fee); got != want {
+		t.Fatalf("seeker funds %d, want %d", got, want)
+	}
+}
+
+func TestHelperPayoutBatches(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	fake := gateway.NewFake([]byte("whsec"))
+	store.WithGateway(fake).WithPayoutPolicy(PayoutPolicy{
+		Interval: 7 * 24 * time.Hour,
+		Anchor:   time.Date(2025, 2, 17, 12, 0, 0, 0, time.UTC),
+		Minimum:  money.New(10000, "BDT"),
+	})
+	seeker := seedUser(t, store, "+8801000000240")
+	helper := seedUser(t, store, "+8801000000241")
+	reviewer := seedUser(t, store, "+8801000000242")
+
+	destination := models.PayoutDestinationInput{
+		Type:          "MOBILE_WALLET",
+		Provider:      "bkash",
+		AccountName:   "Helper",
+		AccountNumber: "01712345678",
+	}
+	if _, err := store.SetPayoutDestination(ctx, helper.ID, destination); !errors.Is(err, services.ErrHelperNotEligible) {
+		t.Fatalf("expected ErrHelperNotEligible before KYC, got %v", err)
+	}
+	doc, err := store.UploadKYC(ctx, helper.ID, models.KYCDocumentUpload{DocumentType: "NID", FileKey: uploadKYCFile(t, store, helper.ID)})
+	if err != nil {
+		t.Fatalf("upload kyc: %v", err)
+	}
+	if _, err := store.ReviewKYC(ctx, reviewer.ID, doc.ID, models.KYCReviewInput{Decision: "APPROVED"}); err != nil {
+		t.Fatalf("review kyc: %v", err)
+	}
+	dest, err := store.SetPayoutDestination(ctx, helper.ID, destination)
+	if err != nil {
+		t.Fatalf("set destination: %v", err)
+	}
+	if dest.MaskedAccount != "*******5678" || dest.Provider != "BKASH" {
+		t.Fatalf("unexpected destination: %+v", dest)
+	}
+
+	earn := func() money.Money {
+		t.Helper()
+		req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+		match := acceptRequest(t, store, helper.ID, req.ID)
+		store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+		payment, err := store.ConfirmCompletion(ctx, seeker.ID, match.ID, models.CompleteSessionInput{Confirmation: "SUCCESS"})
+		if err != nil {
+			t.Fatalf("confirm completion: %v", err)
+		}
+		return payment.HelperEarnings
+	}
+	runBatch := func(want int) {
+		t.Helper()
+		got, err := store.RunPayoutBatch(ctx)
+		if err != nil {
+			t.Fatalf("run payout batch: %v", err)
+		}
+		if got != want {
+			t.Fatalf("expected %d payouts, got %d", want, got)
+		}
+	}
+	balance := func() models.EarningsBalance {
+		t.Helper()
+		earnings, _ := store.Earnings(ctx, helper.ID)
+		if len(earnings.Balances) != 1 {
+			t.Fatalf("expected one currency balance, got %+v", earnings.Balances)
+		}
+		return earnings.Balances[0]
+	}
+
+	first := earn()
+	runBatch(0)
+	if got := balance(); got.Available != first || got.Lifetime != first {
+		t.Fatalf("unexpected balance before batch: %+v", got)
+	}
+
+	clock.advance(3 * time.Hour)
+	runBatch(1)
+	runBatch(0)
+	payouts, _ := store.ListPayouts(ctx, helper.ID)
+	if len(payouts) != 1 || payouts[0].Status != "PAID" || payouts[0].Amount != first || payouts[0].Receipt == nil {
+		t.Fatalf("expected a paid payout with a receipt, got %+v", payouts)
+	}
+	if got := balance(); !got.Available.IsZero() || got.PaidOut != first {
+		t.Fatalf("unexpected balance after payout: %+v", got)
+	}
+	fake.Webhooks()
+
+	second := earn()
+	fake.Script(gateway.OpPayout, gateway.Decline, gateway.DelayedDecline)
+	clock.advance(7 * 24 * time.Hour)
+	runBatch(1)
+	if got := balance(); got.Available != second {
+		t.Fatalf("declined payout should restore the balance: %+v", got)
+	}
+
+	clock.advance(7 * 24 * time.Hour)
+	runBatch(1)
+	if got := balance(); got.InTransit != second || !got.Available.IsZero() {
+		t.Fatalf("expected payout in transit: %+v", got)
+	}
+	for _, webhook := range fake.Webhooks() {
+		event, err := fake.VerifyWebhook(webhook.Payload, webhook.Signature)
+		if err != nil {
+			t.Fatalf("verify webhook: %v", err)
+		}
+		if err := store.HandleGatewayEvent(ctx, event); err != nil {
+			t.Fatalf("handle %s: %v", event.Type, err)
+		}
+	}
+	if got := balance(); got.Available != second || !got.InTransit.IsZero() || got.PaidOut != first {
+		t.Fatalf("delayed failure should restore the balance: %+v", got)
+	}
+
+	payouts, _ = store.ListPayouts(ctx, helper.ID)
+	if len(payouts) != 3 || payouts[0].Status != "FAILED" || payouts[1].Status != "FAILED" {
+		t.Fatalf("unexpected payout history: %+v", payouts)
+	}
+	if summary, _ := store.LedgerSummary(ctx); !summary.Balanced {
+		t.Fatalf("ledger does not reconcile: %+v", summary)
+	}
+}
+
+func TestSavedPaymentMethods(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	seeker := seedUser(t, store, "+8801000000250")
+	helper := seedUser(t, store, "+8801000000251")
+
+	var methods []*models.PaymentMethod
+	for _, token := range []string{"tok_visa_4242", "tok_bkash_01712345678", "tok_mastercard", "tok_nagad", "tok_amex"} {
+		method, err := store.AttachPaymentMethod(ctx, seeker.ID, models.AttachPaymentMethodInput{PaymentMethodID: token})
+		if err != nil {
+			t.Fatalf("attach %s: %v", token, err)
+		}
+		methods = append(methods, method)
+	}
+	if !methods[0].IsDefault || methods[1].IsDefault || methods[1].Last4 != "5678" {
+		t.Fatalf("unexpected methods: %+v %+v", methods[0], methods[1])
+	}
+	if _, err := store.AttachPaymentMethod(ctx, seeker.ID, models.AttachPaymentMethodInput{PaymentMethodID: "tok_visa"}); !errors.Is(err, services.ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if _, err := store.SetDefaultPaymentMethod(ctx, helper.ID, methods[1].ID); err == nil {
+		t.Fatalf("expected another user's method to be hidden")
+	}
+
+	req, err := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	if req.PaymentMethodID != methods[0].ID {
+		t.Fatalf("expected request to use the default method, got %q", req.PaymentMethodID)
+	}
+	acceptRequest(t, store, helper.ID, req.ID)
+	if err := store.RemovePaymentMethod(ctx, seeker.ID, methods[0].ID); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected method tied to a pending request to be kept, got %v", err)
+	}
+
+	if _, err := store.Cancel(ctx, seeker.ID, req.ID, models.CancelRequestInput{Reason: "CHANGED_PLANS"}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if err := store.RemovePaymentMethod(ctx, seeker.ID, methods[0].ID); err != nil {
+		t.Fatalf("remove settled method: %v", err)
+	}
+	remaining, _ := store.ListPaymentMethods(ctx, seeker.ID)
+	if len(remaining) != 4 || remaining[0].ID != methods[4].ID || !remaining[0].IsDefault {
+		t.Fatalf("expected newest method to become default, got %+v", remaining)
+	}
+
+	// A repeat is charged to the original's method, not the current default,
+	// and fails once that method has been removed.
+	input := testRequestInput("GENERAL_HELP")
+	input.PaymentMethodID = methods[2].ID
+	chosen, _ := store.Create(ctx, seeker.ID, input)
+	repeated, err := store.Repeat(ctx, seeker.ID, chosen.ID, models.RepeatRequestInput{})
+	if err != nil || repeated.PaymentMethodID != methods[2].ID {
+		t.Fatalf("expected the repeat to use the original's method, got %+v, %v", repeated, err)
+	}
+	if _, err := store.Repeat(ctx, seeker.ID, req.ID, models.RepeatRequestInput{}); !errors.Is(err, errPaymentMethodNotFound) {
+		t.Fatalf("expected a removed method to be rejected, got %v", err)
+	}
+}
+
+func TestDisputeResolution(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	fake := gateway.NewFake([]byte("whsec"))
+	store.WithGateway(fake).WithAdminPhones([]string{"+8801000000269"})
+	seeker := seedUser(t, store, "+8801000000260")
+	helper := seedUser(t, store, "+8801000000261")
+	stranger := seedUser(t, store, "+8801000000262")
+	admin := seedUser(t, store, "+8801000000269")
+
+	completedPayment := func() *models.Payment {
+		t.Helper()
+		req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+		match := acceptRequest(t, store, helper.ID, req.ID)
+		store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+		payment, err := store.GetRequestPayment(ctx, seeker.ID, req.ID)
+		if err != nil || payment.Status != "HELD" {
+			t.Fatalf("expected held payment, got %+v, %v", payment, err)
+		}
+		return payment
+	}
+
+	partial := completedPayment()
+	if _, err := store.GetTransaction(ctx, stranger.ID, partial.ID); err == nil {
+		t.Fatalf("expected transaction to be hidden from other users")
+	}
+	input := models.OpenDisputeInput{Reason: "POOR_QUALITY", Details: "Half the job was left undone"}
+	tx, err := store.OpenDispute(ctx, seeker.ID, partial.ID, input)
+	if err != nil {
+		t.Fatalf("open dispute: %v", err)
+	}
+	if tx.Status != "DISPUTED" || tx.Dispute == nil || tx.Dispute.Status != "OPEN" {
+		t.Fatalf("expected frozen escrow with an open dispute, got %+v", tx)
+	}
+	if _, err := store.OpenDispute(ctx, helper.ID, partial.ID, input); !errors.Is(err, services.ErrDisputeAlreadyOpen) {
+		t.Fatalf("expected ErrDisputeAlreadyOpen, got %v", err)
+	}
+	if _, err := store.SweepEscrow(ctx); err != nil {
+		t.Fatalf("sweep escrow: %v", err)
+	}
+
+	if _, err := store.PostDisputeMessage(ctx, helper.ID, partial.ID, models.DisputeMessageInput{Body: "I finished the agreed work"}); err != nil {
+		t.Fatalf("helper message: %v", err)
+	}
+	dispute, err := store.PostDisputeMessage(ctx, admin.ID, partial.ID, models.DisputeMessageInput{Body: "Reviewing the evidence"})
+	if err != nil {
+		t.Fatalf("ops message: %v", err)
+	}
+	if len(dispute.Messages) != 2 || dispute.Messages[0].AuthorRole != "HELPER" || dispute.Messages[1].AuthorRole != "OPS" {
+		t.Fatalf("unexpected thread: %+v", dispute.Messages)
+	}
+	if notes, _ := store.ListNotifications(ctx, seeker.ID); len(notes) == 0 || notes[0].Type != "DISPUTE_MESSAGE" {
+		t.Fatalf("expected seeker to be notified of the thread, got %+v", notes)
+	}
+
+	if _, err := store.ResolveDispute(ctx, admin.ID, partial.ID, models.ResolveDisputeInput{Outcome: "REFUND_PARTIAL"}); err == nil {
+		t.Fatalf("expected a partial refund without an amount to be rejected")
+	}
+	tooPrecise, _ := money.Parse("200.005", "")
+	if _, err := store.ResolveDispute(ctx, admin.ID, partial.ID, models.ResolveDisputeInput{Outcome: "REFUND_PARTIAL", RefundAmount: tooPrecise}); !errors.Is(err, money.ErrPrecision) {
+		t.Fatalf("expected ErrPrecision, got %v", err)
+	}
+	tx, err = store.ResolveDispute(ctx, admin.ID, partial.ID, models.ResolveDisputeInput{Outcome: "REFUND_PARTIAL", RefundAmount: money.New(20000, "BDT")})
+	if err != nil {
+		t.Fatalf("resolve partial: %v", err)
+	}
+	if tx.Status != "REFUNDED" || tx.Dispute.Status != "RESOLVED" || tx.Dispute.Resolution.RefundedAmount != money.New(20000, "BDT") ||
+		tx.Dispute.Resolution.HelperAmount != money.New(partial.Amount.Amount()-20000, "BDT") {
+		t.Fatalf("unexpected partial resolution: %+v %+v", tx.Payment, tx.Dispute.Resolution)
+	}
+	if got := fake.Refunded(partial.GatewayRef); got != 20000 {
+		t.Fatalf("expected 20000 refunded through the gateway, got %d", got)
+	}
+	if _, err := store.PostDisputeMessage(ctx, seeker.ID, partial.ID, models.DisputeMessageInput{Body: "Thanks"}); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected resolved dispute to be closed, got %v", err)
+	}
+
+	split := completedPayment()
+	if _, err := store.OpenDispute(ctx, helper.ID, split.ID, models.OpenDisputeInput{Reason: "OTHER"}); err != nil {
+		t.Fatalf("helper opens dispute: %v", err)
+	}
+	tx, err = store.ResolveDispute(ctx, admin.ID, split.ID, models.ResolveDisputeInput{Outcome: "SPLIT"})
+	if err != nil {
+		t.Fatalf("resolve split: %v", err)
+	}
+	if tx.RefundedAmount != split.Amount.Split(2)[0] {
+		t.Fatalf("expected half refunded, got %+v", tx.Payment)
+	}
+
+	released := completedPayment()
+	if _, err := store.OpenDispute(ctx, seeker.ID, released.ID, models.OpenDisputeInput{Reason: "HELPER_NO_SHOW"}); err != nil {
+		t.Fatalf("open dispute: %v", err)
+	}
+	tx, err = store.ResolveDispute(ctx, admin.ID, released.ID, models.ResolveDisputeInput{Outcome: "RELEASE"})
+	if err != nil || tx.Status != "RELEASED" {
+		t.Fatalf("expected release to helper, got %+v, %v", tx, err)
+	}
+
+	summary, _ := store.LedgerSummary(ctx)
+	if !summary.Balanced {
+		t.Fatalf("ledger does not reconcile: %+v", summary)
+	}
+	for _, b := range summary.Balances {
+		if b.Account == ledger.AccountDisputed && b.Balance != 0 {
+			t.Fatalf("expected no funds left frozen, got %d", b.Balance)
+		}
+	}
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	scope := models.IdempotencyScope{UserID: "u-1", Key: "k-1", Route: "POST /v1/requests"}
+
+	if record, err := store.BeginIdempotent(ctx, scope, "hash"); record != nil || err != nil {
+		t.Fatalf("expected fresh reservation, got %+v, %v", record, err)
+	}
+	if _, err := store.BeginIdempotent(ctx, scope, "hash"); !errors.Is(err, services.ErrIdempotencyInFlight) {
+		t.Fatalf("expected ErrIdempotencyInFlight, got %v", err)
+	}
+	store.CompleteIdempotent(ctx, scope, models.StoredResponse{Status: 201, Body: []byte(`{"id":"req-1"}`)})
+	record, err := store.BeginIdempotent(ctx, scope, "hash")
+	if err != nil || record == nil || record.Response.Status != 201 {
+		t.Fatalf("expected stored response, got %+v, %v", record, err)
+	}
+	if _, err := store.BeginIdempotent(ctx, scope, "other"); !errors.Is(err, services.ErrIdempotencyMismatch) {
+		t.Fatalf("expected ErrIdempotencyMismatch, got %v", err)
+	}
+
+	clock.advance(defaultIdempotencyTTL)
+	if removed, _ := store.SweepIdempotencyKeys(ctx); removed != 1 {
+		t.Fatalf("expected expired key to be swept, removed %d", removed)
+	}
+	if record, err := store.BeginIdempotent(ctx, scope, "other"); record != nil || err != nil {
+		t.Fatalf("expected expired key to be reusable, got %+v, %v", record, err)
+	}
+}
+
+func TestWebhookInboxRetriesAndDelivery(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	user := seedUser(t, store, "+8801000000270")
+	store.mu.Lock()
+	notification := store.notifyLocked(user.ID, "TEST", "Hello", "Body", nil)
+	store.mu.Unlock()
+
+	ingest := func(eventID, reference, form string) {
+		t.Helper()
+		if _, _, err := store.IngestWebhook(ctx, models.WebhookInput{Provider: "TWILIO", EventID: eventID, Reference: reference, Payload: []byte(form)}); err != nil {
+			t.Fatalf("ingest %s: %v", eventID, err)
+		}
+	}
+	ingest("SM1:delivered", notification.ID, "MessageSid=SM1&MessageStatus=delivered")
+	clock.advance(time.Second)
+	ingest("SM1:sent", notification.ID, "MessageSid=SM1&MessageStatus=sent")
+	ingest("SM2:failed", "ntf-404", "MessageSid=SM2&MessageStatus=failed")
+	if _, duplicate, _ := store.IngestWebhook(ctx, models.WebhookInput{Provider: "TWILIO", EventID: "SM1:sent"}); !duplicate {
+		t.Fatalf("expected redelivered event to be deduplicated")
+	}
+
+	if processed, err := store.ProcessWebhooks(ctx); processed != 2 || err == nil {
+		t.Fatalf("expected two events applied and one failure, got %d, %v", processed, err)
+	}
+	notes, _ := store.ListNotifications(ctx, user.ID)
+	if delivery := notes[0].Delivery; delivery == nil || delivery.Status != "delivered" || delivery.ProviderRef != "SM1" {
+		t.Fatalf("expected late 'sent' receipt to be ignored, got %+v", delivery)
+	}
+
+	for attempt := 2; attempt <= maxWebhookAttempts; attempt++ {
+		if processed, _ := store.ProcessWebhooks(ctx); processed != 0 {
+			t.Fatalf("retry ran before its backoff elapsed")
+		}
+		clock.advance(webhookRetryBase << (attempt - 2))
+		store.ProcessWebhooks(ctx)
+	}
+	dead, _ := store.ListWebhookEvents(ctx, models.WebhookListFilter{Status: "DEAD"})
+	if len(dead) != 1 || dead[0].Attempts != maxWebhookAttempts {
+		t.Fatalf("expected event to be dead after %d attempts, got %+v", maxWebhookAttempts, dead)
+	}
+
+	replayed, err := store.ReplayWebhookEvent(ctx, dead[0].ID)
+	if err != nil || replayed.Status != "RECEIVED" || replayed.Attempts != 0 {
+		t.Fatalf("replay: %+v, %v", replayed, err)
+	}
+	if _, err := store.ReplayWebhookEvent(ctx, dead[0].ID); !errors.Is(err, services.ErrInvalidState) {
+		t.Fatalf("expected queued event not to be replayed twice, got %v", err)
+	}
+}
+
+func TestFinancialReports(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore(t)
+	store.WithGateway(gateway.NewFake([]byte("whsec"))).
+		WithAdminPhones([]string{"+8801000000289"}).
+		WithPayoutPolicy(PayoutPolicy{Interval: 7 * 24 * time.Hour, Anchor: time.Date(2025, 2, 17, 12, 0, 0, 0, time.UTC), Minimum: money.New(10000, "BDT")})
+	seeker := seedUser(t, store, "+8801000000280")
+	helper := seedUser(t, store, "+8801000000281")
+	admin := seedUser(t, store, "+8801000000289")
+
+	doc, _ := store.UploadKYC(ctx, helper.ID, models.KYCDocumentUpload{DocumentType: "NID", FileKey: uploadKYCFile(t, store, helper.ID)})
+	if _, err := store.ReviewKYC(ctx, admin.ID, doc.ID, models.KYCReviewInput{Decision: "APPROVED"}); err != nil {
+		t.Fatalf("review kyc: %v", err)
+	}
+	if _, err := store.SetPayoutDestination(ctx, helper.ID, models.PayoutDestinationInput{
+		Type: "MOBILE_WALLET", Provider: "bkash", AccountName: "Helper", AccountNumber: "01712345678",
+	}); err != nil {
+		t.Fatalf("set destination: %v", err)
+	}
+
+	completedPayment := func() *models.Payment {
+		t.Helper()
+		req, _ := store.Create(ctx, seeker.ID, testRequestInput("GENERAL_HELP"))
+		match := acceptRequest(t, store, helper.ID, req.ID)
+		store.UpdateStatus(ctx, helper.ID, match.ID, models.MatchStatusUpdate{Status: "COMPLETED"})
+		payment, err := store.GetRequestPayment(ctx, seeker.ID, req.ID)
+		if err != nil {
+			t.Fatalf("get payment: %v", err)
+		}
+		return payment
+	}
+	released := completedPayment()
+	if _, err := store.ConfirmCompletion(ctx, seeker.ID, released.MatchID, models.CompleteSessionInput{Confirmation: "SUCCESS"}); err != nil {
+		t.Fatalf("confirm completion: %v", err)
+	}
+	disputed := completedPayment()
+	if _, err := store.OpenDispute(ctx, seeker.ID, disputed.ID, models.OpenDisputeInput{Reason: "POOR_QUALITY"}); err != nil {
+		t.Fatalf("open dispute: %v", err)
+	}
+	if _, err := store.ResolveDispute(ctx, admin.ID, disputed.ID, models.ResolveDisputeInput{Outcome: "REFUND_PARTIAL", RefundAmount: money.New(20000, "BDT")}); err != nil {
+		t.Fatalf("resolve dispute: %v", err)
+	}
+	store.RunPayoutBatch(ctx)
+	clock.advance(3 * time.Hour)
+	if paid, err := store.RunPayoutBatch(ctx); err != nil || paid != 1 {
+		t.Fatalf("expected one payout, got %d, %v", paid, err)
+	}
+	payouts, _ := store.ListPayouts(ctx, helper.ID)
+
+	day := models.ReportFilter{From: time.Date(2025, 2, 17, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 2, 18, 0, 0, 0, 0, time.UTC)}
+	report := func(kind string, filter models.ReportFilter) *models.Report {
+		t.Helper()
+		report, err := store.FinancialReport(ctx, kind, filter)
+		if err != nil {
+			t.Fatalf("%s report: %v", kind, err)
+		}
+		return report
+	}
+
+	if rows := report(reports.KindTransactions, day).Rows; len(rows) < 10 {
+		t.Fatalf("expected every ledger entry of the day, got %d rows", len(rows))
+	}
+	fees := report(reports.KindFees, day).Rows
+	if len(fees) != 1 || fees[0][2] != released.ID || fees[0][5] != released.PlatformFee.Decimal() {
+		t.Fatalf("expected the released payment's fee, got %v", fees)
+	}
+	refunds := report(reports.KindRefunds, day).Rows
+	if len(refunds) != 1 || refunds[0][1] != disputed.ID || refunds[0][7] != "200.00" || refunds[0][10] == "" {
+		t.Fatalf("expected the partial dispute refund, got %v", refunds)
+	}
+	disputes := report(reports.KindDisputes, day).Rows
+	if len(disputes) != 1 || disputes[0][2] != disputed.ID || disputes[0][9] != "REFUND_PARTIAL" {
+		t.Fatalf("expected the resolved dispute, got %v", disputes)
+	}
+	payoutRows := report(reports.KindPayouts, day).Rows
+	if len(payoutRows) != 1 || payoutRows[0][1] != payouts[0].ID || payoutRows[0][4] != "PAID" || payoutRows[0][10] == "" {
+		t.Fatalf("expected the paid payout with its receipt, got %v", payoutRows)
+	}
+
+	nextDay := models.ReportFilter{From: day.To, To: day.To.AddDate(0, 0, 1)}
+	if rows := report(reports.KindTransactions, nextDay).Rows; len(rows) != 0 {
+		t.Fatalf("expected no transactions the next day, got %v", rows)
+	}
+	if _, err := store.FinancialReport(ctx, "bonuses", day); !errors.Is(err, reports.ErrUnknownReport) {
+		t.Fatalf("expected ErrUnknownReport, got %v", err)
+	}
+
+	settlements := []models.SettlementRecord{
+		{Type: reports.TypeCharge, GatewayRef: released.GatewayRef, Amount: released.Amount.Amount(), Currency: released.Currency},
+		{Type: reports.TypeCharge, GatewayRef: disputed.GatewayRef, Amount: disputed.Amount.Amount(), Currency: disputed.Currency},
+		{Type: reports.TypeRefund, GatewayRef: disputed.GatewayRef, Amount: 10000, Currency: disputed.Currency},
+		{Type: reports.TypeCharge, GatewayRef: "pi_unknown", Amount: 100, Currency: "BDT"},
+	}
+	reconciliation, err := store.ReconciliationReport(ctx, day, settlements)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, row := range reconciliation.Rows {
+		statuses[row[0]+":"+row[1]] = row[7]
+	}
+	want := map[string]string{
+		"CHARGE:" + released.GatewayRef:   reports.StatusMatched,
+		"CHARGE:" + disputed.GatewayRef:   reports.StatusMatched,
+		"REFUND:" + disputed.GatewayRef:   reports.StatusAmountMismatch,
+		"PAYOUT:" + payouts[0].GatewayRef: reports.StatusMissingSettlement,
+		"CHARGE:pi_unknown":               reports.StatusMissingLedger,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("unexpected reconciliation rows: %v", reconciliation.Rows)
+	}
+	for key, status := range want {
+		if statuses[key] != status {
+			t.Fatalf("expected %s to be %s, got %v", key, status, reconciliation.Rows)
+		}
+	}
+}
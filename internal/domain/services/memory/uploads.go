@@ -0,0 +1,415 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/imaging"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/storage"
+)
+
+const (
+	uploadTicketTTL          = 15 * time.Minute
+	uploadPathPrefix         = "/v1/uploads/"
+	maxKYCUploadBytes        = 5 << 20
+	maxAttachmentUploadBytes = 8 << 20
+	maxRequestAttachments    = 5
+	thumbnailMaxSide         = 320
+	// orphanedUploadTTL is how long an upload may stay unattached before the
+	// cleanup job deletes it.
+	orphanedUploadTTL = 24 * time.Hour
+)
+
+var (
+	kycContentTypes        = []string{"application/pdf", "image/jpeg", "image/png"}
+	attachmentContentTypes = []string{"image/jpeg", "image/png"}
+)
+
+var (
+	errStorageNotConfigured = errors.New("object storage not configured")
+	errUploadNotFound       = errors.New("upload not found")
+	errAttachmentNotFound   = errors.New("attachment not found")
+)
+
+// ObjectStorage wires the blob stores used for uploaded files.
+type ObjectStorage struct {
+	// KYC holds identity documents and must encrypt at rest.
+	KYC storage.ObjectStore
+	// Attachments holds request photos and their thumbnails.
+	Attachments storage.ObjectStore
+	Signer      *storage.Signer
+}
+
+func (s *Store) WithObjectStorage(objects ObjectStorage) *Store {
+	s.objects = objects
+	return s
+}
+
+func (s *Store) IssueKYCUpload(_ context.Context, userID string) (*models.UploadTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errUserNotFound
+	}
+
+	return s.issueUploadLocked(userID, "KYC", maxKYCUploadBytes, kycContentTypes)
+}
+
+func (s *Store) IssueAttachmentUpload(_ context.Context, userID string) (*models.UploadTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errUserNotFound
+	}
+
+	return s.issueUploadLocked(userID, "ATTACHMENT", maxAttachmentUploadBytes, attachmentContentTypes)
+}
+
+// GetAttachment returns a request attachment, or its thumbnail, to the
+// requester or the helper matched to the request. Anyone else is told the
+// attachment does not exist.
+func (s *Store) GetAttachment(ctx context.Context, userID, key string, thumbnail bool) (*models.ObjectContent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ticket, ok := s.uploads[key]
+	if !ok || ticket.Purpose != "ATTACHMENT" || ticket.UploadedAt == nil {
+		return nil, errAttachmentNotFound
+	}
+
+	allowed := ticket.OwnerID == userID
+	if !allowed && ticket.AttachedTo != "" {
+		// Dispute evidence is attached to the request too, so the seeker
+		// can see the helper's and ops can see both.
+		helperID, matched := s.matchedHelperLocked(ticket.AttachedTo)
+		req, ok := s.requests[ticket.AttachedTo]
+		user := s.users[userID]
+		allowed = (matched && helperID == userID) ||
+			(ok && req.RequesterID == userID) ||
+			(user != nil && user.HasRole("ADMIN"))
+	}
+	if !allowed {
+		return nil, errAttachmentNotFound
+	}
+
+	objects, err := s.objectStoreLocked(ticket.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey, contentType := ticket.Key, ticket.ContentType
+	if thumbnail {
+		objectKey, contentType = ticket.ThumbnailKey, "image/jpeg"
+	}
+
+	data, err := objects.Get(ctx, objectKey)
+	if err != nil {
+		return nil, fmt.Errorf("load attachment: %w", err)
+	}
+
+	return &models.ObjectContent{ContentType: contentType, Data: data}, nil
+}
+
+// CleanupOrphanedUploads deletes uploads that were never attached to a
+// record within orphanedUploadTTL of being issued, along with their stored
+// objects.
+func (s *Store) CleanupOrphanedUploads(ctx context.Context) (int, error) {
+	orphans, removed, errs := s.collectOrphanedUploads()
+
+	// Objects are deleted without the lock; the keys stay busy meanwhile so
+	// the uploads cannot be claimed.
+	deleted := make([]error, len(orphans))
+	for i, orphan := range orphans {
+		deleted[i] = deleteUploadObjects(ctx, orphan.objects, orphan.ticket)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, orphan := range orphans {
+		key := orphan.ticket.Key
+		delete(s.busyUploads, key)
+		if deleted[i] != nil {
+			errs = append(errs, deleted[i])
+			continue
+		}
+		delete(s.uploads, key)
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+type orphanedUpload struct {
+	ticket  models.UploadTicket
+	objects storage.ObjectStore
+}
+
+// collectOrphanedUploads removes orphaned tickets that never received a file
+// and marks the ones with stored objects busy for deletion.
+func (s *Store) collectOrphanedUploads() ([]orphanedUpload, int, []error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.now().Add(-orphanedUploadTTL)
+	var orphans []orphanedUpload
+	removed := 0
+	var errs []error
+	for key, ticket := range s.uploads {
+		if ticket.ClaimedAt != nil || ticket.IssuedAt.After(cutoff) || s.busyUploads[key] {
+			continue
+		}
+		if ticket.UploadedAt == nil {
+			delete(s.uploads, key)
+			removed++
+			continue
+		}
+
+		objects, err := s.objectStoreLocked(ticket.Purpose)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.busyUploads[key] = true
+		orphans = append(orphans, orphanedUpload{ticket: *ticket, objects: objects})
+	}
+	return orphans, removed, errs
+}
+
+func deleteUploadObjects(ctx context.Context, objects storage.ObjectStore, ticket models.UploadTicket) error {
+	if err := objects.Delete(ctx, ticket.Key); err != nil {
+		return fmt.Errorf("delete %s: %w", ticket.Key, err)
+	}
+	if ticket.ThumbnailKey != "" {
+		if err := objects.Delete(ctx, ticket.ThumbnailKey); err != nil {
+			return fmt.Errorf("delete %s: %w", ticket.ThumbnailKey, err)
+		}
+	}
+	return nil
+}
+
+// UploadService implementation
+
+func (s *Store) ReceiveUpload(ctx context.Context, key string, sig models.UploadSignature, data []byte) (*models.UploadTicket, error) {
+	purpose, contentType, err := s.checkUpload(key, sig, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding an image is slow, so thumbnails are made without the lock.
+	var thumbnail []byte
+	if purpose == "ATTACHMENT" {
+		thumbnail, err = imaging.Thumbnail(data, thumbnailMaxSide)
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, fmt.Errorf("%w: %v", services.ErrPayloadTooLarge, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", services.ErrUnsupportedMediaType, err)
+		}
+	}
+
+	objects, err := s.reserveUpload(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects are written without the lock; the key stays busy meanwhile.
+	thumbnailKey := ""
+	err = objects.Put(ctx, key, data)
+	if err != nil {
+		err = fmt.Errorf("store upload: %w", err)
+	} else if thumbnail != nil {
+		thumbnailKey = key + ".thumb"
+		if err = objects.Put(ctx, thumbnailKey, thumbnail); err != nil {
+			err = fmt.Errorf("store thumbnail: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.busyUploads, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := s.uploads[key]
+	now := s.now()
+	ticket.ContentType = contentType
+	ticket.Size = int64(len(data))
+	ticket.ThumbnailKey = thumbnailKey
+	ticket.UploadedAt = &now
+
+	copyTicket := *ticket
+	return &copyTicket, nil
+}
+
+// checkUpload validates an upload against its ticket and returns the
+// ticket's purpose and the sniffed content type.
+func (s *Store) checkUpload(key string, sig models.UploadSignature, data []byte) (string, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.objects.Signer == nil {
+		return "", "", errStorageNotConfigured
+	}
+	if err := s.objects.Signer.Verify(http.MethodPut, key, sig.Expires, sig.Signature, s.now()); err != nil {
+		return "", "", fmt.Errorf("%w: %v", services.ErrSignatureInvalid, err)
+	}
+
+	ticket, ok := s.uploads[key]
+	if !ok {
+		return "", "", errUploadNotFound
+	}
+	if ticket.UploadedAt != nil {
+		return "", "", fmt.Errorf("upload already completed")
+	}
+
+	if len(data) == 0 {
+		return "", "", fmt.Errorf("upload body is empty")
+	}
+	if int64(len(data)) > ticket.MaxBytes {
+		return "", "", fmt.Errorf("%w: limit is %d bytes", services.ErrPayloadTooLarge, ticket.MaxBytes)
+	}
+
+	contentType, err := storage.SniffContentType(data, ticket.AllowedTypes)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: expected one of %s", services.ErrUnsupportedMediaType, strings.Join(ticket.AllowedTypes, ", "))
+	}
+	if _, err := s.objectStoreLocked(ticket.Purpose); err != nil {
+		return "", "", err
+	}
+	return ticket.Purpose, contentType, nil
+}
+
+func (s *Store) issueUploadLocked(ownerID, purpose string, maxBytes int64, allowed []string) (*models.UploadTicket, error) {
+	if s.objects.Signer == nil {
+		return nil, errStorageNotConfigured
+	}
+
+	key, err := newObjectKey(strings.ToLower(purpose), ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	expires := now.Add(uploadTicketTTL).Truncate(time.Second)
+	signature := s.objects.Signer.Sign(http.MethodPut, key, expires)
+
+	ticket := &models.UploadTicket{
+		Key:          key,
+		Purpose:      purpose,
+		OwnerID:      ownerID,
+		UploadURL:    fmt.Sprintf("%s%s?expires=%d&signature=%s", uploadPathPrefix, key, expires.Unix(), signature),
+		Method:       http.MethodPut,
+		ExpiresAt:    expires,
+		MaxBytes:     maxBytes,
+		AllowedTypes: append([]string{}, allowed...),
+		IssuedAt:     now,
+	}
+	s.uploads[key] = ticket
+
+	copyTicket := *ticket
+	return &copyTicket, nil
+}
+
+// reserveUpload marks a ticket still waiting for its file busy while the
+// file is stored.
+func (s *Store) reserveUpload(key string) (storage.ObjectStore, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, ok := s.uploads[key]
+	if !ok {
+		return nil, errUploadNotFound
+	}
+	if ticket.UploadedAt != nil {
+		return nil, fmt.Errorf("upload already completed")
+	}
+	if s.busyUploads[key] {
+		return nil, fmt.Errorf("%w: upload already in progress", services.ErrInvalidState)
+	}
+	objects, err := s.objectStoreLocked(ticket.Purpose)
+	if err != nil {
+		return nil, err
+	}
+	s.busyUploads[key] = true
+	return objects, nil
+}
+
+// claimUploadLocked marks an uploaded file as attached to the record
+// identified by attachedTo. Only the user the key was issued to may claim
+// it, and only once.
+func (s *Store) claimUploadLocked(ownerID, purpose, key, attachedTo string) (*models.UploadTicket, error) {
+	ticket, err := s.claimableUploadLocked(ownerID, purpose, key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	ticket.ClaimedAt = &now
+	ticket.AttachedTo = attachedTo
+	return ticket, nil
+}
+
+// checkClaimableLocked checks that every key can be claimed before any is,
+// so a bad or repeated key leaves all of them unclaimed.
+func (s *Store) checkClaimableLocked(ownerID, purpose string, keys []string) error {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return fmt.Errorf("file %q is listed more than once", key)
+		}
+		seen[key] = true
+		if _, err := s.claimableUploadLocked(ownerID, purpose, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) claimableUploadLocked(ownerID, purpose, key string) (*models.UploadTicket, error) {
+	ticket, ok := s.uploads[key]
+	if !ok || ticket.OwnerID != ownerID || ticket.Purpose != purpose {
+		return nil, fmt.Errorf("file key %q was not issued to this user", key)
+	}
+	if ticket.UploadedAt == nil {
+		return nil, fmt.Errorf("file %q has not been uploaded", key)
+	}
+	if s.busyUploads[key] {
+		return nil, fmt.Errorf("file %q is no longer available", key)
+	}
+	if ticket.ClaimedAt != nil {
+		return nil, fmt.Errorf("file %q is already in use", key)
+	}
+	return ticket, nil
+}
+
+func (s *Store) objectStoreLocked(purpose string) (storage.ObjectStore, error) {
+	var objects storage.ObjectStore
+	switch purpose {
+	case "KYC":
+		objects = s.objects.KYC
+	case "ATTACHMENT":
+		objects = s.objects.Attachments
+	}
+	if objects == nil {
+		return nil, errStorageNotConfigured
+	}
+	return objects, nil
+}
+
+func newObjectKey(prefix, ownerID string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate object key: %w", err)
+	}
+	return fmt.Sprintf("%s/%s/%s", prefix, ownerID, hex.EncodeToString(buf)), nil
+}
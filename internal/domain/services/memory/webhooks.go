@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/MuhibNayem/community-helper-app/internal/domain/models"
+	"github.com/MuhibNayem/community-helper-app/internal/domain/services"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/gateway"
+	"github.com/MuhibNayem/community-helper-app/internal/platform/twilio"
+)
+
+const (
+	// maxWebhookAttempts is how often an event is tried before it is DEAD.
+	maxWebhookAttempts = 6
+	// webhookRetryBase is the first retry delay; it doubles per attempt.
+	webhookRetryBase = time.Minute
+)
+
+var (
+	errWebhookNotFound     = errors.New("webhook event not found")
+	errNotificationMissing = errors.New("notification not found")
+)
+
+// IngestWebhook stores a verified webhook in the inbox for ProcessWebhooks
+// to apply. A provider event already in the inbox is returned as is with
+// duplicate set.
+func (s *Store) IngestWebhook(_ context.Context, input models.WebhookInput) (*models.WebhookEvent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if input.Provider == "" || input.EventID == "" {
+		return nil, false, errors.New("webhook provider and event ID are required")
+	}
+	dedupeKey := input.Provider + ":" + input.EventID
+	if id, ok := s.webhookKeys[dedupeKey]; ok {
+		copied := *s.webhooks[id]
+		return &copied, true, nil
+	}
+
+	now := s.now()
+	event := &models.WebhookEvent{
+		ID:            fmt.Sprintf("wh-%d", s.nextWebhookID),
+		Provider:      input.Provider,
+		EventID:       input.EventID,
+		Type:          input.Type,
+		Reference:     input.Reference,
+		Payload:       append([]byte(nil), input.Payload...),
+		Status:        "RECEIVED",
+		NextAttemptAt: now,
+		ReceivedAt:    now,
+	}
+	s.nextWebhookID++
+	s.webhooks[event.ID] = event
+	s.webhookKeys[dedupeKey] = event.ID
+
+	copied := *event
+	return &copied, false, nil
+}
+
+// ListWebhookEvents returns inbox events, newest first.
+func (s *Store) ListWebhookEvents(_ context.Context, filter models.WebhookListFilter) ([]models.WebhookEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := []models.WebhookEvent{}
+	for _, event := range s.webhooks {
+		if filter.Provider != "" && event.Provider != filter.Provider {
+			continue
+		}
+		if filter.Status != "" && event.Status != filter.Status {
+			continue
+		}
+		events = append(events, *event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return keyBefore(cursorKey{at: events[i].ReceivedAt, id: events[i].ID}, cursorKey{at: events[j].ReceivedAt, id: events[j].ID}, false)
+	})
+	return events, nil
+}
+
+// ReplayWebhookEvent queues a FAILED or DEAD event to be processed again
+// with a fresh set of attempts.
+func (s *Store) ReplayWebhookEvent(_ context.Context, id string) (*models.WebhookEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.webhooks[id]
+	if !ok {
+		return nil, errWebhookNotFound
+	}
+	if event.Status != "FAILED" && event.Status != "DEAD" {
+		return nil, fmt.Errorf("%w: webhook event is %s", services.ErrInvalidState, event.Status)
+	}
+	event.Status = "RECEIVED"
+	event.Attempts = 0
+	event.NextAttemptAt = s.now()
+
+	copied := *event
+	return &copied, nil
+}
+
+// ProcessWebhooks applies inbox events that are due, oldest first. Failed
+// events are retried with exponential backoff until maxWebhookAttempts.
+func (s *Store) ProcessWebhooks(ctx context.Context) (int, error) {
+	due := s.claimWebhooks()
+	results := make([]error, len(due))
+	for i, event := range due {
+		results[i] = s.applyWebhook(ctx, event)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	processed := 0
+	var errs []error
+	for i, event := range due {
+		if err := results[i]; err != nil {
+			event.LastError = err.Error()
+			event.Status = "FAILED"
+			if event.Attempts >= maxWebhookAttempts {
+				event.Status = "DEAD"
+			}
+			errs = append(errs, fmt.Errorf("webhook %s: %w", event.ID, err))
+			continue
+		}
+		event.Status = "PROCESSED"
+		event.LastError = ""
+		event.ProcessedAt = &now
+		processed++
+	}
+	return processed, errors.Join(errs...)
+}
+
+// claimWebhooks counts an attempt on each due event, oldest first, and
+// schedules its retry up front so a concurrent run does not pick it up while
+// it is being applied.
+func (s *Store) claimWebhooks() []*models.WebhookEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var due []*models.WebhookEvent
+	for _, event := range s.webhooks {
+		if (event.Status == "RECEIVED" || event.Status == "FAILED") && !now.Before(event.NextAttemptAt) {
+			due = append(due, event)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return keyBefore(cursorKey{at: due[i].ReceivedAt, id: due[i].ID}, cursorKey{at: due[j].ReceivedAt, id: due[j].ID}, true)
+	})
+	for _, event := range due {
+		event.Attempts++
+		event.NextAttemptAt = now.Add(webhookRetryBase << (event.Attempts - 1))
+	}
+	return due
+}
+
+// applyWebhook applies a claimed event. It is called without s.mu, since
+// payment events may call the gateway.
+func (s *Store) applyWebhook(ctx context.Context, event *models.WebhookEvent) error {
+	switch event.Provider {
+	case "STRIPE":
+		gatewayEvent, err := gateway.DecodeEvent(event.Payload)
+		if err != nil {
+			return err
+		}
+		return s.HandleGatewayEvent(ctx, gatewayEvent)
+	case "TWILIO":
+		params, err := url.ParseQuery(string(event.Payload))
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.applySMSStatusLocked(event.Reference, params)
+	}
+	return fmt.Errorf("unknown webhook provider %q", event.Provider)
+}
+
+// applySMSStatusLocked records a Twilio delivery receipt on the notification
+// the SMS was sent for. Receipts arriving after a later status are ignored.
+func (s *Store) applySMSStatusLocked(notificationID string, params url.Values) error {
+	sid := params.Get("MessageSid")
+	status := params.Get("MessageStatus")
+
+	var notification *models.Notification
+	for _, candidate := range s.notifications {
+		if candidate.ID == notificationID ||
+			(notificationID == "" && candidate.Delivery != nil && candidate.Delivery.ProviderRef == sid) {
+			notification = candidate
+			break
+		}
+	}
+	if notification == nil {
+		return errNotificationMissing
+	}
+
+	if delivery := notification.Delivery; delivery != nil && twilio.StatusRank(status) < twilio.StatusRank(delivery.Status) {
+		return nil
+	}
+	notification.Delivery = &models.NotificationDelivery{
+		Channel:     "SMS",
+		ProviderRef: sid,
+		Status:      status,
+		ErrorCode:   params.Get("ErrorCode"),
+		UpdatedAt:   s.now(),
+	}
+	return nil
+}
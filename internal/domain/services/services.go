@@ -18,22 +18,129 @@ type UserService interface {
 	GetCurrentUser(ctx context.Context, userID string) (*models.User, error)
 	UpdateProfile(ctx context.Context, userID string, update models.UserUpdate) (*models.User, error)
 	ToggleHelper(ctx context.Context, userID string, toggle models.HelperToggle) (*models.User, error)
+	PauseHelper(ctx context.Context, userID string, input models.HelperPauseInput) (*models.User, error)
+	ResumeHelper(ctx context.Context, userID string) (*models.User, error)
 	UpdateSkills(ctx context.Context, userID string, update models.SkillsUpdate) (*models.HelperProfile, error)
 	ManageAvailability(ctx context.Context, userID string, update models.AvailabilityUpdate) (*models.HelperProfile, error)
+	IssueKYCUpload(ctx context.Context, userID string) (*models.UploadTicket, error)
 	UploadKYC(ctx context.Context, userID string, upload models.KYCDocumentUpload) (*models.KYCDocument, error)
 }
 
+type AttachmentService interface {
+	IssueAttachmentUpload(ctx context.Context, userID string) (*models.UploadTicket, error)
+	GetAttachment(ctx context.Context, userID, key string, thumbnail bool) (*models.ObjectContent, error)
+}
+
+type UploadService interface {
+	ReceiveUpload(ctx context.Context, key string, sig models.UploadSignature, data []byte) (*models.UploadTicket, error)
+}
+
+type KYCReviewService interface {
+	ListKYCDocuments(ctx context.Context, status string) ([]models.KYCDocument, error)
+	ReviewKYC(ctx context.Context, reviewerID, documentID string, input models.KYCReviewInput) (*models.KYCDocument, error)
+}
+
+type OpsService interface {
+	SLAMetrics(ctx context.Context) (*models.SLAMetrics, error)
+}
+
+type NotificationService interface {
+	ListNotifications(ctx context.Context, userID string) ([]models.Notification, error)
+}
+
 type RequestService interface {
 	Create(ctx context.Context, userID string, input models.CreateHelpRequestInput) (*models.HelpRequest, error)
-	List(ctx context.Context, userID string, filter models.RequestListFilter) ([]models.HelpRequest, error)
+	List(ctx context.Context, userID string, filter models.RequestListFilter) (*models.RequestPage, error)
 	Get(ctx context.Context, userID, requestID string) (*models.HelpRequest, error)
+	Update(ctx context.Context, userID, requestID string, input models.UpdateHelpRequestInput) (*models.HelpRequest, error)
+	Submit(ctx context.Context, userID, requestID string) (*models.HelpRequest, error)
+	Repeat(ctx context.Context, userID, requestID string, input models.RepeatRequestInput) (*models.HelpRequest, error)
 	Cancel(ctx context.Context, userID, requestID string, input models.CancelRequestInput) (*models.HelpRequest, error)
 	RateHelper(ctx context.Context, userID, requestID string, rating models.RateRequest) error
 }
 
+type SeriesService interface {
+	CreateSeries(ctx context.Context, userID string, input models.CreateSeriesInput) (*models.RequestSeries, error)
+	ListSeries(ctx context.Context, userID string) ([]models.RequestSeries, error)
+	GetSeries(ctx context.Context, userID, seriesID string) (*models.RequestSeries, error)
+	SkipOccurrence(ctx context.Context, userID, seriesID string, input models.SkipOccurrenceInput) (*models.RequestSeries, error)
+	CancelSeries(ctx context.Context, userID, seriesID string, input models.CancelSeriesInput) (*models.RequestSeries, error)
+}
+
+type PlaceService interface {
+	ListPlaces(ctx context.Context, userID string) ([]models.SavedPlace, error)
+	CreatePlace(ctx context.Context, userID string, input models.SavedPlaceInput) (*models.SavedPlace, error)
+	UpdatePlace(ctx context.Context, userID, placeID string, input models.SavedPlaceInput) (*models.SavedPlace, error)
+	DeletePlace(ctx context.Context, userID, placeID string) error
+}
+
+type PricingService interface {
+	EstimatePrice(ctx context.Context, userID string, input models.CreateHelpRequestInput) (*models.Pricing, error)
+	CreateQuote(ctx context.Context, userID string, input models.CreateHelpRequestInput) (*models.PriceQuote, error)
+	ListPriceBooks(ctx context.Context) ([]models.PriceBook, error)
+	PublishPriceBook(ctx context.Context, book models.PriceBook) (*models.PriceBook, error)
+}
+
+type PaymentService interface {
+	GetRequestPayment(ctx context.Context, userID, requestID string) (*models.Payment, error)
+	ConfirmCompletion(ctx context.Context, seekerID, matchID string, input models.CompleteSessionInput) (*models.Payment, error)
+	AttachPaymentMethod(ctx context.Context, userID string, input models.AttachPaymentMethodInput) (*models.PaymentMethod, error)
+	ListPaymentMethods(ctx context.Context, userID string) ([]models.PaymentMethod, error)
+	SetDefaultPaymentMethod(ctx context.Context, userID, methodID string) (*models.PaymentMethod, error)
+	RemovePaymentMethod(ctx context.Context, userID, methodID string) error
+}
+
+// IdempotencyService remembers responses to requests sent with an
+// Idempotency-Key so retries replay them instead of repeating the request.
+type IdempotencyService interface {
+	// BeginIdempotent reserves scope for a request whose body hashes to
+	// requestHash. It returns the earlier record if the key was already
+	// used, ErrIdempotencyInFlight if that request has not finished and
+	// ErrIdempotencyMismatch if it had a different body.
+	BeginIdempotent(ctx context.Context, scope models.IdempotencyScope, requestHash string) (*models.IdempotencyRecord, error)
+	// CompleteIdempotent stores the response to replay for scope.
+	CompleteIdempotent(ctx context.Context, scope models.IdempotencyScope, response models.StoredResponse) error
+	// AbandonIdempotent releases scope so the request can be retried.
+	AbandonIdempotent(ctx context.Context, scope models.IdempotencyScope) error
+}
+
+type WebhookService interface {
+	IngestWebhook(ctx context.Context, input models.WebhookInput) (*models.WebhookEvent, bool, error)
+	ListWebhookEvents(ctx context.Context, filter models.WebhookListFilter) ([]models.WebhookEvent, error)
+	ReplayWebhookEvent(ctx context.Context, id string) (*models.WebhookEvent, error)
+}
+
+type TransactionService interface {
+	GetTransaction(ctx context.Context, userID, transactionID string) (*models.Transaction, error)
+	OpenDispute(ctx context.Context, userID, transactionID string, input models.OpenDisputeInput) (*models.Transaction, error)
+	PostDisputeMessage(ctx context.Context, userID, transactionID string, input models.DisputeMessageInput) (*models.Dispute, error)
+	ResolveDispute(ctx context.Context, adminID, transactionID string, input models.ResolveDisputeInput) (*models.Transaction, error)
+}
+
+type PayoutService interface {
+	GetPayoutDestination(ctx context.Context, helperID string) (*models.PayoutDestination, error)
+	SetPayoutDestination(ctx context.Context, helperID string, input models.PayoutDestinationInput) (*models.PayoutDestination, error)
+	ListPayouts(ctx context.Context, helperID string) ([]models.Payout, error)
+	GetPayout(ctx context.Context, helperID, payoutID string) (*models.Payout, error)
+	Earnings(ctx context.Context, helperID string) (*models.HelperEarnings, error)
+}
+
+type LedgerService interface {
+	LedgerSummary(ctx context.Context) (*models.LedgerSummary, error)
+	LedgerTransactions(ctx context.Context, reference string) ([]models.LedgerTransaction, error)
+}
+
+// ReportService builds finance's CSV exports for a date range.
+type ReportService interface {
+	FinancialReport(ctx context.Context, kind string, filter models.ReportFilter) (*models.Report, error)
+	ReconciliationReport(ctx context.Context, filter models.ReportFilter, settlements []models.SettlementRecord) (*models.Report, error)
+}
+
 type MatchService interface {
-	ListInvitations(ctx context.Context, helperID string, status string) ([]models.MatchSession, error)
+	ListInvitations(ctx context.Context, helperID string, filter models.InvitationListFilter) (*models.InvitationPage, error)
 	Accept(ctx context.Context, helperID, matchID string) (*models.MatchSession, error)
 	Decline(ctx context.Context, helperID, matchID string, input models.DeclineMatchInput) (*models.MatchSession, error)
 	UpdateStatus(ctx context.Context, helperID, matchID string, input models.MatchStatusUpdate) (*models.MatchSession, error)
+	Withdraw(ctx context.Context, helperID, matchID string, input models.WithdrawMatchInput) (*models.MatchSession, error)
+	CancelMatch(ctx context.Context, helperID, matchID string, input models.HelperCancelInput) (*models.HelpRequest, error)
 }
@@ -0,0 +1,389 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operation names a Gateway call whose outcome can be scripted on a Fake.
+type Operation string
+
+const (
+	OpCreateIntent Operation = "CREATE_INTENT"
+	OpCapture      Operation = "CAPTURE"
+	OpRefund       Operation = "REFUND"
+	OpPayout       Operation = "PAYOUT"
+	OpAttachMethod Operation = "ATTACH_METHOD"
+)
+
+// Outcome is a scripted result for the next call of an operation.
+type Outcome string
+
+const (
+	// Succeed completes the call synchronously and sends its webhook.
+	Succeed Outcome = "SUCCEED"
+	// Decline fails the call with ErrDeclined and sends a failure webhook.
+	Decline Outcome = "DECLINE"
+	// Delay answers PROCESSING (or PENDING); the result only arrives as a
+	// webhook.
+	Delay Outcome = "DELAY"
+	// DelayedDecline answers PROCESSING and later sends a failure webhook.
+	DelayedDecline Outcome = "DELAYED_DECLINE"
+	// DuplicateWebhook succeeds and sends the same webhook twice.
+	DuplicateWebhook Outcome = "DUPLICATE_WEBHOOK"
+	// Unavailable fails the call with ErrUnavailable and changes nothing,
+	// like a provider that cannot be reached.
+	Unavailable Outcome = "UNAVAILABLE"
+)
+
+// ErrUnavailable is returned for calls scripted as Unavailable.
+var ErrUnavailable = errors.New("gateway unavailable")
+
+// Webhook is a signed provider notification waiting to be delivered.
+type Webhook struct {
+	Payload   []byte
+	Signature string
+}
+
+// Fake is an in-process Gateway for development and tests. Calls succeed
+// unless an outcome was scripted for them, and every call queues the
+// webhook a real provider would send; tests deliver them with Webhooks.
+type Fake struct {
+	mu sync.Mutex
+
+	secret []byte
+	now    func() time.Time
+
+	nextID    int
+	scripts   map[Operation][]Outcome
+	intents   map[string]*Intent
+	refunded  map[string]int64
+	transfers map[string]*Transfer
+	methods   map[string]*PaymentMethod
+	webhooks  []Webhook
+}
+
+func NewFake(webhookSecret []byte) *Fake {
+	return &Fake{
+		secret:    webhookSecret,
+		now:       time.Now,
+		scripts:   make(map[Operation][]Outcome),
+		intents:   make(map[string]*Intent),
+		refunded:  make(map[string]int64),
+		transfers: make(map[string]*Transfer),
+		methods:   make(map[string]*PaymentMethod),
+	}
+}
+
+// Script queues outcomes for the next calls of op, in order.
+func (f *Fake) Script(op Operation, outcomes ...Outcome) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scripts[op] = append(f.scripts[op], outcomes...)
+}
+
+// Webhooks returns and clears the signed webhooks queued so far.
+func (f *Fake) Webhooks() []Webhook {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	webhooks := f.webhooks
+	f.webhooks = nil
+	return webhooks
+}
+
+// Intent returns the provider-side state of an intent.
+func (f *Fake) Intent(id string) (Intent, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	intent, ok := f.intents[id]
+	if !ok {
+		return Intent{}, false
+	}
+	return *intent, true
+}
+
+// Refunded returns the total refunded against an intent.
+func (f *Fake) Refunded(intentID string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.refunded[intentID]
+}
+
+// Transfers returns every payout sent through the fake.
+func (f *Fake) Transfers() []Transfer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	transfers := make([]Transfer, 0, len(f.transfers))
+	for _, transfer := range f.transfers {
+		transfers = append(transfers, *transfer)
+	}
+	return transfers
+}
+
+func (f *Fake) CreateIntent(_ context.Context, req IntentRequest) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Amount <= 0 || req.Currency == "" {
+		return nil, ErrInvalidAmount
+	}
+	if req.PaymentMethod != "" {
+		if method, ok := f.methods[req.PaymentMethod]; !ok || method.Customer != req.Customer {
+			return nil, fmt.Errorf("%w: unknown payment method", ErrDeclined)
+		}
+	}
+	outcome := f.outcome(OpCreateIntent)
+	if outcome == Unavailable {
+		return nil, ErrUnavailable
+	}
+	intent := &Intent{
+		ID:        f.newID("pi"),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+	}
+	f.intents[intent.ID] = intent
+
+	switch outcome {
+	case Decline, DelayedDecline:
+		intent.Status = IntentFailed
+		f.emit(EventIntentFailed, intent.ID, req.Reference, req.Amount, 1)
+		if outcome == Decline {
+			return nil, fmt.Errorf("%w: card declined", ErrDeclined)
+		}
+		return f.processing(intent), nil
+	default:
+		intent.Status = IntentRequiresCapture
+		f.emit(EventIntentAuthorized, intent.ID, req.Reference, req.Amount, copies(outcome))
+		if outcome == Delay {
+			return f.processing(intent), nil
+		}
+		copied := *intent
+		return &copied, nil
+	}
+}
+
+func (f *Fake) Capture(_ context.Context, intentID string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentRequiresCapture {
+		return nil, fmt.Errorf("intent %s is %s", intentID, intent.Status)
+	}
+
+	switch outcome := f.outcome(OpCapture); outcome {
+	case Unavailable:
+		return nil, ErrUnavailable
+	case Decline, DelayedDecline:
+		intent.Status = IntentFailed
+		f.emit(EventIntentFailed, intent.ID, intent.Reference, intent.Amount, 1)
+		if outcome == Decline {
+			return nil, fmt.Errorf("%w: capture failed", ErrDeclined)
+		}
+		return f.processing(intent), nil
+	default:
+		intent.Status = IntentSucceeded
+		f.emit(EventIntentCaptured, intent.ID, intent.Reference, intent.Amount, copies(outcome))
+		if outcome == Delay {
+			return f.processing(intent), nil
+		}
+		copied := *intent
+		return &copied, nil
+	}
+}
+
+func (f *Fake) Refund(_ context.Context, intentID string, amount int64) (*Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if amount < 0 || f.refunded[intentID]+amount > intent.Amount {
+		return nil, ErrInvalidAmount
+	}
+
+	outcome := f.outcome(OpRefund)
+	if outcome == Unavailable {
+		return nil, ErrUnavailable
+	}
+	if outcome == Decline || outcome == DelayedDecline {
+		return nil, fmt.Errorf("%w: refund rejected", ErrDeclined)
+	}
+	f.refunded[intentID] += amount
+	if f.refunded[intentID] == intent.Amount || intent.Status != IntentSucceeded {
+		intent.Status = IntentRefunded
+	}
+
+	refund := &Refund{ID: f.newID("re"), IntentID: intentID, Amount: amount, Status: IntentSucceeded}
+	f.emit(EventRefundSucceeded, intentID, intent.Reference, amount, copies(outcome))
+	if outcome == Delay {
+		refund.Status = IntentProcessing
+	}
+	return refund, nil
+}
+
+func (f *Fake) Payout(_ context.Context, req PayoutRequest) (*Transfer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Amount <= 0 || req.Currency == "" {
+		return nil, ErrInvalidAmount
+	}
+	if req.Destination == "" {
+		return nil, fmt.Errorf("%w: payout destination required", ErrDeclined)
+	}
+	outcome := f.outcome(OpPayout)
+	if outcome == Unavailable {
+		return nil, ErrUnavailable
+	}
+	transfer := &Transfer{
+		ID:        f.newID("tr"),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+	}
+	f.transfers[transfer.ID] = transfer
+
+	switch outcome {
+	case Decline, DelayedDecline:
+		transfer.Status = "FAILED"
+		f.emit(EventPayoutFailed, transfer.ID, req.Reference, req.Amount, 1)
+		if outcome == Decline {
+			return nil, fmt.Errorf("%w: payout rejected", ErrDeclined)
+		}
+		pending := *transfer
+		pending.Status = "PENDING"
+		return &pending, nil
+	default:
+		transfer.Status = "PAID"
+		f.emit(EventPayoutPaid, transfer.ID, req.Reference, req.Amount, copies(outcome))
+		copied := *transfer
+		if outcome == Delay {
+			copied.Status = "PENDING"
+		}
+		return &copied, nil
+	}
+}
+
+// fakeBrands are the token brands the fake accepts, by method type.
+var fakeBrands = map[string]string{
+	"visa":       "CARD",
+	"mastercard": "CARD",
+	"amex":       "CARD",
+	"bkash":      "MOBILE_WALLET",
+	"nagad":      "MOBILE_WALLET",
+	"rocket":     "MOBILE_WALLET",
+}
+
+// AttachPaymentMethod accepts tokens of the form "tok_<brand>" or
+// "tok_<brand>_<digits>", e.g. "tok_visa_4242" or "tok_bkash_01712345678".
+func (f *Fake) AttachPaymentMethod(_ context.Context, customer, token string) (*PaymentMethod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(token, "tok_"), "_")
+	methodType, ok := fakeBrands[parts[0]]
+	if !strings.HasPrefix(token, "tok_") || !ok || len(parts) > 2 {
+		return nil, fmt.Errorf("%w: unsupported token %q", ErrDeclined, token)
+	}
+	if f.outcome(OpAttachMethod) == Decline {
+		return nil, fmt.Errorf("%w: payment method rejected", ErrDeclined)
+	}
+
+	digits := "4242"
+	if len(parts) == 2 && len(parts[1]) >= 4 {
+		digits = parts[1]
+	}
+	method := &PaymentMethod{
+		ID:       f.newID("pm"),
+		Customer: customer,
+		Type:     methodType,
+		Brand:    strings.ToUpper(parts[0]),
+		Last4:    digits[len(digits)-4:],
+	}
+	if methodType == "CARD" {
+		method.ExpMonth = 12
+		method.ExpYear = f.now().Year() + 3
+	}
+	f.methods[method.ID] = method
+
+	copied := *method
+	return &copied, nil
+}
+
+func (f *Fake) DetachPaymentMethod(_ context.Context, methodID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.methods[methodID]; !ok {
+		return ErrMethodNotFound
+	}
+	delete(f.methods, methodID)
+	return nil
+}
+
+func (f *Fake) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if err := VerifySignature(f.secret, payload, signature, f.now()); err != nil {
+		return nil, err
+	}
+	return DecodeEvent(payload)
+}
+
+// outcome pops the next scripted outcome for op. Callers hold f.mu.
+func (f *Fake) outcome(op Operation) Outcome {
+	queue := f.scripts[op]
+	if len(queue) == 0 {
+		return Succeed
+	}
+	f.scripts[op] = queue[1:]
+	return queue[0]
+}
+
+// emit queues n identical signed webhooks. Callers hold f.mu.
+func (f *Fake) emit(eventType, objectID, reference string, amount int64, n int) {
+	now := f.now()
+	payload, err := json.Marshal(Event{
+		ID:        f.newID("evt"),
+		Type:      eventType,
+		ObjectID:  objectID,
+		Reference: reference,
+		Amount:    amount,
+		Created:   now,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("encode fake webhook: %v", err))
+	}
+	webhook := Webhook{Payload: payload, Signature: SignPayload(f.secret, payload, now)}
+	for i := 0; i < n; i++ {
+		f.webhooks = append(f.webhooks, webhook)
+	}
+}
+
+func (f *Fake) processing(intent *Intent) *Intent {
+	copied := *intent
+	copied.Status = IntentProcessing
+	return &copied
+}
+
+func (f *Fake) newID(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s_fake_%d", prefix, f.nextID)
+}
+
+func copies(outcome Outcome) int {
+	if outcome == DuplicateWebhook {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeScriptedOutcomes(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake([]byte("whsec"))
+	fake.Script(OpCreateIntent, Decline, Delay)
+
+	req := IntentRequest{Reference: "pay-1", Amount: 50000, Currency: "BDT"}
+	if _, err := fake.CreateIntent(ctx, req); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("expected scripted decline, got %v", err)
+	}
+	delayed, err := fake.CreateIntent(ctx, req)
+	if err != nil || delayed.Status != IntentProcessing {
+		t.Fatalf("expected processing intent, got %+v, %v", delayed, err)
+	}
+	normal, err := fake.CreateIntent(ctx, req)
+	if err != nil || normal.Status != IntentRequiresCapture {
+		t.Fatalf("expected unscripted call to succeed, got %+v, %v", normal, err)
+	}
+
+	var types []string
+	for _, webhook := range fake.Webhooks() {
+		event, err := fake.VerifyWebhook(webhook.Payload, webhook.Signature)
+		if err != nil {
+			t.Fatalf("verify webhook: %v", err)
+		}
+		types = append(types, event.Type)
+	}
+	want := []string{EventIntentFailed, EventIntentAuthorized, EventIntentAuthorized}
+	if len(types) != len(want) {
+		t.Fatalf("expected webhooks %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected webhooks %v, got %v", want, types)
+		}
+	}
+	if len(fake.Webhooks()) != 0 {
+		t.Fatalf("expected webhooks to be drained")
+	}
+
+	fake.Script(OpCapture, Unavailable)
+	if _, err := fake.Capture(ctx, normal.ID); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected scripted outage, got %v", err)
+	}
+	if intent, _ := fake.Intent(normal.ID); intent.Status != IntentRequiresCapture || len(fake.Webhooks()) != 0 {
+		t.Fatalf("expected an outage to change nothing, got %+v", intent)
+	}
+
+	if _, err := fake.Capture(ctx, delayed.ID); err != nil {
+		t.Fatalf("capture delayed intent: %v", err)
+	}
+	if _, err := fake.Refund(ctx, delayed.ID, 20000); err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	if _, err := fake.Refund(ctx, delayed.ID, 40000); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected over-refund to fail, got %v", err)
+	}
+	if fake.Refunded(delayed.ID) != 20000 {
+		t.Fatalf("expected 20000 refunded, got %d", fake.Refunded(delayed.ID))
+	}
+}
+
+func TestFakeDuplicateWebhook(t *testing.T) {
+	fake := NewFake([]byte("whsec"))
+	fake.Script(OpPayout, DuplicateWebhook)
+
+	if _, err := fake.Payout(context.Background(), PayoutRequest{Reference: "po-1", Destination: "bkash:017", Amount: 100, Currency: "BDT"}); err != nil {
+		t.Fatalf("payout: %v", err)
+	}
+	webhooks := fake.Webhooks()
+	if len(webhooks) != 2 {
+		t.Fatalf("expected the webhook twice, got %d", len(webhooks))
+	}
+	first, _ := fake.VerifyWebhook(webhooks[0].Payload, webhooks[0].Signature)
+	second, _ := fake.VerifyWebhook(webhooks[1].Payload, webhooks[1].Signature)
+	if first.ID != second.ID || first.Type != EventPayoutPaid {
+		t.Fatalf("expected identical payout.paid events, got %+v and %+v", first, second)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("whsec")
+	payload := []byte(`{"id":"evt_1","type":"intent.captured"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := SignPayload(secret, payload, now)
+
+	if err := VerifySignature(secret, payload, header, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := VerifySignature(secret, []byte(`{"id":"evt_2"}`), header, now); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected tampered payload to fail, got %v", err)
+	}
+	if err := VerifySignature([]byte("other"), payload, header, now); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected wrong secret to fail, got %v", err)
+	}
+	if err := VerifySignature(secret, payload, header, now.Add(SignatureTolerance+time.Second)); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected stale signature to fail, got %v", err)
+	}
+}
+
+func TestFakePaymentMethods(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake([]byte("whsec"))
+
+	if _, err := fake.AttachPaymentMethod(ctx, "u-1", "4242424242424242"); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("expected raw card number to be rejected, got %v", err)
+	}
+	method, err := fake.AttachPaymentMethod(ctx, "u-1", "tok_bkash_01712345678")
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if method.Type != "MOBILE_WALLET" || method.Brand != "BKASH" || method.Last4 != "5678" {
+		t.Fatalf("unexpected method: %+v", method)
+	}
+
+	req := IntentRequest{Reference: "pay-1", Customer: "u-2", PaymentMethod: method.ID, Amount: 50000, Currency: "BDT"}
+	if _, err := fake.CreateIntent(ctx, req); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("expected another customer's method to be declined, got %v", err)
+	}
+	req.Customer = "u-1"
+	if _, err := fake.CreateIntent(ctx, req); err != nil {
+		t.Fatalf("charge saved method: %v", err)
+	}
+
+	if err := fake.DetachPaymentMethod(ctx, method.ID); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+	if err := fake.DetachPaymentMethod(ctx, method.ID); !errors.Is(err, ErrMethodNotFound) {
+		t.Fatalf("expected ErrMethodNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package gateway abstracts the payment provider that moves real money:
+// card or wallet charges held for escrow, refunds, and payouts to helpers.
+// Amounts are integer minor units (paisa for BDT).
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDeclined         = errors.New("payment declined")
+	ErrIntentNotFound   = errors.New("payment intent not found")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrSignatureInvalid = errors.New("invalid webhook signature")
+	ErrMethodNotFound   = errors.New("payment method not found")
+)
+
+// Intent statuses.
+const (
+	// IntentProcessing means the provider has not decided yet; the outcome
+	// arrives later as a webhook.
+	IntentProcessing      = "PROCESSING"
+	IntentRequiresCapture = "REQUIRES_CAPTURE"
+	IntentSucceeded       = "SUCCEEDED"
+	IntentRefunded        = "REFUNDED"
+	IntentFailed          = "FAILED"
+)
+
+// Webhook event types.
+const (
+	EventIntentAuthorized = "intent.authorized"
+	EventIntentCaptured   = "intent.captured"
+	EventIntentFailed     = "intent.failed"
+	EventRefundSucceeded  = "refund.succeeded"
+	EventPayoutPaid       = "payout.paid"
+	EventPayoutFailed     = "payout.failed"
+)
+
+// Gateway is implemented by each payment provider.
+type Gateway interface {
+	// CreateIntent authorizes a charge without capturing it.
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// Capture takes authorized funds into the platform's escrow account.
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	// Refund returns amount of a charge to the payer, or voids it if it was
+	// never captured.
+	Refund(ctx context.Context, intentID string, amount int64) (*Refund, error)
+	// Payout transfers funds from the platform to a helper's destination.
+	Payout(ctx context.Context, req PayoutRequest) (*Transfer, error)
+	// AttachPaymentMethod saves a token created by the provider's client SDK
+	// to the customer and returns the stored method's masked details.
+	AttachPaymentMethod(ctx context.Context, customer, token string) (*PaymentMethod, error)
+	// DetachPaymentMethod removes a saved method so it can't be charged.
+	DetachPaymentMethod(ctx context.Context, methodID string) error
+	// VerifyWebhook checks a webhook's signature header and decodes it.
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+type IntentRequest struct {
+	// Reference is our payment ID, echoed back in webhooks.
+	Reference string
+	Customer  string
+	// PaymentMethod is a tokenized method; empty uses the customer's default.
+	PaymentMethod string
+	Amount        int64
+	Currency      string
+}
+
+// PaymentMethod is a card or wallet saved with the provider. Only masked
+// details ever leave the provider.
+type PaymentMethod struct {
+	ID       string
+	Customer string
+	// Type is CARD or MOBILE_WALLET.
+	Type     string
+	Brand    string
+	Last4    string
+	ExpMonth int
+	ExpYear  int
+}
+
+type Intent struct {
+	ID        string
+	Reference string
+	Status    string
+	Amount    int64
+	Currency  string
+}
+
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   int64
+	Status   string
+}
+
+type PayoutRequest struct {
+	Reference string
+	// Destination is a tokenized bank account or mobile wallet.
+	Destination string
+	Amount      int64
+	Currency    string
+}
+
+type Transfer struct {
+	ID        string
+	Reference string
+	Status    string
+	Amount    int64
+	Currency  string
+}
+
+// Event is a provider notification about an intent, refund or payout.
+// ObjectID is the ID of the intent or transfer it concerns.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	ObjectID  string    `json:"objectId"`
+	Reference string    `json:"reference,omitempty"`
+	Amount    int64     `json:"amount,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// SignatureTolerance bounds how old a signed webhook may be.
+const SignatureTolerance = 5 * time.Minute
+
+// SignPayload returns a "t=<unix>,v1=<hex hmac>" signature header for
+// payload, the scheme used by the fake gateway and Stripe.
+func SignPayload(secret, payload []byte, at time.Time) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(payloadMAC(secret, ts, payload))
+}
+
+// VerifySignature checks a header produced by SignPayload and rejects
+// signatures older than SignatureTolerance.
+func VerifySignature(secret, payload []byte, header string, now time.Time) error {
+	var ts string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrSignatureInvalid
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrSignatureInvalid
+	}
+	expected := payloadMAC(secret, ts, payload)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrSignatureInvalid
+}
+
+// DecodeEvent parses a verified webhook payload.
+func DecodeEvent(payload []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, errors.New("webhook event missing id or type")
+	}
+	return &event, nil
+}
+
+func payloadMAC(secret []byte, ts string, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts))
+	h.Write([]byte{'.'})
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestThumbnailScalesDown(t *testing.T) {
+	for _, tc := range []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{800, 600, 100, 75},
+		{300, 900, 33, 100},
+		{60, 40, 60, 40},
+	} {
+		data, err := Thumbnail(encodePNG(t, tc.width, tc.height), 100)
+		if err != nil {
+			t.Fatalf("thumbnail %dx%d: %v", tc.width, tc.height, err)
+		}
+		thumb, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("thumbnail is not a JPEG: %v", err)
+		}
+		if got := thumb.Bounds(); got.Dx() != tc.wantW || got.Dy() != tc.wantH {
+			t.Fatalf("thumbnail of %dx%d is %dx%d, want %dx%d", tc.width, tc.height, got.Dx(), got.Dy(), tc.wantW, tc.wantH)
+		}
+	}
+}
+
+func TestThumbnailRejectsBadImages(t *testing.T) {
+	if _, err := Thumbnail([]byte("not an image"), 100); err == nil {
+		t.Fatalf("expected garbage to be rejected")
+	}
+
+	// Rewrite the header of a tiny PNG to claim 100000x100000 pixels; only
+	// the header may be read.
+	data := encodePNG(t, 1, 1)
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:4], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:8], 100000)
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+	if _, err := Thumbnail(data, 100); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
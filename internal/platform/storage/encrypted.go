@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// Encrypted wraps another ObjectStore and seals every object with
+// AES-256-GCM before it is written. The object key is bound as additional
+// data so ciphertexts cannot be swapped between keys.
+type Encrypted struct {
+	inner ObjectStore
+	aead  cipher.AEAD
+}
+
+func NewEncrypted(inner ObjectStore, key []byte) (*Encrypted, error) {
+	if len(key) != 32 {
+		return nil, ErrEncryptionKeyMalformed
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init gcm: %w", err)
+	}
+
+	return &Encrypted{inner: inner, aead: aead}, nil
+}
+
+func (e *Encrypted) Put(ctx context.Context, key string, data []byte) error {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, data, []byte(key))
+	return e.inner.Put(ctx, key, sealed)
+}
+
+func (e *Encrypted) Get(ctx context.Context, key string) ([]byte, error) {
+	sealed, err := e.inner.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	size := e.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("decrypt %s: ciphertext too short", key)
+	}
+
+	data, err := e.aead.Open(nil, sealed[:size], sealed[size:], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (e *Encrypted) Delete(ctx context.Context, key string) error {
+	return e.inner.Delete(ctx, key)
+}
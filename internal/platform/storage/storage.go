@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrObjectNotFound         = errors.New("object not found")
+	ErrInvalidKey             = errors.New("invalid object key")
+	ErrContentTypeNotAllowed  = errors.New("content type not allowed")
+	ErrSignatureInvalid       = errors.New("invalid signature")
+	ErrSignatureExpired       = errors.New("signature expired")
+	ErrEncryptionKeyMalformed = errors.New("encryption key must be 32 bytes")
+)
+
+// ObjectStore persists opaque blobs under slash-separated keys.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// SniffContentType detects the media type of data from its leading bytes,
+// ignoring whatever the client claimed, and checks it against allowed.
+func SniffContentType(data []byte, allowed []string) (string, error) {
+	detected := http.DetectContentType(data)
+	if i := strings.IndexByte(detected, ';'); i >= 0 {
+		detected = detected[:i]
+	}
+
+	for _, contentType := range allowed {
+		if contentType == detected {
+			return detected, nil
+		}
+	}
+	return "", ErrContentTypeNotAllowed
+}
+
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
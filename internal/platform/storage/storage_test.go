@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+	encrypted, err := NewEncrypted(local, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("new encrypted: %v", err)
+	}
+
+	plaintext := []byte("%PDF-1.4 national id")
+	if err := encrypted.Put(ctx, "kyc/user-1/a", plaintext); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	raw, err := local.Get(ctx, "kyc/user-1/a")
+	if err != nil {
+		t.Fatalf("raw get: %v", err)
+	}
+	if bytes.Contains(raw, plaintext) {
+		t.Fatalf("object stored in plaintext")
+	}
+
+	got, err := encrypted.Get(ctx, "kyc/user-1/a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip mismatch: %q", got)
+	}
+
+	// Ciphertext moved under another key must not decrypt.
+	if err := local.Put(ctx, "kyc/user-2/b", raw); err != nil {
+		t.Fatalf("raw put: %v", err)
+	}
+	if _, err := encrypted.Get(ctx, "kyc/user-2/b"); err == nil {
+		t.Fatalf("expected decrypt failure for swapped ciphertext")
+	}
+
+	if _, err := local.Get(ctx, "../escape"); err != ErrInvalidKey {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+}
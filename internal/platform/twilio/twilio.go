@@ -0,0 +1,78 @@
+// Package twilio verifies Twilio webhook requests such as SMS delivery
+// status callbacks.
+package twilio
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SignatureHeader carries the request signature.
+const SignatureHeader = "X-Twilio-Signature"
+
+var ErrSignatureInvalid = errors.New("invalid twilio signature")
+
+// Message delivery statuses reported by status callbacks, in the order a
+// message moves through them. Delivered, undelivered and failed are final.
+const (
+	StatusQueued      = "queued"
+	StatusSending     = "sending"
+	StatusSent        = "sent"
+	StatusDelivered   = "delivered"
+	StatusUndelivered = "undelivered"
+	StatusFailed      = "failed"
+)
+
+// Sign computes the signature Twilio sends for a POST to fullURL with the
+// given form parameters: base64(HMAC-SHA1(authToken, URL + each key and
+// value, sorted by key)).
+func Sign(authToken, fullURL string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(fullURL)
+	for _, key := range keys {
+		for _, value := range params[key] {
+			b.WriteString(key)
+			b.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the X-Twilio-Signature of a request.
+func VerifySignature(authToken, fullURL string, params url.Values, signature string) error {
+	expected := Sign(authToken, fullURL, params)
+	if signature == "" || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// StatusRank orders delivery statuses so late callbacks for an earlier
+// status can be ignored. Unknown statuses rank lowest.
+func StatusRank(status string) int {
+	switch status {
+	case StatusQueued:
+		return 1
+	case StatusSending:
+		return 2
+	case StatusSent:
+		return 3
+	case StatusDelivered, StatusUndelivered, StatusFailed:
+		return 4
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package twilio
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	// Example from Twilio's webhook security documentation.
+	fullURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+	params := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	const token = "12345"
+
+	signature := Sign(token, fullURL, params)
+	if signature != "0/KCTR6DLpKmkAf8muzZqo1nDgQ=" {
+		t.Fatalf("unexpected signature %q", signature)
+	}
+	if err := VerifySignature(token, fullURL, params, signature); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	params.Set("Digits", "9999")
+	if err := VerifySignature(token, fullURL, params, signature); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected tampered params to fail, got %v", err)
+	}
+}